@@ -0,0 +1,60 @@
+package admin
+
+import (
+	"errors"
+	"strconv"
+
+	"com.github.gin-common/app/form"
+	"com.github.gin-common/app/model"
+	"com.github.gin-common/app/service"
+	"com.github.gin-common/common/controllers"
+	"com.github.gin-common/common/exceptions"
+	"com.github.gin-common/common/resp"
+	"github.com/gin-gonic/gin"
+)
+
+type AssignUserRolesController struct {
+	assignRolesForm *form.AssignRolesForm
+	roleService     service.RoleService
+	userService     service.UserService
+}
+
+func (controller *AssignUserRolesController) Init(assignRolesForm *form.AssignRolesForm, roleService service.RoleService, userService service.UserService) {
+	controller.assignRolesForm = assignRolesForm
+	controller.roleService = roleService
+	controller.userService = userService
+}
+
+func (controller *AssignUserRolesController) assignUserRoles(context *gin.Context) (data *resp.Response, err error) {
+	// 为用户分配角色
+	userID := context.Param("userID")
+	intUserID, e := strconv.Atoi(userID)
+	if e != nil {
+		err = exceptions.NewError(exceptions.BadRequest, exceptions.WithError(errors.New("传入正确的userID")))()
+		return
+	}
+	if e := context.ShouldBindJSON(controller.assignRolesForm); e != nil {
+		err = e
+		return
+	}
+	if _, err = controller.userService.GetUserInfoById(uint(intUserID)); err != nil {
+		return
+	}
+	err = controller.roleService.AssignRoles(uint(intUserID), controller.assignRolesForm.RoleIDs...)
+	if err != nil {
+		return
+	}
+	var roles []model.Role
+	roles, err = controller.roleService.GetUserRoles(uint(intUserID))
+	if err != nil {
+		return
+	}
+	data = controllers.Success(gin.H{
+		"roles": roles,
+	})
+	return
+}
+
+func (controller *AssignUserRolesController) DoRequest(context *gin.Context) (data *resp.Response, err error) {
+	return controller.assignUserRoles(context)
+}
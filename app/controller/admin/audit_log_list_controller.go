@@ -0,0 +1,29 @@
+package admin
+
+import (
+	"com.github.gin-common/app/service"
+	"com.github.gin-common/common/audit"
+	"com.github.gin-common/common/controllers"
+	"com.github.gin-common/common/resp"
+	"github.com/gin-gonic/gin"
+)
+
+type AuditLogListController struct {
+	auditService service.AuditService
+}
+
+func (controller *AuditLogListController) Init(auditService service.AuditService) {
+	controller.auditService = auditService
+}
+
+func (controller *AuditLogListController) DoRequest(context *gin.Context) (data *resp.Response, err error) {
+	// 按filter/sort/page参数查询审计日志
+	var logs []audit.AuditLog
+	var pagination resp.Pagination
+	logs, pagination, err = controller.auditService.ListAuditLogs(context.Request.URL.Query())
+	if err != nil {
+		return
+	}
+	data = controllers.Success(resp.PageData(logs, pagination))
+	return
+}
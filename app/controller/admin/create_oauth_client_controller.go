@@ -0,0 +1,51 @@
+package admin
+
+import (
+	"strings"
+
+	"com.github.gin-common/app/form"
+	"com.github.gin-common/app/model"
+	"com.github.gin-common/app/service"
+	"com.github.gin-common/common/controllers"
+	"com.github.gin-common/common/resp"
+	"github.com/gin-gonic/gin"
+)
+
+type CreateOAuthClientController struct {
+	createOAuthClientForm *form.CreateOAuthClientForm
+	oauthService          service.OAuthService
+}
+
+func (controller *CreateOAuthClientController) Init(createOAuthClientForm *form.CreateOAuthClientForm, oauthService service.OAuthService) {
+	controller.createOAuthClientForm = createOAuthClientForm
+	controller.oauthService = oauthService
+}
+
+func (controller *CreateOAuthClientController) createOAuthClient(context *gin.Context) (data *resp.Response, err error) {
+	// 注册OAuth客户端，client_secret仅在此返回一次
+	if e := context.ShouldBindJSON(controller.createOAuthClientForm); e != nil {
+		return nil, e
+	}
+	clientForm := controller.createOAuthClientForm
+	var client *model.OAuthClient
+	var secret string
+	client, secret, err = controller.oauthService.CreateClient(&model.OAuthClient{
+		Name:         clientForm.Name,
+		Type:         clientForm.Type,
+		RedirectURIs: strings.Join(clientForm.RedirectURIs, " "),
+		Scopes:       strings.Join(clientForm.Scopes, " "),
+		GrantTypes:   strings.Join(clientForm.GrantTypes, " "),
+	})
+	if err != nil {
+		return
+	}
+	data = controllers.Success(gin.H{
+		"client":        client,
+		"client_secret": secret,
+	})
+	return
+}
+
+func (controller *CreateOAuthClientController) DoRequest(context *gin.Context) (data *resp.Response, err error) {
+	return controller.createOAuthClient(context)
+}
@@ -0,0 +1,43 @@
+package admin
+
+import (
+	"com.github.gin-common/app/form"
+	"com.github.gin-common/app/model"
+	"com.github.gin-common/app/service"
+	"com.github.gin-common/common/controllers"
+	"com.github.gin-common/common/resp"
+	"github.com/gin-gonic/gin"
+)
+
+type CreateRoleController struct {
+	createRoleForm *form.CreateRoleForm
+	roleService    service.RoleService
+}
+
+func (controller *CreateRoleController) Init(createRoleForm *form.CreateRoleForm, roleService service.RoleService) {
+	controller.createRoleForm = createRoleForm
+	controller.roleService = roleService
+}
+
+func (controller *CreateRoleController) createRole(context *gin.Context) (data *resp.Response, err error) {
+	if e := context.ShouldBindJSON(controller.createRoleForm); e != nil {
+		err = e
+		return
+	}
+	var role *model.Role
+	role, err = controller.roleService.CreateRole(&model.Role{
+		Name:        controller.createRoleForm.Name,
+		Description: controller.createRoleForm.Description,
+	}, controller.createRoleForm.Permissions)
+	if err != nil {
+		return
+	}
+	data = controllers.Success(gin.H{
+		"role": role,
+	})
+	return
+}
+
+func (controller *CreateRoleController) DoRequest(context *gin.Context) (data *resp.Response, err error) {
+	return controller.createRole(context)
+}
@@ -0,0 +1,39 @@
+package admin
+
+import (
+	"errors"
+	"strconv"
+
+	"com.github.gin-common/app/service"
+	"com.github.gin-common/common/controllers"
+	"com.github.gin-common/common/exceptions"
+	"com.github.gin-common/common/resp"
+	"github.com/gin-gonic/gin"
+)
+
+type DeleteOAuthClientController struct {
+	oauthService service.OAuthService
+}
+
+func (controller *DeleteOAuthClientController) Init(oauthService service.OAuthService) {
+	controller.oauthService = oauthService
+}
+
+func (controller *DeleteOAuthClientController) deleteOAuthClient(context *gin.Context) (data *resp.Response, err error) {
+	// 删除OAuth客户端及用户对其的授权
+	id, e := strconv.Atoi(context.Param("clientID"))
+	if e != nil {
+		err = exceptions.NewError(exceptions.BadRequest, exceptions.WithError(errors.New("传入正确的clientID")))()
+		return
+	}
+	err = controller.oauthService.DeleteClient(uint(id))
+	if err != nil {
+		return
+	}
+	data = controllers.Success(gin.H{})
+	return
+}
+
+func (controller *DeleteOAuthClientController) DoRequest(context *gin.Context) (data *resp.Response, err error) {
+	return controller.deleteOAuthClient(context)
+}
@@ -0,0 +1,39 @@
+package admin
+
+import (
+	"errors"
+	"strconv"
+
+	"com.github.gin-common/app/service"
+	"com.github.gin-common/common/controllers"
+	"com.github.gin-common/common/exceptions"
+	"com.github.gin-common/common/resp"
+	"github.com/gin-gonic/gin"
+)
+
+type DeleteRoleController struct {
+	roleService service.RoleService
+}
+
+func (controller *DeleteRoleController) Init(roleService service.RoleService) {
+	controller.roleService = roleService
+}
+
+func (controller *DeleteRoleController) deleteRole(context *gin.Context) (data *resp.Response, err error) {
+	roleID := context.Param("roleID")
+	intRoleID, e := strconv.Atoi(roleID)
+	if e != nil {
+		err = exceptions.NewError(exceptions.BadRequest, exceptions.WithError(errors.New("传入正确的roleID")))()
+		return
+	}
+	err = controller.roleService.DeleteRole(uint(intRoleID))
+	if err != nil {
+		return
+	}
+	data = controllers.Success(gin.H{})
+	return
+}
+
+func (controller *DeleteRoleController) DoRequest(context *gin.Context) (data *resp.Response, err error) {
+	return controller.deleteRole(context)
+}
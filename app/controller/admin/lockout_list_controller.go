@@ -0,0 +1,43 @@
+package admin
+
+import (
+	"errors"
+	"strconv"
+
+	"com.github.gin-common/app/model"
+	"com.github.gin-common/app/service"
+	"com.github.gin-common/common/controllers"
+	"com.github.gin-common/common/exceptions"
+	"com.github.gin-common/common/resp"
+	"github.com/gin-gonic/gin"
+)
+
+type LockoutListController struct {
+	loginGuardService service.LoginGuardService
+}
+
+func (controller *LockoutListController) Init(loginGuardService service.LoginGuardService) {
+	controller.loginGuardService = loginGuardService
+}
+
+func (controller *LockoutListController) listLockouts(context *gin.Context) (data *resp.Response, err error) {
+	// 获取最近的登录锁定记录
+	limit, e := strconv.Atoi(context.DefaultQuery("limit", "50"))
+	if e != nil || limit <= 0 || limit > 500 {
+		err = exceptions.NewError(exceptions.BadRequest, exceptions.WithError(errors.New("limit取值范围为1-500")))()
+		return
+	}
+	var lockouts []model.LoginLockout
+	lockouts, err = controller.loginGuardService.ListLockouts(limit)
+	if err != nil {
+		return
+	}
+	data = controllers.Success(gin.H{
+		"lockouts": lockouts,
+	})
+	return
+}
+
+func (controller *LockoutListController) DoRequest(context *gin.Context) (data *resp.Response, err error) {
+	return controller.listLockouts(context)
+}
@@ -0,0 +1,34 @@
+package admin
+
+import (
+	"com.github.gin-common/app/model"
+	"com.github.gin-common/app/service"
+	"com.github.gin-common/common/controllers"
+	"com.github.gin-common/common/resp"
+	"github.com/gin-gonic/gin"
+)
+
+type OAuthClientListController struct {
+	oauthService service.OAuthService
+}
+
+func (controller *OAuthClientListController) Init(oauthService service.OAuthService) {
+	controller.oauthService = oauthService
+}
+
+func (controller *OAuthClientListController) oauthClientList(context *gin.Context) (data *resp.Response, err error) {
+	// 获取已注册的OAuth客户端
+	var clients []model.OAuthClient
+	clients, err = controller.oauthService.ListClients()
+	if err != nil {
+		return
+	}
+	data = controllers.Success(gin.H{
+		"clients": clients,
+	})
+	return
+}
+
+func (controller *OAuthClientListController) DoRequest(context *gin.Context) (data *resp.Response, err error) {
+	return controller.oauthClientList(context)
+}
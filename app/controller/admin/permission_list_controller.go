@@ -0,0 +1,36 @@
+package admin
+
+import (
+	"com.github.gin-common/app/model"
+	"com.github.gin-common/app/service"
+	"com.github.gin-common/common/controllers"
+	"com.github.gin-common/common/resp"
+	"com.github.gin-common/common/routers"
+	"github.com/gin-gonic/gin"
+)
+
+type PermissionListController struct {
+	roleService service.RoleService
+}
+
+func (controller *PermissionListController) Init(roleService service.RoleService) {
+	controller.roleService = roleService
+}
+
+func (controller *PermissionListController) listPermissions(context *gin.Context) (data *resp.Response, err error) {
+	// 获取已保存的权限及路由上声明的权限
+	var permissions []model.Permission
+	permissions, err = controller.roleService.ListPermissions()
+	if err != nil {
+		return
+	}
+	data = controllers.Success(gin.H{
+		"permissions": permissions,
+		"declared":    routers.DeclaredPermissions(),
+	})
+	return
+}
+
+func (controller *PermissionListController) DoRequest(context *gin.Context) (data *resp.Response, err error) {
+	return controller.listPermissions(context)
+}
@@ -0,0 +1,33 @@
+package admin
+
+import (
+	"com.github.gin-common/app/model"
+	"com.github.gin-common/app/service"
+	"com.github.gin-common/common/controllers"
+	"com.github.gin-common/common/resp"
+	"github.com/gin-gonic/gin"
+)
+
+type RoleListController struct {
+	roleService service.RoleService
+}
+
+func (controller *RoleListController) Init(roleService service.RoleService) {
+	controller.roleService = roleService
+}
+
+func (controller *RoleListController) listRoles(context *gin.Context) (data *resp.Response, err error) {
+	var roles []model.Role
+	roles, err = controller.roleService.ListRoles()
+	if err != nil {
+		return
+	}
+	data = controllers.Success(gin.H{
+		"roles": roles,
+	})
+	return
+}
+
+func (controller *RoleListController) DoRequest(context *gin.Context) (data *resp.Response, err error) {
+	return controller.listRoles(context)
+}
@@ -0,0 +1,44 @@
+package admin
+
+import (
+	"errors"
+	"strconv"
+
+	"com.github.gin-common/app/service"
+	"com.github.gin-common/common/controllers"
+	"com.github.gin-common/common/exceptions"
+	"com.github.gin-common/common/resp"
+	"github.com/gin-gonic/gin"
+)
+
+type UnassignUserRoleController struct {
+	roleService service.RoleService
+}
+
+func (controller *UnassignUserRoleController) Init(roleService service.RoleService) {
+	controller.roleService = roleService
+}
+
+func (controller *UnassignUserRoleController) unassignUserRole(context *gin.Context) (data *resp.Response, err error) {
+	// 取消用户的角色
+	intUserID, e := strconv.Atoi(context.Param("userID"))
+	if e != nil {
+		err = exceptions.NewError(exceptions.BadRequest, exceptions.WithError(errors.New("传入正确的userID")))()
+		return
+	}
+	intRoleID, e := strconv.Atoi(context.Param("roleID"))
+	if e != nil {
+		err = exceptions.NewError(exceptions.BadRequest, exceptions.WithError(errors.New("传入正确的roleID")))()
+		return
+	}
+	err = controller.roleService.UnassignRole(uint(intUserID), uint(intRoleID))
+	if err != nil {
+		return
+	}
+	data = controllers.Success(gin.H{})
+	return
+}
+
+func (controller *UnassignUserRoleController) DoRequest(context *gin.Context) (data *resp.Response, err error) {
+	return controller.unassignUserRole(context)
+}
@@ -0,0 +1,51 @@
+package admin
+
+import (
+	"com.github.gin-common/app/form"
+	"com.github.gin-common/app/middleware"
+	"com.github.gin-common/app/model"
+	"com.github.gin-common/app/service"
+	"com.github.gin-common/common/controllers"
+	"com.github.gin-common/common/exceptions"
+	"com.github.gin-common/common/resp"
+	"github.com/gin-gonic/gin"
+)
+
+type UnlockLoginController struct {
+	unlockLoginForm   *form.UnlockLoginForm
+	loginGuardService service.LoginGuardService
+}
+
+func (controller *UnlockLoginController) Init(unlockLoginForm *form.UnlockLoginForm, loginGuardService service.LoginGuardService) {
+	controller.unlockLoginForm = unlockLoginForm
+	controller.loginGuardService = loginGuardService
+}
+
+func (controller *UnlockLoginController) unlockLogin(context *gin.Context) (data *resp.Response, err error) {
+	// 解除用户名和/或IP的登录锁定
+	var userInfo map[string]interface{}
+
+	userInfo, err = middleware.GetAuthedUserInfo(context)
+	if err != nil {
+		return
+	}
+	user, ok := userInfo["user"].(*model.User)
+	if !ok || user == nil {
+		err = exceptions.GetDefinedErrors(exceptions.ServerError)
+		return
+	}
+	if e := context.ShouldBindJSON(controller.unlockLoginForm); e != nil {
+		err = e
+		return
+	}
+	err = controller.loginGuardService.Unlock(controller.unlockLoginForm.Username, controller.unlockLoginForm.IP, user.ID)
+	if err != nil {
+		return
+	}
+	data = controllers.Success(gin.H{})
+	return
+}
+
+func (controller *UnlockLoginController) DoRequest(context *gin.Context) (data *resp.Response, err error) {
+	return controller.unlockLogin(context)
+}
@@ -0,0 +1,50 @@
+package admin
+
+import (
+	"errors"
+	"strconv"
+
+	"com.github.gin-common/app/form"
+	"com.github.gin-common/app/model"
+	"com.github.gin-common/app/service"
+	"com.github.gin-common/common/controllers"
+	"com.github.gin-common/common/exceptions"
+	"com.github.gin-common/common/resp"
+	"github.com/gin-gonic/gin"
+)
+
+type UpdateRoleController struct {
+	updateRoleForm *form.UpdateRoleForm
+	roleService    service.RoleService
+}
+
+func (controller *UpdateRoleController) Init(updateRoleForm *form.UpdateRoleForm, roleService service.RoleService) {
+	controller.updateRoleForm = updateRoleForm
+	controller.roleService = roleService
+}
+
+func (controller *UpdateRoleController) updateRole(context *gin.Context) (data *resp.Response, err error) {
+	roleID := context.Param("roleID")
+	intRoleID, e := strconv.Atoi(roleID)
+	if e != nil {
+		err = exceptions.NewError(exceptions.BadRequest, exceptions.WithError(errors.New("传入正确的roleID")))()
+		return
+	}
+	if e := context.ShouldBindJSON(controller.updateRoleForm); e != nil {
+		err = e
+		return
+	}
+	var role *model.Role
+	role, err = controller.roleService.UpdateRole(uint(intRoleID), controller.updateRoleForm.Description, controller.updateRoleForm.Permissions)
+	if err != nil {
+		return
+	}
+	data = controllers.Success(gin.H{
+		"role": role,
+	})
+	return
+}
+
+func (controller *UpdateRoleController) DoRequest(context *gin.Context) (data *resp.Response, err error) {
+	return controller.updateRole(context)
+}
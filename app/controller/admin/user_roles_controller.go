@@ -0,0 +1,44 @@
+package admin
+
+import (
+	"errors"
+	"strconv"
+
+	"com.github.gin-common/app/model"
+	"com.github.gin-common/app/service"
+	"com.github.gin-common/common/controllers"
+	"com.github.gin-common/common/exceptions"
+	"com.github.gin-common/common/resp"
+	"github.com/gin-gonic/gin"
+)
+
+type UserRolesController struct {
+	roleService service.RoleService
+}
+
+func (controller *UserRolesController) Init(roleService service.RoleService) {
+	controller.roleService = roleService
+}
+
+func (controller *UserRolesController) userRoles(context *gin.Context) (data *resp.Response, err error) {
+	// 获取用户的角色
+	userID := context.Param("userID")
+	intUserID, e := strconv.Atoi(userID)
+	if e != nil {
+		err = exceptions.NewError(exceptions.BadRequest, exceptions.WithError(errors.New("传入正确的userID")))()
+		return
+	}
+	var roles []model.Role
+	roles, err = controller.roleService.GetUserRoles(uint(intUserID))
+	if err != nil {
+		return
+	}
+	data = controllers.Success(gin.H{
+		"roles": roles,
+	})
+	return
+}
+
+func (controller *UserRolesController) DoRequest(context *gin.Context) (data *resp.Response, err error) {
+	return controller.userRoles(context)
+}
@@ -0,0 +1,36 @@
+package admin
+
+import (
+	"com.github.gin-common/app/exception"
+	"com.github.gin-common/common/controllers"
+	"com.github.gin-common/common/exceptions"
+	"com.github.gin-common/common/jobs"
+	"com.github.gin-common/common/resp"
+	"github.com/gin-gonic/gin"
+)
+
+type WorkflowGraphController struct {
+}
+
+func (controller *WorkflowGraphController) workflowGraph(context *gin.Context) (data *resp.Response, err error) {
+	// 获取工作流DAG, format=dot时返回Graphviz DOT文本
+	w, ok := jobs.GetWorkflow(context.Param("name"))
+	if !ok {
+		err = exceptions.GetDefinedErrors(exception.WorkflowNotFound)
+		return
+	}
+	if context.DefaultQuery("format", "json") == "dot" {
+		data = controllers.Success(gin.H{
+			"dot": w.DOT(),
+		})
+		return
+	}
+	data = controllers.Success(gin.H{
+		"graph": w.Graph(),
+	})
+	return
+}
+
+func (controller *WorkflowGraphController) DoRequest(context *gin.Context) (data *resp.Response, err error) {
+	return controller.workflowGraph(context)
+}
@@ -0,0 +1,34 @@
+package admin
+
+import (
+	"com.github.gin-common/common/controllers"
+	"com.github.gin-common/common/jobs"
+	"com.github.gin-common/common/resp"
+	"github.com/gin-gonic/gin"
+)
+
+type WorkflowListController struct {
+}
+
+func (controller *WorkflowListController) listWorkflows(context *gin.Context) (data *resp.Response, err error) {
+	// 获取所有已注册的工作流
+	var workflows []gin.H
+	for _, w := range jobs.Workflows() {
+		item := gin.H{
+			"name": w.Name(),
+			"spec": w.Spec(),
+		}
+		if runs := w.Runs(); len(runs) > 0 {
+			item["last_run"] = runs[0]
+		}
+		workflows = append(workflows, item)
+	}
+	data = controllers.Success(gin.H{
+		"workflows": workflows,
+	})
+	return
+}
+
+func (controller *WorkflowListController) DoRequest(context *gin.Context) (data *resp.Response, err error) {
+	return controller.listWorkflows(context)
+}
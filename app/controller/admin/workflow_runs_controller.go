@@ -0,0 +1,41 @@
+package admin
+
+import (
+	"com.github.gin-common/app/exception"
+	"com.github.gin-common/common/controllers"
+	"com.github.gin-common/common/exceptions"
+	"com.github.gin-common/common/jobs"
+	"com.github.gin-common/common/resp"
+	"github.com/gin-gonic/gin"
+)
+
+type WorkflowRunsController struct {
+}
+
+func (controller *WorkflowRunsController) workflowRuns(context *gin.Context) (data *resp.Response, err error) {
+	// 获取工作流运行记录, 指定runID时只返回该次运行
+	w, ok := jobs.GetWorkflow(context.Param("name"))
+	if !ok {
+		err = exceptions.GetDefinedErrors(exception.WorkflowNotFound)
+		return
+	}
+	if runID := context.Param("runID"); runID != "" {
+		run, found := w.GetRun(runID)
+		if !found {
+			err = exceptions.GetDefinedErrors(exception.WorkflowRunNotFound)
+			return
+		}
+		data = controllers.Success(gin.H{
+			"run": run,
+		})
+		return
+	}
+	data = controllers.Success(gin.H{
+		"runs": w.Runs(),
+	})
+	return
+}
+
+func (controller *WorkflowRunsController) DoRequest(context *gin.Context) (data *resp.Response, err error) {
+	return controller.workflowRuns(context)
+}
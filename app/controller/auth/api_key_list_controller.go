@@ -0,0 +1,47 @@
+package auth
+
+import (
+	"com.github.gin-common/app/middleware"
+	"com.github.gin-common/app/model"
+	"com.github.gin-common/app/service"
+	"com.github.gin-common/common/controllers"
+	"com.github.gin-common/common/exceptions"
+	"com.github.gin-common/common/resp"
+	"github.com/gin-gonic/gin"
+)
+
+type ApiKeyListController struct {
+	apiKeyService service.ApiKeyService
+}
+
+func (controller *ApiKeyListController) Init(apiKeyService service.ApiKeyService) {
+	controller.apiKeyService = apiKeyService
+}
+
+func (controller *ApiKeyListController) listApiKeys(context *gin.Context) (data *resp.Response, err error) {
+	// 获取当前用户的API Key
+	var userInfo map[string]interface{}
+
+	userInfo, err = middleware.GetAuthedUserInfo(context)
+	if err != nil {
+		return
+	}
+	user, ok := userInfo["user"].(*model.User)
+	if !ok || user == nil {
+		err = exceptions.GetDefinedErrors(exceptions.ServerError)
+		return
+	}
+	var apiKeys []model.ApiKey
+	apiKeys, err = controller.apiKeyService.ListApiKeys(user.ID)
+	if err != nil {
+		return
+	}
+	data = controllers.Success(gin.H{
+		"api_keys": apiKeys,
+	})
+	return
+}
+
+func (controller *ApiKeyListController) DoRequest(context *gin.Context) (data *resp.Response, err error) {
+	return controller.listApiKeys(context)
+}
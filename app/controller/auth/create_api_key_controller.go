@@ -0,0 +1,68 @@
+package auth
+
+import (
+	"time"
+
+	"com.github.gin-common/app/exception"
+	"com.github.gin-common/app/form"
+	"com.github.gin-common/app/middleware"
+	"com.github.gin-common/app/model"
+	"com.github.gin-common/app/service"
+	"com.github.gin-common/common/controllers"
+	"com.github.gin-common/common/exceptions"
+	"com.github.gin-common/common/resp"
+	"github.com/gin-gonic/gin"
+)
+
+type CreateApiKeyController struct {
+	createApiKeyForm *form.CreateApiKeyForm
+	apiKeyService    service.ApiKeyService
+}
+
+func (controller *CreateApiKeyController) Init(createApiKeyForm *form.CreateApiKeyForm, apiKeyService service.ApiKeyService) {
+	controller.createApiKeyForm = createApiKeyForm
+	controller.apiKeyService = apiKeyService
+}
+
+func (controller *CreateApiKeyController) createApiKey(context *gin.Context) (data *resp.Response, err error) {
+	// 为当前用户创建API Key，不允许使用API Key创建新的API Key
+	var userInfo map[string]interface{}
+
+	userInfo, err = middleware.GetAuthedUserInfo(context)
+	if err != nil {
+		return
+	}
+	if middleware.GetAuthedApiKey(context) != nil {
+		err = exceptions.GetDefinedErrors(exception.ApiKeyNotAllowed)
+		return
+	}
+	user, ok := userInfo["user"].(*model.User)
+	if !ok || user == nil {
+		err = exceptions.GetDefinedErrors(exceptions.ServerError)
+		return
+	}
+	if e := context.ShouldBindJSON(controller.createApiKeyForm); e != nil {
+		err = e
+		return
+	}
+	if expiresAt := controller.createApiKeyForm.ExpiresAt; expiresAt != nil && expiresAt.Before(time.Now()) {
+		err = exceptions.GetDefinedErrors(exception.ApiKeyExpired)
+		return
+	}
+	var apiKey *model.ApiKey
+	var key string
+	apiKey, key, err = controller.apiKeyService.CreateApiKey(user.ID, controller.createApiKeyForm.Name,
+		controller.createApiKeyForm.Scopes, controller.createApiKeyForm.ExpiresAt)
+	if err != nil {
+		return
+	}
+	data = controllers.Success(gin.H{
+		"api_key": apiKey,
+		"key":     key,
+	})
+	return
+}
+
+func (controller *CreateApiKeyController) DoRequest(context *gin.Context) (data *resp.Response, err error) {
+	return controller.createApiKey(context)
+}
@@ -0,0 +1,56 @@
+package auth
+
+import (
+	"com.github.gin-common/app/exception"
+	"com.github.gin-common/app/form"
+	"com.github.gin-common/app/middleware"
+	"com.github.gin-common/app/model"
+	"com.github.gin-common/app/service"
+	"com.github.gin-common/common/controllers"
+	"com.github.gin-common/common/exceptions"
+	"com.github.gin-common/common/resp"
+	"github.com/gin-gonic/gin"
+)
+
+type DisableMFAController struct {
+	mfaCodeForm *form.MFACodeForm
+	mfaService  service.MFAService
+}
+
+func (controller *DisableMFAController) Init(mfaCodeForm *form.MFACodeForm, mfaService service.MFAService) {
+	controller.mfaCodeForm = mfaCodeForm
+	controller.mfaService = mfaService
+}
+
+func (controller *DisableMFAController) disableMFA(context *gin.Context) (data *resp.Response, err error) {
+	// 使用动态码或恢复码关闭两步验证
+	var userInfo map[string]interface{}
+
+	userInfo, err = middleware.GetAuthedUserInfo(context)
+	if err != nil {
+		return
+	}
+	if middleware.GetAuthedApiKey(context) != nil {
+		err = exceptions.GetDefinedErrors(exception.ApiKeyNotAllowed)
+		return
+	}
+	user, ok := userInfo["user"].(*model.User)
+	if !ok || user == nil {
+		err = exceptions.GetDefinedErrors(exceptions.ServerError)
+		return
+	}
+	if e := context.ShouldBindJSON(controller.mfaCodeForm); e != nil {
+		err = e
+		return
+	}
+	err = controller.mfaService.Disable(user.ID, controller.mfaCodeForm.Code)
+	if err != nil {
+		return
+	}
+	data = controllers.Success(gin.H{})
+	return
+}
+
+func (controller *DisableMFAController) DoRequest(context *gin.Context) (data *resp.Response, err error) {
+	return controller.disableMFA(context)
+}
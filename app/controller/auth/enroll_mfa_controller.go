@@ -0,0 +1,54 @@
+package auth
+
+import (
+	"com.github.gin-common/app/exception"
+	"com.github.gin-common/app/middleware"
+	"com.github.gin-common/app/model"
+	"com.github.gin-common/app/service"
+	"com.github.gin-common/common/controllers"
+	"com.github.gin-common/common/exceptions"
+	"com.github.gin-common/common/resp"
+	"github.com/gin-gonic/gin"
+)
+
+type EnrollMFAController struct {
+	mfaService service.MFAService
+}
+
+func (controller *EnrollMFAController) Init(mfaService service.MFAService) {
+	controller.mfaService = mfaService
+}
+
+func (controller *EnrollMFAController) enrollMFA(context *gin.Context) (data *resp.Response, err error) {
+	// 生成TOTP密钥及otpauth URI(二维码内容)，需调用confirm确认后生效
+	var userInfo map[string]interface{}
+
+	userInfo, err = middleware.GetAuthedUserInfo(context)
+	if err != nil {
+		return
+	}
+	if middleware.GetAuthedApiKey(context) != nil {
+		err = exceptions.GetDefinedErrors(exception.ApiKeyNotAllowed)
+		return
+	}
+	user, ok := userInfo["user"].(*model.User)
+	if !ok || user == nil {
+		err = exceptions.GetDefinedErrors(exceptions.ServerError)
+		return
+	}
+	var enrollment *service.MFAEnrollment
+	enrollment, err = controller.mfaService.Enroll(user.ID, user.Username)
+	if err != nil {
+		return
+	}
+	data = controllers.Success(gin.H{
+		"secret":     enrollment.Secret,
+		"uri":        enrollment.URI,
+		"qr_payload": enrollment.URI,
+	})
+	return
+}
+
+func (controller *EnrollMFAController) DoRequest(context *gin.Context) (data *resp.Response, err error) {
+	return controller.enrollMFA(context)
+}
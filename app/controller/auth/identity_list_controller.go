@@ -0,0 +1,47 @@
+package auth
+
+import (
+	"com.github.gin-common/app/middleware"
+	"com.github.gin-common/app/model"
+	"com.github.gin-common/app/service"
+	"com.github.gin-common/common/controllers"
+	"com.github.gin-common/common/exceptions"
+	"com.github.gin-common/common/resp"
+	"github.com/gin-gonic/gin"
+)
+
+type IdentityListController struct {
+	oidcService service.OIDCService
+}
+
+func (controller *IdentityListController) Init(oidcService service.OIDCService) {
+	controller.oidcService = oidcService
+}
+
+func (controller *IdentityListController) identityList(context *gin.Context) (data *resp.Response, err error) {
+	// 获取当前用户绑定的第三方账号
+	var userInfo map[string]interface{}
+
+	userInfo, err = middleware.GetAuthedUserInfo(context)
+	if err != nil {
+		return
+	}
+	user, ok := userInfo["user"].(*model.User)
+	if !ok || user == nil {
+		err = exceptions.GetDefinedErrors(exceptions.ServerError)
+		return
+	}
+	var identities []model.UserIdentity
+	identities, err = controller.oidcService.ListIdentities(user.ID)
+	if err != nil {
+		return
+	}
+	data = controllers.Success(gin.H{
+		"identities": identities,
+	})
+	return
+}
+
+func (controller *IdentityListController) DoRequest(context *gin.Context) (data *resp.Response, err error) {
+	return controller.identityList(context)
+}
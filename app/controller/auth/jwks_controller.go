@@ -0,0 +1,27 @@
+package auth
+
+import (
+	"net/http"
+
+	"com.github.gin-common/common/resp"
+	"com.github.gin-common/tools/jwt_tool"
+	"github.com/gin-gonic/gin"
+)
+
+type JWKSController struct {
+}
+
+func (controller *JWKSController) jwks(context *gin.Context) (data *resp.Response, err error) {
+	// 公布校验token的公钥，按JWKS标准格式直接输出
+	m, err := jwt_tool.DefaultKeyManager()
+	if err != nil {
+		return
+	}
+	context.Header("Cache-Control", "public, max-age=300")
+	context.JSON(http.StatusOK, m.JWKS())
+	return
+}
+
+func (controller *JWKSController) DoRequest(context *gin.Context) (data *resp.Response, err error) {
+	return controller.jwks(context)
+}
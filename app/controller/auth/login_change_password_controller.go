@@ -0,0 +1,49 @@
+package auth
+
+import (
+	"com.github.gin-common/app/form"
+	"com.github.gin-common/app/service"
+	"com.github.gin-common/common/controllers"
+	"com.github.gin-common/common/resp"
+	"github.com/gin-gonic/gin"
+)
+
+type LoginChangePasswordController struct {
+	loginChangePasswordForm *form.LoginChangePasswordForm
+	authService             service.AuthService
+}
+
+func (controller *LoginChangePasswordController) Init(loginChangePasswordForm *form.LoginChangePasswordForm, authService service.AuthService) {
+	controller.loginChangePasswordForm = loginChangePasswordForm
+	controller.authService = authService
+}
+
+func (controller *LoginChangePasswordController) loginChangePassword(context *gin.Context) (data *resp.Response, err error) {
+	// 密码过期时使用登录返回的password_change_token设置新密码，成功后继续登录
+	if e := context.ShouldBindJSON(controller.loginChangePasswordForm); e != nil {
+		return nil, e
+	}
+	var result *service.LoginResult
+	result, err = controller.authService.LoginChangePassword(controller.loginChangePasswordForm.PasswordChangeToken, controller.loginChangePasswordForm.NewPassword)
+	if err != nil {
+		return
+	}
+	if result.MFARequired {
+		return controllers.Success(gin.H{
+			"mfa_required":   true,
+			"mfa_token":      result.MFAToken,
+			"mfa_expires_in": result.MFAExpiresIn,
+		}), err
+	}
+	tokens := result.Tokens
+	return controllers.Success(gin.H{
+		"token":              tokens.AccessToken,
+		"refresh_token":      tokens.RefreshToken,
+		"expires_in":         tokens.ExpiresIn,
+		"refresh_expires_in": tokens.RefreshExpiresIn,
+	}), err
+}
+
+func (controller *LoginChangePasswordController) DoRequest(context *gin.Context) (data *resp.Response, err error) {
+	return controller.loginChangePassword(context)
+}
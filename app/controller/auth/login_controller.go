@@ -3,6 +3,7 @@ package auth
 import (
 	"com.github.gin-common/app/form"
 	"com.github.gin-common/app/service"
+	"com.github.gin-common/common/client_ip"
 	"com.github.gin-common/common/controllers"
 	"com.github.gin-common/common/resp"
 	"github.com/gin-gonic/gin"
@@ -22,10 +23,37 @@ func (controller *LoginController) login(context *gin.Context) (data *resp.Respo
 	if e := context.ShouldBindJSON(controller.loginForm); e != nil {
 		return nil, e
 	}
-	var token service.TokenString
-	token, err = controller.authService.Login(controller.loginForm.UserName, controller.loginForm.Password)
+	var result *service.LoginResult
+	result, err = controller.authService.Login(controller.loginForm.UserName, controller.loginForm.Password, service.ClientInfo{
+		IP:        client_ip.Get(context),
+		UserAgent: context.Request.UserAgent(),
+		Device:    controller.loginForm.Device,
+	})
+	if err != nil {
+		return
+	}
+	if result.MFARequired {
+		// 需通过 /login/mfa 完成两步验证
+		return controllers.Success(gin.H{
+			"mfa_required":   true,
+			"mfa_token":      result.MFAToken,
+			"mfa_expires_in": result.MFAExpiresIn,
+		}), err
+	}
+	if result.PasswordChangeRequired {
+		// 密码已过期，需通过 /login/password 设置新密码
+		return controllers.Success(gin.H{
+			"password_change_required":   true,
+			"password_change_token":      result.PasswordChangeToken,
+			"password_change_expires_in": result.PasswordChangeExpiresIn,
+		}), err
+	}
+	tokens := result.Tokens
 	return controllers.Success(gin.H{
-		"token": token,
+		"token":              tokens.AccessToken,
+		"refresh_token":      tokens.RefreshToken,
+		"expires_in":         tokens.ExpiresIn,
+		"refresh_expires_in": tokens.RefreshExpiresIn,
 	}), err
 }
 
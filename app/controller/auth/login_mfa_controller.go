@@ -0,0 +1,41 @@
+package auth
+
+import (
+	"com.github.gin-common/app/form"
+	"com.github.gin-common/app/service"
+	"com.github.gin-common/common/controllers"
+	"com.github.gin-common/common/resp"
+	"github.com/gin-gonic/gin"
+)
+
+type LoginMFAController struct {
+	loginMFAForm *form.LoginMFAForm
+	authService  service.AuthService
+}
+
+func (controller *LoginMFAController) Init(loginMFAForm *form.LoginMFAForm, authService service.AuthService) {
+	controller.loginMFAForm = loginMFAForm
+	controller.authService = authService
+}
+
+func (controller *LoginMFAController) loginMFA(context *gin.Context) (data *resp.Response, err error) {
+	// 使用登录返回的mfa_token及动态码换取token
+	if e := context.ShouldBindJSON(controller.loginMFAForm); e != nil {
+		return nil, e
+	}
+	var tokens *service.TokenPair
+	tokens, err = controller.authService.LoginMFA(controller.loginMFAForm.MFAToken, controller.loginMFAForm.Code)
+	if err != nil {
+		return
+	}
+	return controllers.Success(gin.H{
+		"token":              tokens.AccessToken,
+		"refresh_token":      tokens.RefreshToken,
+		"expires_in":         tokens.ExpiresIn,
+		"refresh_expires_in": tokens.RefreshExpiresIn,
+	}), err
+}
+
+func (controller *LoginMFAController) DoRequest(context *gin.Context) (data *resp.Response, err error) {
+	return controller.loginMFA(context)
+}
@@ -0,0 +1,44 @@
+package auth
+
+import (
+	"com.github.gin-common/app/middleware"
+	"com.github.gin-common/app/model"
+	"com.github.gin-common/app/service"
+	"com.github.gin-common/common/controllers"
+	"com.github.gin-common/common/exceptions"
+	"com.github.gin-common/common/resp"
+	"github.com/gin-gonic/gin"
+)
+
+type LogoutAllController struct {
+	authService service.AuthService
+}
+
+func (controller *LogoutAllController) Init(authService service.AuthService) {
+	controller.authService = authService
+}
+
+func (controller *LogoutAllController) logoutAll(context *gin.Context) (data *resp.Response, err error) {
+	// 注销当前用户在所有设备上的会话
+	var userInfo map[string]interface{}
+
+	userInfo, err = middleware.GetAuthedUserInfo(context)
+	if err != nil {
+		return
+	}
+	user, ok := userInfo["user"].(*model.User)
+	if !ok || user == nil {
+		err = exceptions.GetDefinedErrors(exceptions.ServerError)
+		return
+	}
+	err = controller.authService.RevokeAllSessions(user.ID)
+	if err != nil {
+		return
+	}
+	data = controllers.Success(gin.H{})
+	return
+}
+
+func (controller *LogoutAllController) DoRequest(context *gin.Context) (data *resp.Response, err error) {
+	return controller.logoutAll(context)
+}
@@ -0,0 +1,47 @@
+package auth
+
+import (
+	"com.github.gin-common/app/middleware"
+	"com.github.gin-common/app/model"
+	"com.github.gin-common/app/service"
+	"com.github.gin-common/common/controllers"
+	"com.github.gin-common/common/exceptions"
+	"com.github.gin-common/common/resp"
+	"github.com/gin-gonic/gin"
+)
+
+type MFAStatusController struct {
+	mfaService service.MFAService
+}
+
+func (controller *MFAStatusController) Init(mfaService service.MFAService) {
+	controller.mfaService = mfaService
+}
+
+func (controller *MFAStatusController) mfaStatus(context *gin.Context) (data *resp.Response, err error) {
+	// 获取当前用户的两步验证状态
+	var userInfo map[string]interface{}
+
+	userInfo, err = middleware.GetAuthedUserInfo(context)
+	if err != nil {
+		return
+	}
+	user, ok := userInfo["user"].(*model.User)
+	if !ok || user == nil {
+		err = exceptions.GetDefinedErrors(exceptions.ServerError)
+		return
+	}
+	var status *service.MFAStatus
+	status, err = controller.mfaService.Status(user.ID)
+	if err != nil {
+		return
+	}
+	data = controllers.Success(gin.H{
+		"mfa": status,
+	})
+	return
+}
+
+func (controller *MFAStatusController) DoRequest(context *gin.Context) (data *resp.Response, err error) {
+	return controller.mfaStatus(context)
+}
@@ -0,0 +1,32 @@
+package auth
+
+import (
+	"net/http"
+
+	"com.github.gin-common/app/service"
+	"com.github.gin-common/common/resp"
+	"github.com/gin-gonic/gin"
+)
+
+type OIDCAuthorizeController struct {
+	oidcService service.OIDCService
+}
+
+func (controller *OIDCAuthorizeController) Init(oidcService service.OIDCService) {
+	controller.oidcService = oidcService
+}
+
+func (controller *OIDCAuthorizeController) authorize(context *gin.Context) (data *resp.Response, err error) {
+	// 跳转到身份提供方登录
+	var authURL string
+	authURL, err = controller.oidcService.AuthorizationURL(context.Param("provider"), 0)
+	if err != nil {
+		return
+	}
+	context.Redirect(http.StatusFound, authURL)
+	return
+}
+
+func (controller *OIDCAuthorizeController) DoRequest(context *gin.Context) (data *resp.Response, err error) {
+	return controller.authorize(context)
+}
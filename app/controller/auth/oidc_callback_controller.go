@@ -0,0 +1,64 @@
+package auth
+
+import (
+	"errors"
+
+	"com.github.gin-common/app/exception"
+	"com.github.gin-common/app/service"
+	"com.github.gin-common/common/client_ip"
+	"com.github.gin-common/common/controllers"
+	"com.github.gin-common/common/exceptions"
+	"com.github.gin-common/common/resp"
+	"github.com/gin-gonic/gin"
+)
+
+type OIDCCallbackController struct {
+	oidcService service.OIDCService
+}
+
+func (controller *OIDCCallbackController) Init(oidcService service.OIDCService) {
+	controller.oidcService = oidcService
+}
+
+func (controller *OIDCCallbackController) callback(context *gin.Context) (data *resp.Response, err error) {
+	// 身份提供方授权回调，登录流程返回token，绑定流程返回绑定信息
+	if e := context.Query("error"); e != "" {
+		if desc := context.Query("error_description"); desc != "" {
+			e = desc
+		}
+		err = exceptions.NewError(exception.OIDCLoginFailed, exceptions.WithError(errors.New(e)))()
+		return
+	}
+	var result *service.OIDCCallbackResult
+	result, err = controller.oidcService.Callback(context.Param("provider"), context.Query("state"), context.Query("code"), service.ClientInfo{
+		IP:        client_ip.Get(context),
+		UserAgent: context.Request.UserAgent(),
+		Device:    context.Query("device"),
+	})
+	if err != nil {
+		return
+	}
+	if result.Linked {
+		return controllers.Success(gin.H{
+			"identity": result.Identity,
+		}), err
+	}
+	if result.Login.MFARequired {
+		return controllers.Success(gin.H{
+			"mfa_required":   true,
+			"mfa_token":      result.Login.MFAToken,
+			"mfa_expires_in": result.Login.MFAExpiresIn,
+		}), err
+	}
+	tokens := result.Login.Tokens
+	return controllers.Success(gin.H{
+		"token":              tokens.AccessToken,
+		"refresh_token":      tokens.RefreshToken,
+		"expires_in":         tokens.ExpiresIn,
+		"refresh_expires_in": tokens.RefreshExpiresIn,
+	}), err
+}
+
+func (controller *OIDCCallbackController) DoRequest(context *gin.Context) (data *resp.Response, err error) {
+	return controller.callback(context)
+}
@@ -0,0 +1,47 @@
+package auth
+
+import (
+	"com.github.gin-common/app/middleware"
+	"com.github.gin-common/app/model"
+	"com.github.gin-common/app/service"
+	"com.github.gin-common/common/controllers"
+	"com.github.gin-common/common/exceptions"
+	"com.github.gin-common/common/resp"
+	"github.com/gin-gonic/gin"
+)
+
+type OIDCLinkController struct {
+	oidcService service.OIDCService
+}
+
+func (controller *OIDCLinkController) Init(oidcService service.OIDCService) {
+	controller.oidcService = oidcService
+}
+
+func (controller *OIDCLinkController) link(context *gin.Context) (data *resp.Response, err error) {
+	// 为当前用户绑定第三方账号，返回授权地址
+	var userInfo map[string]interface{}
+
+	userInfo, err = middleware.GetAuthedUserInfo(context)
+	if err != nil {
+		return
+	}
+	user, ok := userInfo["user"].(*model.User)
+	if !ok || user == nil {
+		err = exceptions.GetDefinedErrors(exceptions.ServerError)
+		return
+	}
+	var authURL string
+	authURL, err = controller.oidcService.AuthorizationURL(context.Param("provider"), user.ID)
+	if err != nil {
+		return
+	}
+	data = controllers.Success(gin.H{
+		"authorization_url": authURL,
+	})
+	return
+}
+
+func (controller *OIDCLinkController) DoRequest(context *gin.Context) (data *resp.Response, err error) {
+	return controller.link(context)
+}
@@ -0,0 +1,41 @@
+package auth
+
+import (
+	"com.github.gin-common/app/form"
+	"com.github.gin-common/app/service"
+	"com.github.gin-common/common/controllers"
+	"com.github.gin-common/common/resp"
+	"github.com/gin-gonic/gin"
+)
+
+type RefreshTokenController struct {
+	refreshTokenForm *form.RefreshTokenForm
+	authService      service.AuthService
+}
+
+func (controller *RefreshTokenController) Init(refreshTokenForm *form.RefreshTokenForm, authService service.AuthService) {
+	controller.refreshTokenForm = refreshTokenForm
+	controller.authService = authService
+}
+
+func (controller *RefreshTokenController) refreshToken(context *gin.Context) (data *resp.Response, err error) {
+	if e := context.ShouldBindJSON(controller.refreshTokenForm); e != nil {
+		return nil, e
+	}
+	var tokens *service.TokenPair
+	tokens, err = controller.authService.RefreshToken(controller.refreshTokenForm.RefreshToken)
+	if err != nil {
+		return
+	}
+	data = controllers.Success(gin.H{
+		"token":              tokens.AccessToken,
+		"refresh_token":      tokens.RefreshToken,
+		"expires_in":         tokens.ExpiresIn,
+		"refresh_expires_in": tokens.RefreshExpiresIn,
+	})
+	return
+}
+
+func (controller *RefreshTokenController) DoRequest(context *gin.Context) (data *resp.Response, err error) {
+	return controller.refreshToken(context)
+}
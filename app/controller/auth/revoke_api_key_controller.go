@@ -0,0 +1,52 @@
+package auth
+
+import (
+	"errors"
+	"strconv"
+
+	"com.github.gin-common/app/middleware"
+	"com.github.gin-common/app/model"
+	"com.github.gin-common/app/service"
+	"com.github.gin-common/common/controllers"
+	"com.github.gin-common/common/exceptions"
+	"com.github.gin-common/common/resp"
+	"github.com/gin-gonic/gin"
+)
+
+type RevokeApiKeyController struct {
+	apiKeyService service.ApiKeyService
+}
+
+func (controller *RevokeApiKeyController) Init(apiKeyService service.ApiKeyService) {
+	controller.apiKeyService = apiKeyService
+}
+
+func (controller *RevokeApiKeyController) revokeApiKey(context *gin.Context) (data *resp.Response, err error) {
+	// 删除当前用户的API Key
+	keyID, e := strconv.Atoi(context.Param("keyID"))
+	if e != nil {
+		err = exceptions.NewError(exceptions.BadRequest, exceptions.WithError(errors.New("传入正确的keyID")))()
+		return
+	}
+	var userInfo map[string]interface{}
+
+	userInfo, err = middleware.GetAuthedUserInfo(context)
+	if err != nil {
+		return
+	}
+	user, ok := userInfo["user"].(*model.User)
+	if !ok || user == nil {
+		err = exceptions.GetDefinedErrors(exceptions.ServerError)
+		return
+	}
+	err = controller.apiKeyService.RevokeApiKey(user.ID, uint(keyID))
+	if err != nil {
+		return
+	}
+	data = controllers.Success(gin.H{})
+	return
+}
+
+func (controller *RevokeApiKeyController) DoRequest(context *gin.Context) (data *resp.Response, err error) {
+	return controller.revokeApiKey(context)
+}
@@ -0,0 +1,44 @@
+package auth
+
+import (
+	"com.github.gin-common/app/middleware"
+	"com.github.gin-common/app/model"
+	"com.github.gin-common/app/service"
+	"com.github.gin-common/common/controllers"
+	"com.github.gin-common/common/exceptions"
+	"com.github.gin-common/common/resp"
+	"github.com/gin-gonic/gin"
+)
+
+type RevokeSessionController struct {
+	authService service.AuthService
+}
+
+func (controller *RevokeSessionController) Init(authService service.AuthService) {
+	controller.authService = authService
+}
+
+func (controller *RevokeSessionController) revokeSession(context *gin.Context) (data *resp.Response, err error) {
+	// 注销当前用户的指定会话
+	var userInfo map[string]interface{}
+
+	userInfo, err = middleware.GetAuthedUserInfo(context)
+	if err != nil {
+		return
+	}
+	user, ok := userInfo["user"].(*model.User)
+	if !ok || user == nil {
+		err = exceptions.GetDefinedErrors(exceptions.ServerError)
+		return
+	}
+	err = controller.authService.RevokeSession(user.ID, context.Param("sessionID"))
+	if err != nil {
+		return
+	}
+	data = controllers.Success(gin.H{})
+	return
+}
+
+func (controller *RevokeSessionController) DoRequest(context *gin.Context) (data *resp.Response, err error) {
+	return controller.revokeSession(context)
+}
@@ -0,0 +1,50 @@
+package auth
+
+import (
+	"com.github.gin-common/app/middleware"
+	"com.github.gin-common/app/model"
+	"com.github.gin-common/app/service"
+	"com.github.gin-common/common/controllers"
+	"com.github.gin-common/common/exceptions"
+	"com.github.gin-common/common/resp"
+	"github.com/gin-gonic/gin"
+)
+
+type SessionListController struct {
+	authService service.AuthService
+}
+
+func (controller *SessionListController) Init(authService service.AuthService) {
+	controller.authService = authService
+}
+
+func (controller *SessionListController) listSessions(context *gin.Context) (data *resp.Response, err error) {
+	// 获取当前用户的所有登录会话
+	var userInfo map[string]interface{}
+
+	userInfo, err = middleware.GetAuthedUserInfo(context)
+	if err != nil {
+		return
+	}
+	user, ok := userInfo["user"].(*model.User)
+	if !ok || user == nil {
+		err = exceptions.GetDefinedErrors(exceptions.ServerError)
+		return
+	}
+	currentSessionID, _ := userInfo["sessionID"].(string)
+
+	var sessions []service.Session
+	sessions, err = controller.authService.ListSessions(user.ID)
+	if err != nil {
+		return
+	}
+	data = controllers.Success(gin.H{
+		"sessions":        sessions,
+		"current_session": currentSessionID,
+	})
+	return
+}
+
+func (controller *SessionListController) DoRequest(context *gin.Context) (data *resp.Response, err error) {
+	return controller.listSessions(context)
+}
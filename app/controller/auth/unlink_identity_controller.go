@@ -0,0 +1,52 @@
+package auth
+
+import (
+	"errors"
+	"strconv"
+
+	"com.github.gin-common/app/middleware"
+	"com.github.gin-common/app/model"
+	"com.github.gin-common/app/service"
+	"com.github.gin-common/common/controllers"
+	"com.github.gin-common/common/exceptions"
+	"com.github.gin-common/common/resp"
+	"github.com/gin-gonic/gin"
+)
+
+type UnlinkIdentityController struct {
+	oidcService service.OIDCService
+}
+
+func (controller *UnlinkIdentityController) Init(oidcService service.OIDCService) {
+	controller.oidcService = oidcService
+}
+
+func (controller *UnlinkIdentityController) unlinkIdentity(context *gin.Context) (data *resp.Response, err error) {
+	// 解除当前用户的第三方账号绑定
+	identityID, e := strconv.Atoi(context.Param("identityID"))
+	if e != nil {
+		err = exceptions.NewError(exceptions.BadRequest, exceptions.WithError(errors.New("传入正确的identityID")))()
+		return
+	}
+	var userInfo map[string]interface{}
+
+	userInfo, err = middleware.GetAuthedUserInfo(context)
+	if err != nil {
+		return
+	}
+	user, ok := userInfo["user"].(*model.User)
+	if !ok || user == nil {
+		err = exceptions.GetDefinedErrors(exceptions.ServerError)
+		return
+	}
+	err = controller.oidcService.Unlink(user.ID, uint(identityID))
+	if err != nil {
+		return
+	}
+	data = controllers.Success(gin.H{})
+	return
+}
+
+func (controller *UnlinkIdentityController) DoRequest(context *gin.Context) (data *resp.Response, err error) {
+	return controller.unlinkIdentity(context)
+}
@@ -0,0 +1,42 @@
+package oauth
+
+import (
+	"com.github.gin-common/app/form"
+	"com.github.gin-common/app/service"
+	"com.github.gin-common/common/controllers"
+	"com.github.gin-common/common/resp"
+	"github.com/gin-gonic/gin"
+)
+
+type AuthorizeController struct {
+	consentForm  *form.OAuthConsentForm
+	oauthService service.OAuthService
+}
+
+func (controller *AuthorizeController) Init(consentForm *form.OAuthConsentForm, oauthService service.OAuthService) {
+	controller.consentForm = consentForm
+	controller.oauthService = oauthService
+}
+
+func (controller *AuthorizeController) authorize(context *gin.Context) (data *resp.Response, err error) {
+	// 用户确认或拒绝授权，返回客户端回调地址(携带code或error)
+	if e := context.ShouldBindJSON(controller.consentForm); e != nil {
+		return nil, e
+	}
+	user, err := authedUser(context)
+	if err != nil {
+		return
+	}
+	var redirectTo string
+	redirectTo, err = controller.oauthService.Authorize(authorizeRequest(&controller.consentForm.OAuthAuthorizeForm), user.ID, controller.consentForm.Approved)
+	if err != nil {
+		return
+	}
+	return controllers.Success(gin.H{
+		"redirect_to": redirectTo,
+	}), err
+}
+
+func (controller *AuthorizeController) DoRequest(context *gin.Context) (data *resp.Response, err error) {
+	return controller.authorize(context)
+}
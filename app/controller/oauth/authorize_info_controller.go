@@ -0,0 +1,54 @@
+package oauth
+
+import (
+	"com.github.gin-common/app/form"
+	"com.github.gin-common/app/service"
+	"com.github.gin-common/common/controllers"
+	"com.github.gin-common/common/resp"
+	"github.com/gin-gonic/gin"
+)
+
+type AuthorizeInfoController struct {
+	authorizeForm *form.OAuthAuthorizeForm
+	oauthService  service.OAuthService
+}
+
+func (controller *AuthorizeInfoController) Init(authorizeForm *form.OAuthAuthorizeForm, oauthService service.OAuthService) {
+	controller.authorizeForm = authorizeForm
+	controller.oauthService = oauthService
+}
+
+func (controller *AuthorizeInfoController) authorizeInfo(context *gin.Context) (data *resp.Response, err error) {
+	// 校验授权请求，返回授权确认页所需的客户端及授权范围
+	if e := context.ShouldBindQuery(controller.authorizeForm); e != nil {
+		return nil, e
+	}
+	user, err := authedUser(context)
+	if err != nil {
+		return
+	}
+	var info *service.OAuthAuthorizeInfo
+	info, err = controller.oauthService.AuthorizeInfo(authorizeRequest(controller.authorizeForm), user.ID)
+	if err != nil {
+		return
+	}
+	return controllers.Success(gin.H{
+		"authorize": info,
+	}), err
+}
+
+func (controller *AuthorizeInfoController) DoRequest(context *gin.Context) (data *resp.Response, err error) {
+	return controller.authorizeInfo(context)
+}
+
+func authorizeRequest(authorizeForm *form.OAuthAuthorizeForm) service.OAuthAuthorizeRequest {
+	return service.OAuthAuthorizeRequest{
+		ResponseType:        authorizeForm.ResponseType,
+		ClientID:            authorizeForm.ClientID,
+		RedirectURI:         authorizeForm.RedirectURI,
+		Scope:               authorizeForm.Scope,
+		State:               authorizeForm.State,
+		CodeChallenge:       authorizeForm.CodeChallenge,
+		CodeChallengeMethod: authorizeForm.CodeChallengeMethod,
+	}
+}
@@ -0,0 +1,41 @@
+package oauth
+
+import (
+	"net/http"
+
+	"com.github.gin-common/app/form"
+	"com.github.gin-common/app/service"
+	"com.github.gin-common/common/resp"
+	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
+)
+
+type IntrospectController struct {
+	tokenOperationForm *form.OAuthTokenOperationForm
+	oauthService       service.OAuthService
+}
+
+func (controller *IntrospectController) Init(tokenOperationForm *form.OAuthTokenOperationForm, oauthService service.OAuthService) {
+	controller.tokenOperationForm = tokenOperationForm
+	controller.oauthService = oauthService
+}
+
+func (controller *IntrospectController) introspect(context *gin.Context) (data *resp.Response, err error) {
+	// 查询token状态(RFC 7662)
+	if e := context.ShouldBindWith(controller.tokenOperationForm, binding.Form); e != nil {
+		return nil, renderOAuthError(context, service.NewOAuthError("invalid_request", e.Error()))
+	}
+	operationForm := controller.tokenOperationForm
+	introspection, err := controller.oauthService.Introspect(
+		clientCredentials(context, operationForm.ClientID, operationForm.ClientSecret), operationForm.Token, operationForm.TokenTypeHint)
+	if err != nil {
+		return nil, renderOAuthError(context, err)
+	}
+	context.Header("Cache-Control", "no-store")
+	context.JSON(http.StatusOK, introspection)
+	return
+}
+
+func (controller *IntrospectController) DoRequest(context *gin.Context) (data *resp.Response, err error) {
+	return controller.introspect(context)
+}
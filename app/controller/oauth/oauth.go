@@ -0,0 +1,53 @@
+package oauth
+
+import (
+	"errors"
+	"net/http"
+	"net/url"
+
+	"com.github.gin-common/app/middleware"
+	"com.github.gin-common/app/model"
+	"com.github.gin-common/app/service"
+	"com.github.gin-common/common/exceptions"
+	"github.com/gin-gonic/gin"
+)
+
+// 客户端认证优先使用Basic认证(RFC 6749 2.3.1)，其次为表单参数
+func clientCredentials(context *gin.Context, clientID string, clientSecret string) service.OAuthClientCredentials {
+	if id, secret, ok := context.Request.BasicAuth(); ok {
+		if v, err := url.QueryUnescape(id); err == nil {
+			id = v
+		}
+		if v, err := url.QueryUnescape(secret); err == nil {
+			secret = v
+		}
+		return service.OAuthClientCredentials{ClientID: id, ClientSecret: secret}
+	}
+	return service.OAuthClientCredentials{ClientID: clientID, ClientSecret: clientSecret}
+}
+
+// OAuthError按RFC 6749格式输出，其他错误交由ControllerHandler处理
+func renderOAuthError(context *gin.Context, err error) error {
+	var oauthErr *service.OAuthError
+	if !errors.As(err, &oauthErr) {
+		return err
+	}
+	if oauthErr.Status == http.StatusUnauthorized {
+		context.Header("WWW-Authenticate", `Basic realm="oauth"`)
+	}
+	context.Header("Cache-Control", "no-store")
+	context.JSON(oauthErr.Status, oauthErr)
+	return nil
+}
+
+func authedUser(context *gin.Context) (*model.User, error) {
+	userInfo, err := middleware.GetAuthedUserInfo(context)
+	if err != nil {
+		return nil, err
+	}
+	user, ok := userInfo["user"].(*model.User)
+	if !ok || user == nil {
+		return nil, exceptions.GetDefinedErrors(exceptions.ServerError)
+	}
+	return user, nil
+}
@@ -0,0 +1,41 @@
+package oauth
+
+import (
+	"net/http"
+
+	"com.github.gin-common/app/form"
+	"com.github.gin-common/app/service"
+	"com.github.gin-common/common/resp"
+	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
+)
+
+type RevokeController struct {
+	tokenOperationForm *form.OAuthTokenOperationForm
+	oauthService       service.OAuthService
+}
+
+func (controller *RevokeController) Init(tokenOperationForm *form.OAuthTokenOperationForm, oauthService service.OAuthService) {
+	controller.tokenOperationForm = tokenOperationForm
+	controller.oauthService = oauthService
+}
+
+func (controller *RevokeController) revoke(context *gin.Context) (data *resp.Response, err error) {
+	// 注销token(RFC 7009)，token无效时同样返回200
+	if e := context.ShouldBindWith(controller.tokenOperationForm, binding.Form); e != nil {
+		return nil, renderOAuthError(context, service.NewOAuthError("invalid_request", e.Error()))
+	}
+	operationForm := controller.tokenOperationForm
+	err = controller.oauthService.Revoke(
+		clientCredentials(context, operationForm.ClientID, operationForm.ClientSecret), operationForm.Token, operationForm.TokenTypeHint)
+	if err != nil {
+		return nil, renderOAuthError(context, err)
+	}
+	context.Status(http.StatusOK)
+	context.Writer.WriteHeaderNow()
+	return
+}
+
+func (controller *RevokeController) DoRequest(context *gin.Context) (data *resp.Response, err error) {
+	return controller.revoke(context)
+}
@@ -0,0 +1,48 @@
+package oauth
+
+import (
+	"net/http"
+
+	"com.github.gin-common/app/form"
+	"com.github.gin-common/app/service"
+	"com.github.gin-common/common/resp"
+	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
+)
+
+type TokenController struct {
+	tokenForm    *form.OAuthTokenForm
+	oauthService service.OAuthService
+}
+
+func (controller *TokenController) Init(tokenForm *form.OAuthTokenForm, oauthService service.OAuthService) {
+	controller.tokenForm = tokenForm
+	controller.oauthService = oauthService
+}
+
+func (controller *TokenController) token(context *gin.Context) (data *resp.Response, err error) {
+	// token端点，请求为表单格式，响应按RFC 6749格式直接输出
+	if e := context.ShouldBindWith(controller.tokenForm, binding.Form); e != nil {
+		return nil, renderOAuthError(context, service.NewOAuthError("invalid_request", e.Error()))
+	}
+	tokenForm := controller.tokenForm
+	token, err := controller.oauthService.Token(service.OAuthTokenRequest{
+		OAuthClientCredentials: clientCredentials(context, tokenForm.ClientID, tokenForm.ClientSecret),
+		GrantType:              tokenForm.GrantType,
+		Code:                   tokenForm.Code,
+		RedirectURI:            tokenForm.RedirectURI,
+		CodeVerifier:           tokenForm.CodeVerifier,
+		RefreshToken:           tokenForm.RefreshToken,
+		Scope:                  tokenForm.Scope,
+	})
+	if err != nil {
+		return nil, renderOAuthError(context, err)
+	}
+	context.Header("Cache-Control", "no-store")
+	context.JSON(http.StatusOK, token)
+	return
+}
+
+func (controller *TokenController) DoRequest(context *gin.Context) (data *resp.Response, err error) {
+	return controller.token(context)
+}
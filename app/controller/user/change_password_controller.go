@@ -5,6 +5,9 @@ import (
 	"strconv"
 
 	"com.github.gin-common/app/form"
+	"com.github.gin-common/app/middleware"
+	"com.github.gin-common/app/model"
+	"com.github.gin-common/app/policy"
 	"com.github.gin-common/app/service"
 	"com.github.gin-common/common/controllers"
 	"com.github.gin-common/common/exceptions"
@@ -15,11 +18,13 @@ import (
 type ChangePasswordController struct {
 	userService    service.UserService
 	changePassForm *form.ChangePassForm
+	roleService    service.RoleService
 }
 
-func (controller *ChangePasswordController) Init(userService service.UserService, changePassForm *form.ChangePassForm) {
+func (controller *ChangePasswordController) Init(userService service.UserService, changePassForm *form.ChangePassForm, roleService service.RoleService) {
 	controller.userService = userService
 	controller.changePassForm = changePassForm
+	controller.roleService = roleService
 }
 
 func (controller *ChangePasswordController) changePassword(context *gin.Context) (data *resp.Response, err error) {
@@ -30,11 +35,26 @@ func (controller *ChangePasswordController) changePassword(context *gin.Context)
 		err = exceptions.NewError(exceptions.BadRequest, exceptions.WithError(errors.New("传入正确的userID")))()
 		return
 	}
+	// 仅允许修改自己的密码或管理员操作
+	if err = policy.Authorize(context, controller.roleService, policy.ChangePassword, policy.UserResource(uint(intUserID))); err != nil {
+		return
+	}
 	if e := context.ShouldBindJSON(controller.changePassForm); e != nil {
 		err = e
 		return
 	}
-	err = controller.userService.ChangePassword(uint(intUserID), controller.changePassForm.OldPassword, controller.changePassForm.NewPassword)
+	var keepSessionIDs []string
+	if controller.changePassForm.KeepCurrentSession {
+		// 仅修改自己的密码时才保留当前会话
+		if userInfo, e := middleware.GetAuthedUserInfo(context); e == nil {
+			user, _ := userInfo["user"].(*model.User)
+			sessionID, _ := userInfo["sessionID"].(string)
+			if user != nil && user.ID == uint(intUserID) && sessionID != "" {
+				keepSessionIDs = append(keepSessionIDs, sessionID)
+			}
+		}
+	}
+	err = controller.userService.ChangePassword(uint(intUserID), controller.changePassForm.OldPassword, controller.changePassForm.NewPassword, keepSessionIDs...)
 	if err != nil {
 		return
 	}
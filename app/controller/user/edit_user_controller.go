@@ -12,6 +12,7 @@ import (
 
 	"com.github.gin-common/app/form"
 	"com.github.gin-common/app/model"
+	"com.github.gin-common/app/policy"
 	"com.github.gin-common/app/service"
 	"github.com/gin-gonic/gin"
 )
@@ -19,11 +20,13 @@ import (
 type EditUserController struct {
 	updateUserForm *form.UpdateUserForm
 	userService    service.UserService
+	roleService    service.RoleService
 }
 
-func (controller *EditUserController) Init(updateUserForm *form.UpdateUserForm, userService service.UserService) {
+func (controller *EditUserController) Init(updateUserForm *form.UpdateUserForm, userService service.UserService, roleService service.RoleService) {
 	controller.updateUserForm = updateUserForm
 	controller.userService = userService
+	controller.roleService = roleService
 }
 
 func (controller *EditUserController) editUser(context *gin.Context) (data *resp.Response, err error) {
@@ -33,19 +36,29 @@ func (controller *EditUserController) editUser(context *gin.Context) (data *resp
 		err = exceptions.NewError(exceptions.BadRequest, exceptions.WithError(errors.New("传入正确的userID")))()
 		return
 	}
+	// 仅允许修改自己的信息或管理员操作
+	if err = policy.Authorize(context, controller.roleService, policy.UpdateUser, policy.UserResource(uint(intUserID))); err != nil {
+		return
+	}
 	if e := context.ShouldBindJSON(controller.updateUserForm); e != nil {
 		err = e
 		return
 	}
+	// 传入If-Match时按其中的版本号更新，版本不一致返回409
+	var expectedVersion *uint
+	if expectedVersion, err = controllers.IfMatchVersion(context); err != nil {
+		return
+	}
 	var user *model.User
 	user, err = controller.userService.UpdateUser(uint(intUserID), model.User{
 		Name:     controller.updateUserForm.Name,
 		Email:    controller.updateUserForm.Email,
 		Username: controller.updateUserForm.UserName,
-	})
+	}, expectedVersion)
 	if err != nil {
 		return
 	}
+	controllers.SetVersionETag(context, user.Version)
 	data = controllers.Success(gin.H{
 		"user": user,
 	})
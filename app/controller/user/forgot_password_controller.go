@@ -0,0 +1,44 @@
+package user
+
+import (
+	"com.github.gin-common/app/form"
+	"com.github.gin-common/app/service"
+	"com.github.gin-common/common/client_ip"
+	"com.github.gin-common/common/controllers"
+	"com.github.gin-common/common/resp"
+	"github.com/gin-gonic/gin"
+)
+
+type ForgotPasswordController struct {
+	userService        service.UserService
+	loginGuardService  service.LoginGuardService
+	forgotPasswordForm *form.ForgotPasswordForm
+}
+
+func (controller *ForgotPasswordController) Init(userService service.UserService, loginGuardService service.LoginGuardService, forgotPasswordForm *form.ForgotPasswordForm) {
+	controller.userService = userService
+	controller.loginGuardService = loginGuardService
+	controller.forgotPasswordForm = forgotPasswordForm
+}
+
+func (controller *ForgotPasswordController) forgotPassword(context *gin.Context) (data *resp.Response, err error) {
+	// 发送重置密码邮件，无论邮箱是否存在都返回成功
+	if e := context.ShouldBindJSON(controller.forgotPasswordForm); e != nil {
+		err = e
+		return
+	}
+	err = controller.loginGuardService.ThrottlePasswordReset(controller.forgotPasswordForm.Email, client_ip.Get(context))
+	if err != nil {
+		return
+	}
+	err = controller.userService.RequestPasswordReset(controller.forgotPasswordForm.Email)
+	if err != nil {
+		return
+	}
+	data = controllers.Success(gin.H{})
+	return
+}
+
+func (controller *ForgotPasswordController) DoRequest(context *gin.Context) (data *resp.Response, err error) {
+	return controller.forgotPassword(context)
+}
@@ -35,7 +35,7 @@ func (controller *GetUserInfoController) getUserInfo(context *gin.Context) (data
 	if err != nil {
 		return
 	}
-
+	controllers.SetVersionETag(context, user.Version)
 	data = controllers.Success(gin.H{
 		"user": user,
 	})
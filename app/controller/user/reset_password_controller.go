@@ -0,0 +1,37 @@
+package user
+
+import (
+	"com.github.gin-common/app/form"
+	"com.github.gin-common/app/service"
+	"com.github.gin-common/common/controllers"
+	"com.github.gin-common/common/resp"
+	"github.com/gin-gonic/gin"
+)
+
+type ResetPasswordController struct {
+	userService       service.UserService
+	resetPasswordForm *form.ResetPasswordForm
+}
+
+func (controller *ResetPasswordController) Init(userService service.UserService, resetPasswordForm *form.ResetPasswordForm) {
+	controller.userService = userService
+	controller.resetPasswordForm = resetPasswordForm
+}
+
+func (controller *ResetPasswordController) resetPassword(context *gin.Context) (data *resp.Response, err error) {
+	// 使用邮件中的token重置密码
+	if e := context.ShouldBindJSON(controller.resetPasswordForm); e != nil {
+		err = e
+		return
+	}
+	err = controller.userService.ResetPassword(controller.resetPasswordForm.Token, controller.resetPasswordForm.NewPassword)
+	if err != nil {
+		return
+	}
+	data = controllers.Success(gin.H{})
+	return
+}
+
+func (controller *ResetPasswordController) DoRequest(context *gin.Context) (data *resp.Response, err error) {
+	return controller.resetPassword(context)
+}
@@ -0,0 +1,44 @@
+package user
+
+import (
+	"com.github.gin-common/app/middleware"
+	"com.github.gin-common/app/model"
+	"com.github.gin-common/app/service"
+	"com.github.gin-common/common/controllers"
+	"com.github.gin-common/common/exceptions"
+	"com.github.gin-common/common/resp"
+	"github.com/gin-gonic/gin"
+)
+
+type SendEmailVerificationController struct {
+	userService service.UserService
+}
+
+func (controller *SendEmailVerificationController) Init(userService service.UserService) {
+	controller.userService = userService
+}
+
+func (controller *SendEmailVerificationController) sendEmailVerification(context *gin.Context) (data *resp.Response, err error) {
+	// 向当前用户的邮箱发送验证邮件
+	var userInfo map[string]interface{}
+
+	userInfo, err = middleware.GetAuthedUserInfo(context)
+	if err != nil {
+		return
+	}
+	user, ok := userInfo["user"].(*model.User)
+	if !ok || user == nil {
+		err = exceptions.GetDefinedErrors(exceptions.ServerError)
+		return
+	}
+	err = controller.userService.SendEmailVerification(user.ID)
+	if err != nil {
+		return
+	}
+	data = controllers.Success(gin.H{})
+	return
+}
+
+func (controller *SendEmailVerificationController) DoRequest(context *gin.Context) (data *resp.Response, err error) {
+	return controller.sendEmailVerification(context)
+}
@@ -0,0 +1,58 @@
+package user
+
+import (
+	"com.github.gin-common/app/form"
+	"com.github.gin-common/app/service"
+	"com.github.gin-common/common/controllers"
+	"com.github.gin-common/common/resp"
+	"github.com/gin-gonic/gin"
+)
+
+type UserListController struct {
+	userListForm *form.UserListForm
+	userService  service.UserService
+}
+
+func (controller *UserListController) Init(userListForm *form.UserListForm, userService service.UserService) {
+	controller.userListForm = userListForm
+	controller.userService = userService
+}
+
+// 解析列表查询参数，传入cursor或mode=cursor时使用cursor分页
+func bindUserListQuery(context *gin.Context, listForm *form.UserListForm) (query service.UserListQuery, err error) {
+	if err = context.ShouldBindQuery(listForm); err != nil {
+		return
+	}
+	query = service.UserListQuery{
+		Search:         listForm.Search,
+		ActivateStatus: listForm.ActivateStatus,
+		DeleteStatus:   listForm.DeleteStatus,
+		CreatedAfter:   listForm.CreatedAfter,
+		CreatedBefore:  listForm.CreatedBefore,
+		Sort:           listForm.Sort,
+		Page:           listForm.Page,
+		PageSize:       listForm.PageSize,
+		Values:         context.Request.URL.Query(),
+		CursorMode:     listForm.Mode == resp.PageModeCursor || listForm.Cursor != "",
+		Cursor:         listForm.Cursor,
+	}
+	return
+}
+
+func (controller *UserListController) listUsers(context *gin.Context) (data *resp.Response, err error) {
+	// 分页查询用户列表
+	var query service.UserListQuery
+	if query, err = bindUserListQuery(context, controller.userListForm); err != nil {
+		return
+	}
+	var result *service.UserListResult
+	result, err = controller.userService.ListUsers(query)
+	if err != nil {
+		return
+	}
+	return controllers.Success(resp.PageData(result.Users, result.Pagination)), nil
+}
+
+func (controller *UserListController) DoRequest(context *gin.Context) (data *resp.Response, err error) {
+	return controller.listUsers(context)
+}
@@ -0,0 +1,39 @@
+package user
+
+import (
+	"com.github.gin-common/app/form"
+	"com.github.gin-common/app/service"
+	"com.github.gin-common/common/controllers"
+	"com.github.gin-common/common/resp"
+	"github.com/gin-gonic/gin"
+)
+
+type UserTrashController struct {
+	userListForm *form.UserListForm
+	userService  service.UserService
+}
+
+func (controller *UserTrashController) Init(userListForm *form.UserListForm, userService service.UserService) {
+	controller.userListForm = userListForm
+	controller.userService = userService
+}
+
+func (controller *UserTrashController) listDeletedUsers(context *gin.Context) (data *resp.Response, err error) {
+	// 分页查询已删除的用户，参数同用户列表(delete_status无效)
+	var query service.UserListQuery
+	if query, err = bindUserListQuery(context, controller.userListForm); err != nil {
+		return
+	}
+	deleted := true
+	query.DeleteStatus = &deleted
+	var result *service.UserListResult
+	result, err = controller.userService.ListUsers(query)
+	if err != nil {
+		return
+	}
+	return controllers.Success(resp.PageData(result.Users, result.Pagination)), nil
+}
+
+func (controller *UserTrashController) DoRequest(context *gin.Context) (data *resp.Response, err error) {
+	return controller.listDeletedUsers(context)
+}
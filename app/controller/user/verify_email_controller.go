@@ -0,0 +1,37 @@
+package user
+
+import (
+	"com.github.gin-common/app/form"
+	"com.github.gin-common/app/service"
+	"com.github.gin-common/common/controllers"
+	"com.github.gin-common/common/resp"
+	"github.com/gin-gonic/gin"
+)
+
+type VerifyEmailController struct {
+	userService     service.UserService
+	verifyEmailForm *form.VerifyEmailForm
+}
+
+func (controller *VerifyEmailController) Init(userService service.UserService, verifyEmailForm *form.VerifyEmailForm) {
+	controller.userService = userService
+	controller.verifyEmailForm = verifyEmailForm
+}
+
+func (controller *VerifyEmailController) verifyEmail(context *gin.Context) (data *resp.Response, err error) {
+	// 使用邮件中的token完成邮箱验证
+	if e := context.ShouldBindJSON(controller.verifyEmailForm); e != nil {
+		err = e
+		return
+	}
+	err = controller.userService.VerifyEmail(controller.verifyEmailForm.Token)
+	if err != nil {
+		return
+	}
+	data = controllers.Success(gin.H{})
+	return
+}
+
+func (controller *VerifyEmailController) DoRequest(context *gin.Context) (data *resp.Response, err error) {
+	return controller.verifyEmail(context)
+}
@@ -0,0 +1,47 @@
+package exception
+
+import (
+	"net/http"
+
+	"com.github.gin-common/common/exceptions"
+)
+
+func ApiKeyInvalid() *exceptions.ApiError {
+	return &exceptions.ApiError{
+		Code:          "600001",
+		HttpCode:      http.StatusUnauthorized,
+		DefaultErrMsg: "API Key无效",
+	}
+}
+
+func ApiKeyExpired() *exceptions.ApiError {
+	return &exceptions.ApiError{
+		Code:          "600002",
+		HttpCode:      http.StatusUnauthorized,
+		DefaultErrMsg: "API Key已过期",
+	}
+}
+
+func ApiKeyNotFound() *exceptions.ApiError {
+	return &exceptions.ApiError{
+		Code:          "600003",
+		HttpCode:      http.StatusNotFound,
+		DefaultErrMsg: "API Key不存在",
+	}
+}
+
+func ApiKeyCreateFailed() *exceptions.ApiError {
+	return &exceptions.ApiError{
+		Code:          "600004",
+		HttpCode:      http.StatusInternalServerError,
+		DefaultErrMsg: "创建API Key失败",
+	}
+}
+
+func ApiKeyNotAllowed() *exceptions.ApiError {
+	return &exceptions.ApiError{
+		Code:          "600005",
+		HttpCode:      http.StatusForbidden,
+		DefaultErrMsg: "不允许使用API Key进行此操作",
+	}
+}
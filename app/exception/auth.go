@@ -45,3 +45,91 @@ func UserDeactivated() *exceptions.ApiError {
 		DefaultErrMsg: "用户被禁用",
 	}
 }
+
+func RefreshTokenInvalid() *exceptions.ApiError {
+	return &exceptions.ApiError{
+		Code:          "300006",
+		HttpCode:      http.StatusUnauthorized,
+		DefaultErrMsg: "refresh token无效",
+	}
+}
+
+func RefreshTokenReused() *exceptions.ApiError {
+	return &exceptions.ApiError{
+		Code:          "300007",
+		HttpCode:      http.StatusUnauthorized,
+		DefaultErrMsg: "refresh token已被使用，相关登录已全部失效",
+	}
+}
+
+func SessionNotFound() *exceptions.ApiError {
+	return &exceptions.ApiError{
+		Code:          "300008",
+		HttpCode:      http.StatusNotFound,
+		DefaultErrMsg: "会话不存在",
+	}
+}
+
+func TokenSignatureInvalid() *exceptions.ApiError {
+	return &exceptions.ApiError{
+		Code:          "300009",
+		HttpCode:      http.StatusUnauthorized,
+		DefaultErrMsg: "token签名无效",
+	}
+}
+
+func TokenNotYetValid() *exceptions.ApiError {
+	return &exceptions.ApiError{
+		Code:          "300010",
+		HttpCode:      http.StatusUnauthorized,
+		DefaultErrMsg: "token尚未生效",
+	}
+}
+
+func TokenIssuerInvalid() *exceptions.ApiError {
+	return &exceptions.ApiError{
+		Code:          "300011",
+		HttpCode:      http.StatusUnauthorized,
+		DefaultErrMsg: "token签发者无效",
+	}
+}
+
+func TokenAudienceInvalid() *exceptions.ApiError {
+	return &exceptions.ApiError{
+		Code:          "300012",
+		HttpCode:      http.StatusUnauthorized,
+		DefaultErrMsg: "token受众无效",
+	}
+}
+
+func TokenClaimMissing() *exceptions.ApiError {
+	return &exceptions.ApiError{
+		Code:          "300013",
+		HttpCode:      http.StatusUnauthorized,
+		DefaultErrMsg: "token缺少必要声明",
+	}
+}
+
+func TokenRevoked() *exceptions.ApiError {
+	return &exceptions.ApiError{
+		Code:          "300014",
+		HttpCode:      http.StatusUnauthorized,
+		DefaultErrMsg: "token已注销",
+	}
+}
+
+func AccountLocked() *exceptions.ApiError {
+	return &exceptions.ApiError{
+		Code:          "300015",
+		HttpCode:      http.StatusLocked,
+		DefaultErrMsg: "登录失败次数过多，账号已被临时锁定",
+	}
+}
+
+func PasswordChangeChallengeInvalid() *exceptions.ApiError {
+	return &exceptions.ApiError{
+		Code:          "300016",
+		HttpCode:      http.StatusUnauthorized,
+		DefaultErrMsg: "修改密码请求已失效，请重新登录",
+	}
+}
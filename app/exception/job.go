@@ -0,0 +1,23 @@
+package exception
+
+import (
+	"net/http"
+
+	"com.github.gin-common/common/exceptions"
+)
+
+func WorkflowNotFound() *exceptions.ApiError {
+	return &exceptions.ApiError{
+		Code:          "400001",
+		HttpCode:      http.StatusNotFound,
+		DefaultErrMsg: "工作流不存在",
+	}
+}
+
+func WorkflowRunNotFound() *exceptions.ApiError {
+	return &exceptions.ApiError{
+		Code:          "400002",
+		HttpCode:      http.StatusNotFound,
+		DefaultErrMsg: "工作流运行记录不存在",
+	}
+}
@@ -0,0 +1,55 @@
+package exception
+
+import (
+	"net/http"
+
+	"com.github.gin-common/common/exceptions"
+)
+
+func MFACodeInvalid() *exceptions.ApiError {
+	return &exceptions.ApiError{
+		Code:          "700001",
+		HttpCode:      http.StatusUnauthorized,
+		DefaultErrMsg: "验证码错误",
+	}
+}
+
+func MFAChallengeInvalid() *exceptions.ApiError {
+	return &exceptions.ApiError{
+		Code:          "700002",
+		HttpCode:      http.StatusUnauthorized,
+		DefaultErrMsg: "两步验证已失效，请重新登录",
+	}
+}
+
+func MFANotEnrolled() *exceptions.ApiError {
+	return &exceptions.ApiError{
+		Code:          "700003",
+		HttpCode:      http.StatusBadRequest,
+		DefaultErrMsg: "未绑定两步验证",
+	}
+}
+
+func MFAAlreadyEnabled() *exceptions.ApiError {
+	return &exceptions.ApiError{
+		Code:          "700004",
+		HttpCode:      http.StatusBadRequest,
+		DefaultErrMsg: "两步验证已开启",
+	}
+}
+
+func MFANotEnabled() *exceptions.ApiError {
+	return &exceptions.ApiError{
+		Code:          "700005",
+		HttpCode:      http.StatusBadRequest,
+		DefaultErrMsg: "两步验证未开启",
+	}
+}
+
+func MFAUpdateFailed() *exceptions.ApiError {
+	return &exceptions.ApiError{
+		Code:          "700006",
+		HttpCode:      http.StatusInternalServerError,
+		DefaultErrMsg: "更新两步验证失败",
+	}
+}
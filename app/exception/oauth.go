@@ -0,0 +1,31 @@
+package exception
+
+import (
+	"net/http"
+
+	"com.github.gin-common/common/exceptions"
+)
+
+func OAuthClientNotFound() *exceptions.ApiError {
+	return &exceptions.ApiError{
+		Code:          "900001",
+		HttpCode:      http.StatusNotFound,
+		DefaultErrMsg: "OAuth客户端不存在",
+	}
+}
+
+func OAuthClientCreateFailed() *exceptions.ApiError {
+	return &exceptions.ApiError{
+		Code:          "900002",
+		HttpCode:      http.StatusInternalServerError,
+		DefaultErrMsg: "创建OAuth客户端失败",
+	}
+}
+
+func OAuthAuthorizeInvalid() *exceptions.ApiError {
+	return &exceptions.ApiError{
+		Code:          "900003",
+		HttpCode:      http.StatusBadRequest,
+		DefaultErrMsg: "授权请求无效",
+	}
+}
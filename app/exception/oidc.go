@@ -0,0 +1,55 @@
+package exception
+
+import (
+	"net/http"
+
+	"com.github.gin-common/common/exceptions"
+)
+
+func OIDCProviderNotFound() *exceptions.ApiError {
+	return &exceptions.ApiError{
+		Code:          "800001",
+		HttpCode:      http.StatusNotFound,
+		DefaultErrMsg: "身份提供方不存在",
+	}
+}
+
+func OIDCStateInvalid() *exceptions.ApiError {
+	return &exceptions.ApiError{
+		Code:          "800002",
+		HttpCode:      http.StatusBadRequest,
+		DefaultErrMsg: "登录请求已失效，请重新登录",
+	}
+}
+
+func OIDCLoginFailed() *exceptions.ApiError {
+	return &exceptions.ApiError{
+		Code:          "800003",
+		HttpCode:      http.StatusUnauthorized,
+		DefaultErrMsg: "第三方登录失败",
+	}
+}
+
+func OIDCIdentityLinked() *exceptions.ApiError {
+	return &exceptions.ApiError{
+		Code:          "800004",
+		HttpCode:      http.StatusConflict,
+		DefaultErrMsg: "该第三方账号已绑定其他用户",
+	}
+}
+
+func OIDCIdentityNotFound() *exceptions.ApiError {
+	return &exceptions.ApiError{
+		Code:          "800005",
+		HttpCode:      http.StatusNotFound,
+		DefaultErrMsg: "第三方账号绑定不存在",
+	}
+}
+
+func OIDCUserNotFound() *exceptions.ApiError {
+	return &exceptions.ApiError{
+		Code:          "800006",
+		HttpCode:      http.StatusForbidden,
+		DefaultErrMsg: "该第三方账号未绑定用户",
+	}
+}
@@ -0,0 +1,55 @@
+package exception
+
+import (
+	"net/http"
+
+	"com.github.gin-common/common/exceptions"
+)
+
+func RoleNotFound() *exceptions.ApiError {
+	return &exceptions.ApiError{
+		Code:          "500001",
+		HttpCode:      http.StatusNotFound,
+		DefaultErrMsg: "角色不存在",
+	}
+}
+
+func RoleNameDuplicate() *exceptions.ApiError {
+	return &exceptions.ApiError{
+		Code:          "500002",
+		HttpCode:      http.StatusInternalServerError,
+		DefaultErrMsg: "角色名重复",
+	}
+}
+
+func RoleCreateFailed() *exceptions.ApiError {
+	return &exceptions.ApiError{
+		Code:          "500003",
+		HttpCode:      http.StatusInternalServerError,
+		DefaultErrMsg: "创建角色失败",
+	}
+}
+
+func RoleUpdateFailed() *exceptions.ApiError {
+	return &exceptions.ApiError{
+		Code:          "500004",
+		HttpCode:      http.StatusInternalServerError,
+		DefaultErrMsg: "更新角色失败",
+	}
+}
+
+func RoleDeleteFailed() *exceptions.ApiError {
+	return &exceptions.ApiError{
+		Code:          "500005",
+		HttpCode:      http.StatusInternalServerError,
+		DefaultErrMsg: "删除角色失败",
+	}
+}
+
+func AssignRoleFailed() *exceptions.ApiError {
+	return &exceptions.ApiError{
+		Code:          "500006",
+		HttpCode:      http.StatusInternalServerError,
+		DefaultErrMsg: "分配角色失败",
+	}
+}
@@ -77,3 +77,67 @@ func ChangePassFailed() *exceptions.ApiError {
 		DefaultErrMsg: "修改密码失败",
 	}
 }
+
+func UserTokenInvalid() *exceptions.ApiError {
+	return &exceptions.ApiError{
+		Code:          "200009",
+		HttpCode:      http.StatusBadRequest,
+		DefaultErrMsg: "链接无效或已过期",
+	}
+}
+
+func MailSendFailed() *exceptions.ApiError {
+	return &exceptions.ApiError{
+		Code:          "200010",
+		HttpCode:      http.StatusInternalServerError,
+		DefaultErrMsg: "发送邮件失败",
+	}
+}
+
+func EmailNotSet() *exceptions.ApiError {
+	return &exceptions.ApiError{
+		Code:          "200011",
+		HttpCode:      http.StatusBadRequest,
+		DefaultErrMsg: "未设置邮箱",
+	}
+}
+
+func EmailAlreadyVerified() *exceptions.ApiError {
+	return &exceptions.ApiError{
+		Code:          "200012",
+		HttpCode:      http.StatusBadRequest,
+		DefaultErrMsg: "邮箱已验证",
+	}
+}
+
+func PasswordPolicyViolated() *exceptions.ApiError {
+	return &exceptions.ApiError{
+		Code:          "200013",
+		HttpCode:      http.StatusBadRequest,
+		DefaultErrMsg: "密码不符合安全要求",
+	}
+}
+
+func PasswordReused() *exceptions.ApiError {
+	return &exceptions.ApiError{
+		Code:          "200014",
+		HttpCode:      http.StatusBadRequest,
+		DefaultErrMsg: "不能使用最近使用过的密码",
+	}
+}
+
+func RestoreUserFailed() *exceptions.ApiError {
+	return &exceptions.ApiError{
+		Code:          "200015",
+		HttpCode:      http.StatusInternalServerError,
+		DefaultErrMsg: "恢复用户失败",
+	}
+}
+
+func PasswordResetThrottled() *exceptions.ApiError {
+	return &exceptions.ApiError{
+		Code:          "200016",
+		HttpCode:      http.StatusTooManyRequests,
+		DefaultErrMsg: "找回密码请求过于频繁，请稍后再试",
+	}
+}
@@ -1,6 +1,33 @@
 package form
 
+import "time"
+
 type LoginForm struct {
 	UserName string `binding:"required" json:"username"`
 	Password string `binding:"required" json:"password"`
+	Device   string `binding:"max=256" json:"device"`
+}
+
+type RefreshTokenForm struct {
+	RefreshToken string `binding:"required" json:"refresh_token"`
+}
+
+type CreateApiKeyForm struct {
+	Name      string     `binding:"required,max=128" json:"name"`
+	Scopes    []string   `binding:"dive,required,max=128" json:"scopes"`
+	ExpiresAt *time.Time `json:"expires_at"`
+}
+
+type LoginMFAForm struct {
+	MFAToken string `binding:"required" json:"mfa_token"`
+	Code     string `binding:"required,max=32" json:"code"`
+}
+
+type MFACodeForm struct {
+	Code string `binding:"required,max=32" json:"code"`
+}
+
+type LoginChangePasswordForm struct {
+	PasswordChangeToken string `binding:"required" json:"password_change_token"`
+	NewPassword         string `binding:"required" json:"new_password"`
 }
@@ -0,0 +1,6 @@
+package form
+
+type UnlockLoginForm struct {
+	Username string `binding:"required_without=IP,max=256" json:"username"`
+	IP       string `binding:"required_without=Username,max=64" json:"ip"`
+}
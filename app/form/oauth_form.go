@@ -0,0 +1,42 @@
+package form
+
+type OAuthAuthorizeForm struct {
+	ResponseType        string `binding:"required" form:"response_type" json:"response_type"`
+	ClientID            string `binding:"required" form:"client_id" json:"client_id"`
+	RedirectURI         string `form:"redirect_uri" json:"redirect_uri"`
+	Scope               string `form:"scope" json:"scope"`
+	State               string `form:"state" json:"state"`
+	CodeChallenge       string `binding:"required" form:"code_challenge" json:"code_challenge"`
+	CodeChallengeMethod string `binding:"required" form:"code_challenge_method" json:"code_challenge_method"`
+}
+
+type OAuthConsentForm struct {
+	OAuthAuthorizeForm
+	Approved bool `json:"approved"`
+}
+
+type OAuthTokenForm struct {
+	GrantType    string `binding:"required" form:"grant_type"`
+	Code         string `form:"code"`
+	RedirectURI  string `form:"redirect_uri"`
+	CodeVerifier string `form:"code_verifier"`
+	RefreshToken string `form:"refresh_token"`
+	Scope        string `form:"scope"`
+	ClientID     string `form:"client_id"`
+	ClientSecret string `form:"client_secret"`
+}
+
+type OAuthTokenOperationForm struct {
+	Token         string `binding:"required" form:"token"`
+	TokenTypeHint string `form:"token_type_hint"`
+	ClientID      string `form:"client_id"`
+	ClientSecret  string `form:"client_secret"`
+}
+
+type CreateOAuthClientForm struct {
+	Name         string   `binding:"required,max=128" json:"name"`
+	Type         string   `binding:"required,oneof=confidential public" json:"type"`
+	RedirectURIs []string `binding:"dive,required,url" json:"redirect_uris"`
+	Scopes       []string `binding:"dive,required,max=128" json:"scopes"`
+	GrantTypes   []string `binding:"dive,oneof=authorization_code client_credentials refresh_token" json:"grant_types"`
+}
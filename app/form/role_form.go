@@ -0,0 +1,16 @@
+package form
+
+type CreateRoleForm struct {
+	Name        string   `binding:"required,max=128" json:"name"`
+	Description string   `binding:"max=256" json:"description"`
+	Permissions []string `binding:"dive,required,max=128" json:"permissions"`
+}
+
+type UpdateRoleForm struct {
+	Description string   `binding:"max=256" json:"description"`
+	Permissions []string `binding:"required,dive,required,max=128" json:"permissions"`
+}
+
+type AssignRolesForm struct {
+	RoleIDs []uint `binding:"required,min=1" json:"role_ids"`
+}
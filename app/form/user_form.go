@@ -1,5 +1,7 @@
 package form
 
+import "time"
+
 type CreateUserForm struct {
 	UserName string `binding:"required" json:"username"`
 	Password string `binding:"required" json:"password"`
@@ -16,4 +18,32 @@ type UpdateUserForm struct {
 type ChangePassForm struct {
 	OldPassword string `binding:"required,nefield=NewPassword" json:"old_password"`
 	NewPassword string `binding:"required" json:"new_password"`
+	// 修改自己的密码时是否保留当前登录会话
+	KeepCurrentSession bool `json:"keep_current_session"`
+}
+
+type ForgotPasswordForm struct {
+	Email string `binding:"required,email" json:"email"`
+}
+
+type ResetPasswordForm struct {
+	Token       string `binding:"required" json:"token"`
+	NewPassword string `binding:"required" json:"new_password"`
+}
+
+type VerifyEmailForm struct {
+	Token string `binding:"required" json:"token"`
+}
+
+type UserListForm struct {
+	Search         string     `binding:"max=256" form:"search"`
+	ActivateStatus *bool      `form:"activate_status"`
+	DeleteStatus   *bool      `form:"delete_status"`
+	CreatedAfter   *time.Time `form:"created_after" time_format:"2006-01-02T15:04:05Z07:00"`
+	CreatedBefore  *time.Time `form:"created_before" time_format:"2006-01-02T15:04:05Z07:00"`
+	Sort           string     `form:"sort"`
+	Mode           string     `binding:"omitempty,oneof=offset cursor" form:"mode"`
+	Page           int        `binding:"omitempty,min=1" form:"page"`
+	PageSize       int        `binding:"omitempty,min=1,max=100" form:"page_size"`
+	Cursor         string     `form:"cursor"`
 }
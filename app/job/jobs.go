@@ -0,0 +1,22 @@
+package job
+
+import (
+	"github.com/robfig/cron/v3"
+	"go.uber.org/zap"
+
+	"com.github.gin-common/app/service"
+	"com.github.gin-common/common/jobs"
+)
+
+// 注册应用的定时任务
+func RegisterJobs(c *cron.Cron, newUserService func() service.UserService, logger zap.Logger) error {
+	purgeJob, err := NewPurgeDeletedUsersJob(newUserService, logger)
+	if err != nil {
+		return err
+	}
+	if purgeJob != nil {
+		job := &jobs.Job{}
+		job.Init("purgeDeletedUsers", c, purgeJob)
+	}
+	return nil
+}
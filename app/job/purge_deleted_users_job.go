@@ -0,0 +1,69 @@
+package job
+
+import (
+	"strconv"
+	"time"
+
+	"go.uber.org/zap"
+
+	"com.github.gin-common/app/service"
+	"com.github.gin-common/util"
+)
+
+// 每批物理删除的用户数
+const purgeBatchSize = 200
+
+// 定期物理删除软删除超过保留期的用户
+type PurgeDeletedUsersJob struct {
+	spec      string
+	retention time.Duration
+	// 每批使用新的service，避免数据库会话超时
+	newUserService func() service.UserService
+	logger         zap.Logger
+}
+
+func (job *PurgeDeletedUsersJob) Init(spec string, retention time.Duration, newUserService func() service.UserService, logger zap.Logger) {
+	job.spec = spec
+	job.retention = retention
+	job.newUserService = newUserService
+	job.logger = logger
+}
+
+func (job *PurgeDeletedUsersJob) Spec() string {
+	return job.spec
+}
+
+func (job *PurgeDeletedUsersJob) Run() error {
+	before := time.Now().Add(-job.retention)
+	var total int64
+	for {
+		purged, err := job.newUserService().PurgeDeletedUsers(before, purgeBatchSize)
+		if err != nil {
+			// 返回错误会导致任务panic，记录日志后等待下次执行
+			job.logger.Error("purge deleted users failed", zap.Int64("purged", total), zap.Error(err))
+			return nil
+		}
+		total += purged
+		if purged < purgeBatchSize {
+			break
+		}
+	}
+	if total > 0 {
+		job.logger.Info("purged deleted users", zap.Int64("purged", total))
+	}
+	return nil
+}
+
+// 读取USER_PURGE_RETENTION_DAYS(默认30，0为不清理)及USER_PURGE_SPEC(默认每天)
+func NewPurgeDeletedUsersJob(newUserService func() service.UserService, logger zap.Logger) (*PurgeDeletedUsersJob, error) {
+	days, err := strconv.Atoi(util.GetDefaultEnv("USER_PURGE_RETENTION_DAYS", "30"))
+	if err != nil {
+		return nil, err
+	}
+	if days <= 0 {
+		return nil, nil
+	}
+	job := &PurgeDeletedUsersJob{}
+	job.Init(util.GetDefaultEnv("USER_PURGE_SPEC", "@daily"), time.Duration(days)*24*time.Hour, newUserService, logger)
+	return job, nil
+}
@@ -6,6 +6,7 @@ import (
 	"errors"
 	"fmt"
 	"net/http"
+	"strconv"
 	"strings"
 
 	"com.github.gin-common/app/service"
@@ -15,6 +16,8 @@ import (
 	"com.github.gin-common/app/model"
 
 	"com.github.gin-common/common/exceptions"
+	"com.github.gin-common/common/request_scope"
+	"com.github.gin-common/common/routers"
 
 	"com.github.gin-common/app/exception"
 
@@ -32,19 +35,59 @@ func extractToken(r *http.Request) string {
 	return ""
 }
 
+// jwt校验错误与ApiError的对应关系
+var tokenErrors = []struct {
+	err    error
+	apiErr exceptions.ApiErrorDefFunc
+}{
+	{jwt_tool.ErrTokenMalformed, exception.TokenInvalid},
+	{jwt_tool.ErrTokenSignatureInvalid, exception.TokenSignatureInvalid},
+	{jwt_tool.ErrTokenExpired, exception.TokenExpired},
+	{jwt_tool.ErrTokenNotYetValid, exception.TokenNotYetValid},
+	{jwt_tool.ErrTokenIssuerInvalid, exception.TokenIssuerInvalid},
+	{jwt_tool.ErrTokenAudienceInvalid, exception.TokenAudienceInvalid},
+	{jwt_tool.ErrTokenClaimMissing, exception.TokenClaimMissing},
+}
+
+func tokenApiError(err error) error {
+	for _, e := range tokenErrors {
+		if errors.Is(err, e.err) {
+			return exceptions.GetDefinedErrors(e.apiErr)
+		}
+	}
+	return exceptions.GetDefinedErrors(exception.TokenInvalid)
+}
+
+// API Key可通过X-API-Key或Authorization: ApiKey xxx传递
+func extractApiKey(r *http.Request) string {
+	if key := r.Header.Get("X-API-Key"); key != "" {
+		return key
+	}
+	strArr := strings.Split(r.Header.Get("Authorization"), " ")
+	if len(strArr) == 2 && strings.EqualFold(strArr[0], "ApiKey") {
+		return strArr[1]
+	}
+	return ""
+}
+
 type AuthMiddleware struct {
-	rdb         *redis.Client
-	ctx         context.Context
-	userService service.UserService
+	rdb           *redis.Client
+	ctx           context.Context
+	userService   service.UserService
+	apiKeyService service.ApiKeyService
 }
 
-func (middleware *AuthMiddleware) Init(rdb *redis.Client, userService service.UserService, ctx context.Context) {
+func (middleware *AuthMiddleware) Init(rdb *redis.Client, userService service.UserService, apiKeyService service.ApiKeyService, ctx context.Context) {
 	middleware.rdb = rdb
 	middleware.userService = userService
+	middleware.apiKeyService = apiKeyService
 	middleware.ctx = ctx
 }
 
 func (middleware *AuthMiddleware) Before(ctx *gin.Context) (err error) {
+	if key := extractApiKey(ctx.Request); key != "" {
+		return middleware.apiKeyAuth(ctx, key)
+	}
 	token := extractToken(ctx.Request)
 	if token == "" {
 		return exceptions.GetDefinedErrors(exception.TokenInvalid)
@@ -53,50 +96,107 @@ func (middleware *AuthMiddleware) Before(ctx *gin.Context) (err error) {
 	claims, err = jwt_tool.TokenClaims(token)
 
 	if err != nil {
-		if ve, ok := err.(*jwt.ValidationError); ok {
-			if ve.Errors&jwt.ValidationErrorSignatureInvalid != 0 {
-				return exceptions.GetDefinedErrors(exception.TokenInvalid)
-			}
-			if ve.Errors&jwt.ValidationErrorExpired != 0 {
-				return exceptions.GetDefinedErrors(exception.TokenExpired)
-			}
-			return exceptions.GetDefinedErrors(exception.TokenInvalid)
-		}
-		return exceptions.GetDefinedErrors(exception.TokenInvalid)
+		return tokenApiError(err)
 	}
 
-	tokenUUID, ok := claims["tokenUUID"].(string)
+	tokenUUID, ok := claims["jti"].(string)
 	if !ok {
-		return exceptions.GetDefinedErrors(exception.TokenInvalid)
+		return exceptions.GetDefinedErrors(exception.TokenClaimMissing)
 	}
 
-	var val string
-	val, err = middleware.rdb.Get(middleware.ctx, fmt.Sprintf("accessToken:%s", tokenUUID)).Result()
-	if err != nil {
-		if err == redis.Nil {
-			// token失效
-			return exceptions.GetDefinedErrors(exception.TokenExpired)
-		}
-		return
+	var userData *service.AccessTokenData
+	if jwt_tool.StatelessMode() {
+		userData, err = middleware.statelessTokenData(claims, tokenUUID)
+	} else {
+		userData, err = middleware.storedTokenData(claims, tokenUUID)
 	}
-	var userData map[string]uint
-	err = json.Unmarshal([]byte(val), &userData)
 	if err != nil {
 		return
 	}
-	//根据userID获取用户信息
+	//根据userID获取用户信息(无状态模式下取自进程内缓存)
 	var user *model.User
-	user, err = middleware.userService.GetUserInfoById(userData["userId"])
+	user, err = middleware.userService.GetUserInfoById(userData.UserID)
 	if err != nil {
 		return
 	}
+	request_scope.Get(ctx).SetUser(user.ID, user.Username)
 	ctx.Set("userInfo", map[string]interface{}{
 		"user":      user,
 		"tokenUUID": tokenUUID,
+		"sessionID": userData.FamilyID,
+	})
+	return
+}
+
+// 使用API Key认证，userInfo中额外保存apiKey用于校验授权范围
+func (middleware *AuthMiddleware) apiKeyAuth(ctx *gin.Context, key string) (err error) {
+	var apiKey *model.ApiKey
+	apiKey, err = middleware.apiKeyService.Authenticate(key)
+	if err != nil {
+		return
+	}
+	// 未声明权限的路由无法按授权范围校验，只允许拥有全部权限(*)的API Key访问
+	if routers.RoutePermission(ctx) == "" && !apiKey.HasScope(model.PermissionAll) {
+		return exceptions.GetDefinedErrors(exceptions.Forbidden)
+	}
+	var user *model.User
+	user, err = middleware.userService.GetUserInfoById(apiKey.UserID)
+	if err != nil {
+		return
+	}
+	request_scope.Get(ctx).SetUser(user.ID, user.Username)
+	ctx.Set("userInfo", map[string]interface{}{
+		"user":   user,
+		"apiKey": apiKey,
 	})
 	return
 }
 
+// 当前请求使用的API Key，非API Key认证时返回nil
+func GetAuthedApiKey(ctx *gin.Context) *model.ApiKey {
+	userInfo, err := GetAuthedUserInfo(ctx)
+	if err != nil {
+		return nil
+	}
+	apiKey, _ := userInfo["apiKey"].(*model.ApiKey)
+	return apiKey
+}
+
+// 有状态模式：从redis中获取access token对应的用户
+func (middleware *AuthMiddleware) storedTokenData(claims jwt.MapClaims, tokenUUID string) (*service.AccessTokenData, error) {
+	val, err := middleware.rdb.Get(middleware.ctx, fmt.Sprintf("accessToken:%s", tokenUUID)).Result()
+	if err != nil {
+		if err == redis.Nil {
+			// token失效
+			return nil, exceptions.GetDefinedErrors(exception.TokenExpired)
+		}
+		return nil, err
+	}
+	var userData service.AccessTokenData
+	if err = json.Unmarshal([]byte(val), &userData); err != nil {
+		return nil, err
+	}
+	// sub须与token对应的用户一致
+	if sub, _ := claims["sub"].(string); sub != strconv.FormatUint(uint64(userData.UserID), 10) {
+		return nil, exceptions.GetDefinedErrors(exception.TokenInvalid)
+	}
+	return &userData, nil
+}
+
+// 无状态模式：用户信息取自token声明，仅检查本地缓存的注销列表
+func (middleware *AuthMiddleware) statelessTokenData(claims jwt.MapClaims, tokenUUID string) (*service.AccessTokenData, error) {
+	if jwt_tool.DefaultDenylist().Contains(tokenUUID) {
+		return nil, exceptions.GetDefinedErrors(exception.TokenRevoked)
+	}
+	sub, _ := claims["sub"].(string)
+	userID, err := strconv.ParseUint(sub, 10, 64)
+	if err != nil {
+		return nil, exceptions.GetDefinedErrors(exception.TokenInvalid)
+	}
+	familyID, _ := claims["sid"].(string)
+	return &service.AccessTokenData{UserID: uint(userID), FamilyID: familyID}, nil
+}
+
 func (middleware *AuthMiddleware) After(ctx *gin.Context) (err error) {
 	return
 }
@@ -156,3 +256,25 @@ func (middleware *DeactivatedAbortMiddleware) DeniedBeforeAbortContext() bool {
 func (middleware *DeactivatedAbortMiddleware) AllowAfterAbortContext() bool {
 	return false
 }
+
+// 拒绝API Key认证的请求，用于凭据、两步验证、会话及OAuth授权等只允许用户本人操作的接口，需在AuthMiddleware之后使用
+type ApiKeyDeniedMiddleware struct{}
+
+func (middleware *ApiKeyDeniedMiddleware) Before(ctx *gin.Context) (err error) {
+	if GetAuthedApiKey(ctx) != nil {
+		return exceptions.GetDefinedErrors(exception.ApiKeyNotAllowed)
+	}
+	return
+}
+
+func (middleware *ApiKeyDeniedMiddleware) After(ctx *gin.Context) (err error) {
+	return
+}
+
+func (middleware *ApiKeyDeniedMiddleware) DeniedBeforeAbortContext() bool {
+	return false
+}
+
+func (middleware *ApiKeyDeniedMiddleware) AllowAfterAbortContext() bool {
+	return false
+}
@@ -0,0 +1,61 @@
+package middleware
+
+import (
+	"com.github.gin-common/app/model"
+	"com.github.gin-common/app/service"
+	"com.github.gin-common/common/exceptions"
+	"com.github.gin-common/common/routers"
+	"github.com/gin-gonic/gin"
+)
+
+// 根据路由声明的权限(RouteDesc.Permission)校验当前用户，需在AuthMiddleware之后使用
+type PermissionMiddleware struct {
+	roleService service.RoleService
+}
+
+func (middleware *PermissionMiddleware) Init(roleService service.RoleService) {
+	middleware.roleService = roleService
+}
+
+func (middleware *PermissionMiddleware) Before(ctx *gin.Context) (err error) {
+	permission := routers.RoutePermission(ctx)
+	if permission == "" {
+		return
+	}
+	var userInfo map[string]interface{}
+
+	userInfo, err = GetAuthedUserInfo(ctx)
+	if err != nil {
+		return
+	}
+	user, ok := userInfo["user"].(*model.User)
+	if !ok {
+		err = exceptions.GetDefinedErrors(exceptions.ServerError)
+		return
+	}
+	// API Key请求还需在其授权范围内
+	if apiKey, ok := userInfo["apiKey"].(*model.ApiKey); ok && !apiKey.HasScope(permission) {
+		return exceptions.GetDefinedErrors(exceptions.Forbidden)
+	}
+	var allowed bool
+	allowed, err = middleware.roleService.HasPermission(user.ID, permission)
+	if err != nil {
+		return
+	}
+	if !allowed {
+		return exceptions.GetDefinedErrors(exceptions.Forbidden)
+	}
+	return
+}
+
+func (middleware *PermissionMiddleware) After(ctx *gin.Context) (err error) {
+	return
+}
+
+func (middleware *PermissionMiddleware) DeniedBeforeAbortContext() bool {
+	return false
+}
+
+func (middleware *PermissionMiddleware) AllowAfterAbortContext() bool {
+	return false
+}
@@ -0,0 +1,41 @@
+package model
+
+import (
+	"strings"
+	"time"
+
+	"com.github.gin-common/common/models"
+)
+
+// 个人API Key，只保存摘要，通过前缀查找
+type ApiKey struct {
+	models.BaseModel
+	UserID     uint       `gorm:"not null;index" json:"user_id"`
+	Name       string     `gorm:"size:128;not null" json:"name"`
+	Prefix     string     `gorm:"size:16;not null;uniqueIndex" json:"prefix"`
+	KeyHash    string     `gorm:"size:64;not null" json:"-"`
+	Scopes     string     `gorm:"size:1024;not null;default:''" json:"scopes"`
+	ExpiresAt  *time.Time `json:"expires_at"`
+	LastUsedAt *time.Time `json:"last_used_at"`
+}
+
+func (key *ApiKey) ScopeList() []string {
+	if key.Scopes == "" {
+		return nil
+	}
+	return strings.Split(key.Scopes, ",")
+}
+
+// 判断API Key的授权范围是否包含权限
+func (key *ApiKey) HasScope(permission string) bool {
+	for _, scope := range key.ScopeList() {
+		if scope == permission || scope == PermissionAll {
+			return true
+		}
+	}
+	return false
+}
+
+func (key *ApiKey) Expired() bool {
+	return key.ExpiresAt != nil && key.ExpiresAt.Before(time.Now())
+}
@@ -0,0 +1,28 @@
+package model
+
+import (
+	"time"
+
+	"com.github.gin-common/common/models"
+)
+
+const (
+	LockoutScopeUser = "user"
+	LockoutScopeIP   = "ip"
+)
+
+// 登录锁定记录，每次锁定及管理员解锁都会留存
+type LoginLockout struct {
+	models.BaseModel
+	// 锁定维度: user/ip
+	Scope string `gorm:"size:16;not null;index:idx_lockout_subject" json:"scope"`
+	// 被锁定的用户名或IP
+	Subject     string     `gorm:"size:256;not null;index:idx_lockout_subject" json:"subject"`
+	Username    string     `gorm:"size:256;not null;default:''" json:"username"`
+	IP          string     `gorm:"size:64;not null;default:''" json:"ip"`
+	Failures    int        `gorm:"not null" json:"failures"`
+	LockCount   int        `gorm:"not null" json:"lock_count"`
+	LockedUntil time.Time  `json:"locked_until"`
+	UnlockedAt  *time.Time `json:"unlocked_at"`
+	UnlockedBy  *uint      `json:"unlocked_by"`
+}
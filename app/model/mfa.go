@@ -0,0 +1,26 @@
+package model
+
+import (
+	"time"
+
+	"com.github.gin-common/common/models"
+)
+
+// 用户的TOTP两步验证配置，确认后才生效
+type UserMFA struct {
+	models.BaseModel
+	UserID      uint       `gorm:"not null;uniqueIndex" json:"user_id"`
+	Secret      string     `gorm:"size:64;not null" json:"-"`
+	Enabled     bool       `gorm:"not null;default:0" json:"enabled"`
+	ConfirmedAt *time.Time `json:"confirmed_at"`
+	// 最近一次使用的时间窗口，防止动态码重放
+	LastCounter int64 `gorm:"not null;default:0" json:"-"`
+}
+
+// 一次性恢复码，只保存摘要
+type MFARecoveryCode struct {
+	models.BaseModel
+	UserID   uint       `gorm:"not null;index" json:"user_id"`
+	CodeHash string     `gorm:"size:64;not null" json:"-"`
+	UsedAt   *time.Time `json:"used_at"`
+}
@@ -0,0 +1,82 @@
+package model
+
+import (
+	"strings"
+
+	"com.github.gin-common/common/models"
+)
+
+const (
+	OAuthClientConfidential = "confidential"
+	OAuthClientPublic       = "public"
+)
+
+const (
+	GrantAuthorizationCode = "authorization_code"
+	GrantClientCredentials = "client_credentials"
+	GrantRefreshToken      = "refresh_token"
+)
+
+// 注册到授权服务器的OAuth2客户端，secret只保存摘要(public客户端没有secret)
+// RedirectURIs、Scopes、GrantTypes均以空格分隔
+type OAuthClient struct {
+	models.BaseModel
+	ClientID     string `gorm:"size:64;not null;uniqueIndex" json:"client_id"`
+	SecretHash   string `gorm:"size:64;not null;default:''" json:"-"`
+	Name         string `gorm:"size:128;not null" json:"name"`
+	Type         string `gorm:"size:16;not null" json:"type"`
+	RedirectURIs string `gorm:"size:2048;not null;default:''" json:"redirect_uris"`
+	Scopes       string `gorm:"size:1024;not null;default:''" json:"scopes"`
+	GrantTypes   string `gorm:"size:256;not null" json:"grant_types"`
+}
+
+func (client *OAuthClient) IsPublic() bool {
+	return client.Type == OAuthClientPublic
+}
+
+func (client *OAuthClient) RedirectURIList() []string {
+	return strings.Fields(client.RedirectURIs)
+}
+
+func (client *OAuthClient) ScopeList() []string {
+	return strings.Fields(client.Scopes)
+}
+
+func containsField(fields string, value string) bool {
+	for _, field := range strings.Fields(fields) {
+		if field == value {
+			return true
+		}
+	}
+	return false
+}
+
+func (client *OAuthClient) AllowsGrant(grantType string) bool {
+	return containsField(client.GrantTypes, grantType)
+}
+
+func (client *OAuthClient) AllowsRedirectURI(uri string) bool {
+	return containsField(client.RedirectURIs, uri)
+}
+
+func (client *OAuthClient) AllowsScope(scope string) bool {
+	return containsField(client.Scopes, scope)
+}
+
+// 用户对客户端的授权同意记录
+type OAuthConsent struct {
+	models.BaseModel
+	UserID   uint   `gorm:"not null;uniqueIndex:idx_consent_user_client" json:"user_id"`
+	ClientID string `gorm:"size:64;not null;uniqueIndex:idx_consent_user_client" json:"client_id"`
+	Scopes   string `gorm:"size:1024;not null;default:''" json:"scopes"`
+}
+
+// 已同意的授权范围是否包含全部scopes
+func (consent *OAuthConsent) Covers(scopes []string) bool {
+	for _, scope := range scopes {
+		if !containsField(consent.Scopes, scope) {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,10 @@
+package model
+
+import "com.github.gin-common/common/models"
+
+// 用户使用过的密码摘要，用于禁止重复使用最近的密码
+type PasswordHistory struct {
+	models.BaseModel
+	UserID uint   `gorm:"not null;index" json:"user_id"`
+	Hash   string `gorm:"size:256;not null" json:"-"`
+}
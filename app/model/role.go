@@ -0,0 +1,31 @@
+package model
+
+import (
+	"com.github.gin-common/common/models"
+)
+
+// 拥有该权限的角色可访问所有路由
+const PermissionAll = "*"
+
+// 初始化时创建的超级管理员角色
+const RoleAdmin = "admin"
+
+type Permission struct {
+	models.BaseModel
+	Code        string `gorm:"unique;not null;size:128" json:"code"`
+	Description string `gorm:"size:256;not null;default:''" json:"description"`
+}
+
+type Role struct {
+	models.BaseModel
+	models.OperatorModel
+	Name        string       `gorm:"unique;not null;size:128" json:"name"`
+	Description string       `gorm:"size:256;not null;default:''" json:"description"`
+	Permissions []Permission `gorm:"many2many:role_permissions" json:"permissions"`
+}
+
+type UserRole struct {
+	models.BaseModel
+	UserID uint `gorm:"not null;uniqueIndex:idx_user_role" json:"user_id"`
+	RoleID uint `gorm:"not null;uniqueIndex:idx_user_role;index" json:"role_id"`
+}
@@ -1,23 +1,40 @@
 package model
 
 import (
+	"time"
+
 	"com.github.gin-common/common/models"
-	"com.github.gin-common/util"
+	"com.github.gin-common/tools/password_tool"
 )
 
 type User struct {
 	models.BaseModel
 	models.SoftDeleteModel
 	models.ActivateModel
-	Username string `gorm:"unique;not null;size:256" json:"username"`
+	models.VersionModel
+	models.OperatorModel
+	// 与delete_mark组成联合唯一索引(见migrate)，已删除用户的用户名可以重新使用
+	Username string `gorm:"not null;size:256" json:"username"`
 	Name     string `gorm:"size:256;not null;default:''" json:"name"`
-	Password string `gorm:"size:256;not null;" json:"-"`
+	Password string `gorm:"size:256;not null;" json:"-" audit:"mask"`
 	Email    string `gorm:"size:256" json:"email"`
+	// 邮箱验证时间，修改邮箱后清空
+	EmailVerifiedAt *time.Time `json:"email_verified_at"`
+	// 最后修改密码的时间，用于判断密码是否过期
+	PasswordChangedAt *time.Time `json:"password_changed_at"`
+}
+
+func (user *User) AuditTarget() string {
+	return "user"
 }
 
 func (user *User) SetPass(pwd string) error {
 	// 修改密码
-	encryptPass, err := util.HashAndSalt([]byte(pwd))
+	hashers, err := password_tool.DefaultHashers()
+	if err != nil {
+		return err
+	}
+	encryptPass, err := hashers.Hash(pwd)
 	if err != nil {
 		return err
 	}
@@ -26,5 +43,30 @@ func (user *User) SetPass(pwd string) error {
 }
 
 func (user *User) CheckPass(pwd string) bool {
-	return util.CompareHash(user.Password, []byte(pwd))
+	// 按哈希值前缀识别算法校验
+	hashers, err := password_tool.DefaultHashers()
+	if err != nil {
+		return false
+	}
+	return hashers.Verify(user.Password, pwd)
+}
+
+// 密码哈希算法或参数与当前首选配置不一致
+func (user *User) NeedsRehash() bool {
+	hashers, err := password_tool.DefaultHashers()
+	if err != nil {
+		return false
+	}
+	return hashers.NeedsRehash(user.Password)
+}
+
+func (user *User) EmailVerified() bool {
+	return user.Email != "" && user.EmailVerifiedAt != nil
+}
+
+func (user *User) PasswordChangedTime() time.Time {
+	if user.PasswordChangedAt != nil {
+		return *user.PasswordChangedAt
+	}
+	return user.CreatedAt
 }
@@ -0,0 +1,12 @@
+package model
+
+import "com.github.gin-common/common/models"
+
+// 外部身份(OIDC)与本地用户的绑定，provider+subject唯一
+type UserIdentity struct {
+	models.BaseModel
+	UserID   uint   `gorm:"not null;index" json:"user_id"`
+	Provider string `gorm:"size:64;not null;uniqueIndex:idx_identity_provider_subject" json:"provider"`
+	Subject  string `gorm:"size:255;not null;uniqueIndex:idx_identity_provider_subject" json:"subject"`
+	Email    string `gorm:"size:256" json:"email"`
+}
@@ -0,0 +1,67 @@
+package policy
+
+import (
+	"errors"
+
+	"com.github.gin-common/app/middleware"
+	"com.github.gin-common/app/model"
+	"com.github.gin-common/app/service"
+	"com.github.gin-common/common/exceptions"
+	"com.github.gin-common/common/policy"
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	// 修改用户信息
+	UpdateUser = "user:update"
+	// 修改用户密码
+	ChangePassword = "user:change_password"
+
+	ResourceUser = "user"
+)
+
+// 注册代码中声明的策略，可通过POLICY_FILE覆盖
+func RegisterPolicies(engine *policy.Engine) {
+	ownerOrAdmin := policy.Any(policy.IsOwner(), policy.HasRole(model.RoleAdmin))
+	engine.Register(UpdateUser, ownerOrAdmin)
+	engine.Register(ChangePassword, ownerOrAdmin)
+}
+
+// 构造用户资源，用户资源的所有者即其本身
+func UserResource(userID uint) policy.Resource {
+	return policy.Resource{Type: ResourceUser, ID: userID, OwnerID: userID}
+}
+
+// 使用用户及其角色、权限构造访问主体
+func SubjectOf(user *model.User, roleService service.RoleService) (subject policy.Subject, err error) {
+	subject = policy.Subject{ID: user.ID, Attrs: map[string]interface{}{
+		"username": user.Username,
+		"active":   user.ActivateStatus,
+	}}
+	roles, err := roleService.GetUserRoles(user.ID)
+	if err != nil {
+		return
+	}
+	for _, role := range roles {
+		subject.Roles = append(subject.Roles, role.Name)
+	}
+	subject.Permissions, err = roleService.GetUserPermissions(user.ID)
+	return
+}
+
+// 以当前登录用户为主体校验策略
+func Authorize(ctx *gin.Context, roleService service.RoleService, name string, resource policy.Resource) error {
+	userInfo, err := middleware.GetAuthedUserInfo(ctx)
+	if err != nil {
+		return exceptions.NewError(exceptions.Unauthorized, exceptions.WithError(err))()
+	}
+	user, ok := userInfo["user"].(*model.User)
+	if !ok || user == nil {
+		return exceptions.NewError(exceptions.Unauthorized, exceptions.WithError(errors.New("未获取到用户信息")))()
+	}
+	subject, err := SubjectOf(user, roleService)
+	if err != nil {
+		return err
+	}
+	return policy.Default().Authorize(name, subject, resource)
+}
@@ -0,0 +1,24 @@
+package policy_test
+
+import (
+	"testing"
+
+	"com.github.gin-common/app/model"
+	apppolicy "com.github.gin-common/app/policy"
+	"com.github.gin-common/common/policy"
+	"com.github.gin-common/common/policy/policytest"
+)
+
+func TestOwnerOrAdmin(t *testing.T) {
+	engine := policy.NewEngine()
+	apppolicy.RegisterPolicies(engine)
+	cases := []policytest.Case{
+		{Name: "owner", Subject: policy.Subject{ID: 1}, Resource: apppolicy.UserResource(1), Allowed: true},
+		{Name: "other user", Subject: policy.Subject{ID: 2}, Resource: apppolicy.UserResource(1), Allowed: false},
+		{Name: "admin", Subject: policy.Subject{ID: 2, Roles: []string{model.RoleAdmin}}, Resource: apppolicy.UserResource(1), Allowed: true},
+		{Name: "other role", Subject: policy.Subject{ID: 2, Roles: []string{"user"}}, Resource: apppolicy.UserResource(1), Allowed: false},
+		{Name: "anonymous", Subject: policy.Subject{}, Resource: apppolicy.UserResource(0), Allowed: false},
+	}
+	policytest.AssertCases(t, engine, apppolicy.UpdateUser, cases)
+	policytest.AssertCases(t, engine, apppolicy.ChangePassword, cases)
+}
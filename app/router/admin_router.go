@@ -0,0 +1,79 @@
+package router
+
+import (
+	"net/http"
+
+	"com.github.gin-common/common/controllers"
+
+	"com.github.gin-common/wires"
+
+	"com.github.gin-common/common/routers"
+)
+
+type AdminRouter struct{}
+
+func (router AdminRouter) GroupName() string {
+	return "/admin"
+}
+
+func (router AdminRouter) GroupConfig() map[string][]routers.RouteDesc {
+	return map[string][]routers.RouteDesc{
+		"/workflows": {
+			{Method: http.MethodGet, Controller: []controllers.ControllerFunc{wires.WorkflowListController}, Permission: "job:read"},
+		},
+		"/workflows/:name": {
+			{Method: http.MethodGet, Controller: []controllers.ControllerFunc{wires.WorkflowGraphController}, Permission: "job:read"},
+		},
+		"/workflows/:name/runs": {
+			{Method: http.MethodGet, Controller: []controllers.ControllerFunc{wires.WorkflowRunsController}, Permission: "job:read"},
+		},
+		"/workflows/:name/runs/:runID": {
+			{Method: http.MethodGet, Controller: []controllers.ControllerFunc{wires.WorkflowRunsController}, Permission: "job:read"},
+		},
+		"/audit": {
+			{Method: http.MethodGet, Controller: []controllers.ControllerFunc{wires.AuditLogListController}, Permission: "audit:read"},
+		},
+		"/lockouts": {
+			{Method: http.MethodGet, Controller: []controllers.ControllerFunc{wires.LockoutListController}, Permission: "lockout:read"},
+		},
+		"/lockouts/unlock": {
+			{Method: http.MethodPost, Controller: []controllers.ControllerFunc{wires.UnlockLoginController}, Permission: "lockout:manage"},
+		},
+		"/oauth/clients": {
+			{Method: http.MethodGet, Controller: []controllers.ControllerFunc{wires.OAuthClientListController}, Permission: "oauth_client:read"},
+			{Method: http.MethodPost, Controller: []controllers.ControllerFunc{wires.CreateOAuthClientController}, Permission: "oauth_client:manage"},
+		},
+		"/oauth/clients/:clientID": {
+			{Method: http.MethodDelete, Controller: []controllers.ControllerFunc{wires.DeleteOAuthClientController}, Permission: "oauth_client:manage"},
+		},
+		"/roles": {
+			{Method: http.MethodGet, Controller: []controllers.ControllerFunc{wires.RoleListController}, Permission: "role:read"},
+			{Method: http.MethodPost, Controller: []controllers.ControllerFunc{wires.CreateRoleController}, Permission: "role:manage"},
+		},
+		"/roles/:roleID": {
+			{Method: http.MethodPut, Controller: []controllers.ControllerFunc{wires.UpdateRoleController}, Permission: "role:manage"},
+			{Method: http.MethodDelete, Controller: []controllers.ControllerFunc{wires.DeleteRoleController}, Permission: "role:manage"},
+		},
+		"/permissions": {
+			{Method: http.MethodGet, Controller: []controllers.ControllerFunc{wires.PermissionListController}, Permission: "role:read"},
+		},
+		"/trash/users": {
+			{Method: http.MethodGet, Controller: []controllers.ControllerFunc{wires.UserTrashController}, Permission: "user:read"},
+		},
+		"/users/:userID/roles": {
+			{Method: http.MethodGet, Controller: []controllers.ControllerFunc{wires.UserRolesController}, Permission: "role:read"},
+			{Method: http.MethodPost, Controller: []controllers.ControllerFunc{wires.AssignUserRolesController}, Permission: "role:manage"},
+		},
+		"/users/:userID/roles/:roleID": {
+			{Method: http.MethodDelete, Controller: []controllers.ControllerFunc{wires.UnassignUserRoleController}, Permission: "role:manage"},
+		},
+	}
+}
+
+func (router AdminRouter) GroupMiddleware() []controllers.MiddlewareFunc {
+	return []controllers.MiddlewareFunc{
+		wires.AuthMiddleware,
+		wires.DeactivatedAbortMiddleware,
+		wires.PermissionMiddleware,
+	}
+}
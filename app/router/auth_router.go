@@ -21,10 +21,88 @@ func (router AuthRouter) GroupConfig() map[string][]routers.RouteDesc {
 		"/login": {
 			routers.RouteDesc{Method: http.MethodPost, Controller: []controllers.ControllerFunc{wires.LoginController}},
 		},
+		"/login/mfa": {
+			routers.RouteDesc{Method: http.MethodPost, Controller: []controllers.ControllerFunc{wires.LoginMFAController}},
+		},
+		"/login/password": {
+			routers.RouteDesc{Method: http.MethodPost, Controller: []controllers.ControllerFunc{wires.LoginChangePasswordController}},
+		},
+		"/password/forgot": {
+			routers.RouteDesc{Method: http.MethodPost, Controller: []controllers.ControllerFunc{wires.ForgotPasswordController}},
+		},
+		"/password/reset": {
+			routers.RouteDesc{Method: http.MethodPost, Controller: []controllers.ControllerFunc{wires.ResetPasswordController}},
+		},
+		"/email/verify": {
+			routers.RouteDesc{Method: http.MethodPost, Controller: []controllers.ControllerFunc{wires.VerifyEmailController}},
+		},
+		"/email/verification": {
+			routers.RouteDesc{Method: http.MethodPost, MiddleWare: []controllers.MiddlewareFunc{wires.AuthMiddleware, wires.DeactivatedAbortMiddleware},
+				Controller: []controllers.ControllerFunc{wires.SendEmailVerificationController}},
+		},
+		"/token/refresh": {
+			routers.RouteDesc{Method: http.MethodPost, Controller: []controllers.ControllerFunc{wires.RefreshTokenController}},
+		},
 		"/logout": {
-			routers.RouteDesc{Method: http.MethodPost, MiddleWare: []controllers.MiddlewareFunc{wires.AuthMiddleware},
+			routers.RouteDesc{Method: http.MethodPost, MiddleWare: []controllers.MiddlewareFunc{wires.AuthMiddleware, wires.ApiKeyDeniedMiddleware},
 				Controller: []controllers.ControllerFunc{wires.LogoutController}},
 		},
+		"/logout/all": {
+			routers.RouteDesc{Method: http.MethodPost, MiddleWare: []controllers.MiddlewareFunc{wires.AuthMiddleware, wires.ApiKeyDeniedMiddleware},
+				Controller: []controllers.ControllerFunc{wires.LogoutAllController}},
+		},
+		"/sessions": {
+			routers.RouteDesc{Method: http.MethodGet, MiddleWare: []controllers.MiddlewareFunc{wires.AuthMiddleware, wires.DeactivatedAbortMiddleware, wires.ApiKeyDeniedMiddleware},
+				Controller: []controllers.ControllerFunc{wires.SessionListController}},
+		},
+		"/sessions/:sessionID": {
+			routers.RouteDesc{Method: http.MethodDelete, MiddleWare: []controllers.MiddlewareFunc{wires.AuthMiddleware, wires.DeactivatedAbortMiddleware, wires.ApiKeyDeniedMiddleware},
+				Controller: []controllers.ControllerFunc{wires.RevokeSessionController}},
+		},
+		"/api_keys": {
+			routers.RouteDesc{Method: http.MethodGet, MiddleWare: []controllers.MiddlewareFunc{wires.AuthMiddleware, wires.DeactivatedAbortMiddleware, wires.ApiKeyDeniedMiddleware},
+				Controller: []controllers.ControllerFunc{wires.ApiKeyListController}},
+			routers.RouteDesc{Method: http.MethodPost, MiddleWare: []controllers.MiddlewareFunc{wires.AuthMiddleware, wires.DeactivatedAbortMiddleware, wires.ApiKeyDeniedMiddleware},
+				Controller: []controllers.ControllerFunc{wires.CreateApiKeyController}},
+		},
+		"/api_keys/:keyID": {
+			routers.RouteDesc{Method: http.MethodDelete, MiddleWare: []controllers.MiddlewareFunc{wires.AuthMiddleware, wires.DeactivatedAbortMiddleware, wires.ApiKeyDeniedMiddleware},
+				Controller: []controllers.ControllerFunc{wires.RevokeApiKeyController}},
+		},
+		"/mfa": {
+			routers.RouteDesc{Method: http.MethodGet, MiddleWare: []controllers.MiddlewareFunc{wires.AuthMiddleware, wires.DeactivatedAbortMiddleware, wires.ApiKeyDeniedMiddleware},
+				Controller: []controllers.ControllerFunc{wires.MFAStatusController}},
+		},
+		"/mfa/totp/enroll": {
+			routers.RouteDesc{Method: http.MethodPost, MiddleWare: []controllers.MiddlewareFunc{wires.AuthMiddleware, wires.DeactivatedAbortMiddleware, wires.ApiKeyDeniedMiddleware},
+				Controller: []controllers.ControllerFunc{wires.EnrollMFAController}},
+		},
+		"/mfa/totp/confirm": {
+			routers.RouteDesc{Method: http.MethodPost, MiddleWare: []controllers.MiddlewareFunc{wires.AuthMiddleware, wires.DeactivatedAbortMiddleware, wires.ApiKeyDeniedMiddleware},
+				Controller: []controllers.ControllerFunc{wires.ConfirmMFAController}},
+		},
+		"/mfa/totp/disable": {
+			routers.RouteDesc{Method: http.MethodPost, MiddleWare: []controllers.MiddlewareFunc{wires.AuthMiddleware, wires.DeactivatedAbortMiddleware, wires.ApiKeyDeniedMiddleware},
+				Controller: []controllers.ControllerFunc{wires.DisableMFAController}},
+		},
+		"/oidc/:provider/authorize": {
+			routers.RouteDesc{Method: http.MethodGet, Controller: []controllers.ControllerFunc{wires.OIDCAuthorizeController}},
+		},
+		"/oidc/:provider/callback": {
+			routers.RouteDesc{Method: http.MethodGet, Controller: []controllers.ControllerFunc{wires.OIDCCallbackController}},
+		},
+		"/oidc/:provider/link": {
+			routers.RouteDesc{Method: http.MethodPost, MiddleWare: []controllers.MiddlewareFunc{wires.AuthMiddleware, wires.DeactivatedAbortMiddleware, wires.ApiKeyDeniedMiddleware},
+				Controller: []controllers.ControllerFunc{wires.OIDCLinkController}},
+		},
+		"/identities": {
+			routers.RouteDesc{Method: http.MethodGet, MiddleWare: []controllers.MiddlewareFunc{wires.AuthMiddleware, wires.DeactivatedAbortMiddleware, wires.ApiKeyDeniedMiddleware},
+				Controller: []controllers.ControllerFunc{wires.IdentityListController}},
+		},
+		"/identities/:identityID": {
+			routers.RouteDesc{Method: http.MethodDelete, MiddleWare: []controllers.MiddlewareFunc{wires.AuthMiddleware, wires.DeactivatedAbortMiddleware, wires.ApiKeyDeniedMiddleware},
+				Controller: []controllers.ControllerFunc{wires.UnlinkIdentityController}},
+		},
 		"/current_user": {
 			routers.RouteDesc{Method: http.MethodGet, MiddleWare: []controllers.MiddlewareFunc{wires.AuthMiddleware, wires.DeactivatedAbortMiddleware},
 				Controller: []controllers.ControllerFunc{wires.CurrentUserController}},
@@ -0,0 +1,41 @@
+package router
+
+import (
+	"net/http"
+
+	"com.github.gin-common/common/controllers"
+
+	"com.github.gin-common/wires"
+
+	"com.github.gin-common/common/routers"
+)
+
+type OAuthRouter struct{}
+
+func (router OAuthRouter) GroupName() string {
+	return "/oauth"
+}
+
+func (router OAuthRouter) GroupConfig() map[string][]routers.RouteDesc {
+	return map[string][]routers.RouteDesc{
+		"/authorize": {
+			routers.RouteDesc{Method: http.MethodGet, MiddleWare: []controllers.MiddlewareFunc{wires.AuthMiddleware, wires.DeactivatedAbortMiddleware, wires.ApiKeyDeniedMiddleware},
+				Controller: []controllers.ControllerFunc{wires.OAuthAuthorizeInfoController}},
+			routers.RouteDesc{Method: http.MethodPost, MiddleWare: []controllers.MiddlewareFunc{wires.AuthMiddleware, wires.DeactivatedAbortMiddleware, wires.ApiKeyDeniedMiddleware},
+				Controller: []controllers.ControllerFunc{wires.OAuthAuthorizeController}},
+		},
+		"/token": {
+			routers.RouteDesc{Method: http.MethodPost, Controller: []controllers.ControllerFunc{wires.OAuthTokenController}},
+		},
+		"/introspect": {
+			routers.RouteDesc{Method: http.MethodPost, Controller: []controllers.ControllerFunc{wires.OAuthIntrospectController}},
+		},
+		"/revoke": {
+			routers.RouteDesc{Method: http.MethodPost, Controller: []controllers.ControllerFunc{wires.OAuthRevokeController}},
+		},
+	}
+}
+
+func (router OAuthRouter) GroupMiddleware() []controllers.MiddlewareFunc {
+	return []controllers.MiddlewareFunc{}
+}
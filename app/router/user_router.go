@@ -19,21 +19,25 @@ func (router UserRouter) GroupName() string {
 func (router UserRouter) GroupConfig() map[string][]routers.RouteDesc {
 	return map[string][]routers.RouteDesc{
 		"": {
-			{Method: http.MethodPost, Controller: []controllers.ControllerFunc{wires.CreateUserController}},
+			{Method: http.MethodGet, Controller: []controllers.ControllerFunc{wires.UserListController}, Permission: "user:read"},
+			{Method: http.MethodPost, Controller: []controllers.ControllerFunc{wires.CreateUserController}, Permission: "user:create"},
 		},
 		"/:userID": {
 			{Method: http.MethodGet, Controller: []controllers.ControllerFunc{wires.GetUserInfoController}},
-			{Method: http.MethodPut, Controller: []controllers.ControllerFunc{wires.UpdateUserController}},
-			{Method: http.MethodDelete, Controller: []controllers.ControllerFunc{wires.DeleteUserController}},
+			{Method: http.MethodPut, MiddleWare: []controllers.MiddlewareFunc{wires.ApiKeyDeniedMiddleware}, Controller: []controllers.ControllerFunc{wires.UpdateUserController}},
+			{Method: http.MethodDelete, Controller: []controllers.ControllerFunc{wires.DeleteUserController}, Permission: "user:delete"},
 		},
 		"/:userID/activate": {
-			{Method: http.MethodPatch, Controller: []controllers.ControllerFunc{wires.ActivateUserController}},
+			{Method: http.MethodPatch, Controller: []controllers.ControllerFunc{wires.ActivateUserController}, Permission: "user:activate"},
 		},
 		"/:userID/deactivate": {
-			{Method: http.MethodPatch, Controller: []controllers.ControllerFunc{wires.DeActivateUserController}},
+			{Method: http.MethodPatch, Controller: []controllers.ControllerFunc{wires.DeActivateUserController}, Permission: "user:deactivate"},
+		},
+		"/:userID/restore": {
+			{Method: http.MethodPatch, Controller: []controllers.ControllerFunc{wires.RestoreUserController}, Permission: "user:restore"},
 		},
 		"/:userID/changePass": {
-			{Method: http.MethodPost, Controller: []controllers.ControllerFunc{wires.ChangePasswordController}},
+			{Method: http.MethodPost, MiddleWare: []controllers.MiddlewareFunc{wires.ApiKeyDeniedMiddleware}, Controller: []controllers.ControllerFunc{wires.ChangePasswordController}},
 		},
 	}
 }
@@ -42,5 +46,6 @@ func (router UserRouter) GroupMiddleware() []controllers.MiddlewareFunc {
 	return []controllers.MiddlewareFunc{
 		wires.AuthMiddleware,
 		wires.DeactivatedAbortMiddleware,
+		wires.PermissionMiddleware,
 	}
 }
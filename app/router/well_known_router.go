@@ -0,0 +1,29 @@
+package router
+
+import (
+	"net/http"
+
+	"com.github.gin-common/common/controllers"
+
+	"com.github.gin-common/wires"
+
+	"com.github.gin-common/common/routers"
+)
+
+type WellKnownRouter struct{}
+
+func (router WellKnownRouter) GroupName() string {
+	return "/.well-known"
+}
+
+func (router WellKnownRouter) GroupConfig() map[string][]routers.RouteDesc {
+	return map[string][]routers.RouteDesc{
+		"/jwks.json": {
+			routers.RouteDesc{Method: http.MethodGet, Controller: []controllers.ControllerFunc{wires.JWKSController}},
+		},
+	}
+}
+
+func (router WellKnownRouter) GroupMiddleware() []controllers.MiddlewareFunc {
+	return []controllers.MiddlewareFunc{}
+}
@@ -0,0 +1,18 @@
+package service
+
+import (
+	"time"
+
+	"com.github.gin-common/app/model"
+)
+
+type ApiKeyService interface {
+	// 创建API Key，明文key仅在创建时返回
+	CreateApiKey(userID uint, name string, scopes []string, expiresAt *time.Time) (apiKey *model.ApiKey, key string, err error)
+	// 获取用户的API Key
+	ListApiKeys(userID uint) ([]model.ApiKey, error)
+	// 删除用户的API Key
+	RevokeApiKey(userID uint, id uint) error
+	// 校验明文key
+	Authenticate(key string) (*model.ApiKey, error)
+}
@@ -0,0 +1,16 @@
+package service
+
+import (
+	"net/url"
+
+	"com.github.gin-common/common/audit"
+	"com.github.gin-common/common/resp"
+)
+
+// 审计日志，数据变更由gorm回调自动记录(见audit.Auditable)，认证等事件通过Record记录
+type AuditService interface {
+	// 记录事件，未指定操作人时取自请求上下文，记录失败只写日志
+	Record(log *audit.AuditLog) error
+	// 按查询参数(filter/sort/page)分页获取审计日志
+	ListAuditLogs(values url.Values) ([]audit.AuditLog, resp.Pagination, error)
+}
@@ -1,10 +1,69 @@
 package service
 
+import "time"
+
 type TokenString string
 
+type TokenPair struct {
+	AccessToken      TokenString `json:"access_token"`
+	RefreshToken     TokenString `json:"refresh_token"`
+	ExpiresIn        int         `json:"expires_in"`
+	RefreshExpiresIn int         `json:"refresh_expires_in"`
+}
+
+// 存放于redis accessToken:<uuid> 中的会话数据
+type AccessTokenData struct {
+	UserID   uint   `json:"userId"`
+	FamilyID string `json:"familyId"`
+}
+
+// 登录时的客户端信息
+type ClientInfo struct {
+	IP        string
+	UserAgent string
+	Device    string
+}
+
+// 登录会话，一次登录(及其后续refresh)对应一个会话
+type Session struct {
+	ID          string     `json:"id"`
+	UserID      uint       `json:"user_id"`
+	Device      string     `json:"device"`
+	IP          string     `json:"ip"`
+	UserAgent   string     `json:"user_agent"`
+	LoginAt     time.Time  `json:"login_at"`
+	RefreshedAt *time.Time `json:"refreshed_at"`
+}
+
+// 登录结果，开启两步验证时不直接返回token，而是返回MFA challenge token；
+// 密码过期时返回修改密码的challenge token
+type LoginResult struct {
+	Tokens                  *TokenPair
+	MFARequired             bool
+	MFAToken                string
+	MFAExpiresIn            int
+	PasswordChangeRequired  bool
+	PasswordChangeToken     string
+	PasswordChangeExpiresIn int
+}
+
 type AuthService interface {
-	// 登录
-	Login(username string, password string) (token TokenString, err error)
+	// 登录(密码校验)
+	Login(username string, password string, client ClientInfo) (result *LoginResult, err error)
+	// 使用MFA challenge token及动态码(或恢复码)完成登录
+	LoginMFA(mfaToken string, code string) (tokens *TokenPair, err error)
+	// 密码过期时，使用修改密码challenge token设置新密码并继续登录
+	LoginChangePassword(passwordChangeToken string, newPassword string) (result *LoginResult, err error)
+	// 已通过外部身份认证的用户登录(开启两步验证时仍需完成MFA)
+	LoginExternal(userID uint, client ClientInfo) (result *LoginResult, err error)
 	// 登出
 	Logout(sessionID string) error
+	// 使用refresh token换取新的token(refresh token同时轮换)
+	RefreshToken(refreshToken string) (tokens *TokenPair, err error)
+	// 获取用户的所有有效会话
+	ListSessions(userID uint) ([]Session, error)
+	// 注销用户的指定会话
+	RevokeSession(userID uint, sessionID string) error
+	// 注销用户的全部会话, exceptSessionIDs中的会话会被保留
+	RevokeAllSessions(userID uint, exceptSessionIDs ...string) error
 }
@@ -0,0 +1,116 @@
+package impl
+
+import (
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/hex"
+	"errors"
+	"strings"
+	"time"
+
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+
+	"com.github.gin-common/app/exception"
+	"com.github.gin-common/app/model"
+	"com.github.gin-common/common/exceptions"
+	"com.github.gin-common/util"
+)
+
+// API Key格式: gck_<prefix>_<secret>，prefix用于查找，secret只保存sha256摘要
+const apiKeyScheme = "gck"
+
+type ApiKeyServiceImpl struct {
+	session *gorm.DB
+	logger  zap.Logger
+}
+
+func (service *ApiKeyServiceImpl) Init(session *gorm.DB, logger zap.Logger) {
+	service.session = session
+	service.logger = logger
+}
+
+func randomHex(n int) (string, error) {
+	b := make([]byte, n)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
+
+func parseApiKey(key string) (prefix string, secret string, ok bool) {
+	parts := strings.SplitN(key, "_", 3)
+	if len(parts) != 3 || parts[0] != apiKeyScheme || parts[1] == "" || parts[2] == "" {
+		return "", "", false
+	}
+	return parts[1], parts[2], true
+}
+
+func (service *ApiKeyServiceImpl) CreateApiKey(userID uint, name string, scopes []string, expiresAt *time.Time) (*model.ApiKey, string, error) {
+	prefix, err := randomHex(6)
+	if err != nil {
+		return nil, "", exceptions.GetDefinedErrors(exception.ApiKeyCreateFailed)
+	}
+	secret, err := util.RandomToken(32)
+	if err != nil {
+		return nil, "", exceptions.GetDefinedErrors(exception.ApiKeyCreateFailed)
+	}
+	apiKey := &model.ApiKey{
+		UserID:    userID,
+		Name:      name,
+		Prefix:    prefix,
+		KeyHash:   util.SHA256Hex(secret),
+		Scopes:    strings.Join(scopes, ","),
+		ExpiresAt: expiresAt,
+	}
+	if result := service.session.Create(apiKey); result.Error != nil {
+		service.logger.Error(result.Error.Error())
+		return nil, "", exceptions.GetDefinedErrors(exception.ApiKeyCreateFailed)
+	}
+	return apiKey, strings.Join([]string{apiKeyScheme, prefix, secret}, "_"), nil
+}
+
+func (service *ApiKeyServiceImpl) ListApiKeys(userID uint) ([]model.ApiKey, error) {
+	var apiKeys []model.ApiKey
+	if result := service.session.Where("user_id = ?", userID).Order("id desc").Find(&apiKeys); result.Error != nil {
+		return nil, result.Error
+	}
+	return apiKeys, nil
+}
+
+func (service *ApiKeyServiceImpl) RevokeApiKey(userID uint, id uint) error {
+	result := service.session.Where("id = ? AND user_id = ?", id, userID).Delete(&model.ApiKey{})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return exceptions.GetDefinedErrors(exception.ApiKeyNotFound)
+	}
+	return nil
+}
+
+func (service *ApiKeyServiceImpl) Authenticate(key string) (*model.ApiKey, error) {
+	prefix, secret, ok := parseApiKey(key)
+	if !ok {
+		return nil, exceptions.GetDefinedErrors(exception.ApiKeyInvalid)
+	}
+	apiKey := &model.ApiKey{}
+	if result := service.session.Where("prefix = ?", prefix).First(apiKey); result.Error != nil {
+		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+			return nil, exceptions.GetDefinedErrors(exception.ApiKeyInvalid)
+		}
+		return nil, result.Error
+	}
+	if subtle.ConstantTimeCompare([]byte(apiKey.KeyHash), []byte(util.SHA256Hex(secret))) != 1 {
+		return nil, exceptions.GetDefinedErrors(exception.ApiKeyInvalid)
+	}
+	if apiKey.Expired() {
+		return nil, exceptions.GetDefinedErrors(exception.ApiKeyExpired)
+	}
+	now := time.Now()
+	if result := service.session.Model(apiKey).UpdateColumn("last_used_at", now); result.Error != nil {
+		service.logger.Warn(result.Error.Error())
+	}
+	apiKey.LastUsedAt = &now
+	return apiKey, nil
+}
@@ -0,0 +1,59 @@
+package impl
+
+import (
+	"net/url"
+
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+
+	"com.github.gin-common/common/audit"
+	"com.github.gin-common/common/models"
+	"com.github.gin-common/common/resp"
+)
+
+var auditLogQuerySchema = models.QuerySchema{
+	Fields: map[string]models.QueryField{
+		"id":          {Type: models.FieldInt, Sortable: true},
+		"actor_id":    {Type: models.FieldInt, Filterable: true},
+		"actor_name":  {Type: models.FieldString, Filterable: true},
+		"action":      {Type: models.FieldString, Filterable: true, Operators: []string{models.OpEq, models.OpIn}},
+		"target_type": {Type: models.FieldString, Filterable: true, Operators: []string{models.OpEq, models.OpIn}},
+		"target_id":   {Type: models.FieldString, Filterable: true, Operators: []string{models.OpEq, models.OpIn}},
+		"ip":          {Type: models.FieldString, Filterable: true, Operators: []string{models.OpEq}},
+		"request_id":  {Type: models.FieldString, Filterable: true, Operators: []string{models.OpEq}},
+		"created_at":  {Type: models.FieldTime, Filterable: true, Sortable: true},
+	},
+	DefaultSort:     "-id",
+	DefaultPageSize: 20,
+	MaxPageSize:     100,
+}
+
+type AuditServiceImpl struct {
+	session *gorm.DB
+	logger  zap.Logger
+	logs    *models.Repository
+}
+
+func (service *AuditServiceImpl) Init(session *gorm.DB, logger zap.Logger) {
+	service.session = session
+	service.logger = logger
+	service.logs = models.NewRepository(session, &audit.AuditLog{})
+}
+
+func (service *AuditServiceImpl) Record(log *audit.AuditLog) error {
+	if err := audit.Record(service.session, log); err != nil {
+		service.logger.Error(err.Error())
+		return err
+	}
+	return nil
+}
+
+func (service *AuditServiceImpl) ListAuditLogs(values url.Values) ([]audit.AuditLog, resp.Pagination, error) {
+	query, err := auditLogQuerySchema.Parse(values)
+	if err != nil {
+		return nil, resp.Pagination{}, err
+	}
+	var logs []audit.AuditLog
+	pagination, err := service.logs.List(query, &logs)
+	return logs, pagination, err
+}
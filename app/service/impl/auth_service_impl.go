@@ -12,6 +12,7 @@ import (
 
 	"com.github.gin-common/app/exception"
 	"com.github.gin-common/app/service"
+	"com.github.gin-common/common/audit"
 	"com.github.gin-common/common/exceptions"
 	"com.github.gin-common/tools/jwt_tool"
 
@@ -21,91 +22,515 @@ import (
 )
 
 type AuthServiceImpl struct {
-	ctx         context.Context
-	rdb         *redis.Client
-	userService service.UserService
+	ctx          context.Context
+	rdb          *redis.Client
+	userService  service.UserService
+	mfaService   service.MFAService
+	loginGuard   service.LoginGuardService
+	auditService service.AuditService
+	sessionStore *SessionStore
 }
 
-func (authService *AuthServiceImpl) Init(ctx context.Context, rdb *redis.Client, userService service.UserService) {
+func (authService *AuthServiceImpl) Init(ctx context.Context, rdb *redis.Client, userService service.UserService, mfaService service.MFAService, loginGuard service.LoginGuardService, auditService service.AuditService) {
 	authService.ctx = ctx
 	authService.rdb = rdb
 	authService.userService = userService
+	authService.mfaService = mfaService
+	authService.loginGuard = loginGuard
+	authService.auditService = auditService
+	authService.sessionStore = &SessionStore{}
+	authService.sessionStore.Init(rdb, ctx)
 }
 
 type Option struct {
 }
 
-func (authService *AuthServiceImpl) Login(username string, password string) (token service.TokenString, err error) {
+// refresh token 对应的数据
+type refreshTokenData struct {
+	UserID    uint   `json:"userId"`
+	FamilyID  string `json:"familyId"`
+	TokenUUID string `json:"tokenUUID"`
+}
+
+// 密码校验通过、等待两步验证或修改过期密码的登录
+type loginChallengeData struct {
+	UserID   uint               `json:"userId"`
+	Username string             `json:"username"`
+	Client   service.ClientInfo `json:"client"`
+}
+
+// 两步验证最多尝试次数
+const mfaChallengeMaxAttempts = 5
+
+func mfaChallengeKey(tokenHash string) string {
+	return fmt.Sprintf("mfaChallenge:%s", tokenHash)
+}
+
+func mfaChallengeAttemptsKey(tokenHash string) string {
+	return fmt.Sprintf("mfaChallengeAttempts:%s", tokenHash)
+}
+
+func getMFAChallengeExpire() (int, error) {
+	return strconv.Atoi(util.GetDefaultEnv("MFA_CHALLENGE_EXPIRE", "300"))
+}
+
+func passwordChangeKey(tokenHash string) string {
+	return fmt.Sprintf("passwordChange:%s", tokenHash)
+}
+
+// 认证事件的审计动作
+const (
+	auditActionLogin       = "login"
+	auditActionLoginFailed = "login_failed"
+	auditActionLogout      = "logout"
+)
+
+// 记录认证事件，userID为0时(用户不存在)只记录用户名，记录失败不影响认证结果
+func (authService *AuthServiceImpl) auditAuth(action string, userID uint, username string, detail string) {
+	log := &audit.AuditLog{Action: action, ActorName: username, TargetType: "user", Detail: detail}
+	if userID != 0 {
+		log.ActorID = &userID
+		log.TargetID = strconv.FormatUint(uint64(userID), 10)
+		if username == "" {
+			if user, e := authService.userService.GetUserInfoById(userID); e == nil {
+				log.ActorName = user.Username
+			}
+		}
+	}
+	_ = authService.auditService.Record(log)
+}
+
+// 记录失败登录，触发锁定时返回AccountLocked
+func (authService *AuthServiceImpl) loginFailed(username string, user *model.User, client service.ClientInfo) error {
+	err := authService.loginGuard.RecordFailure(username, client.IP)
+	if err == nil {
+		err = exceptions.GetDefinedErrors(exception.UserNameOrPassInvalid)
+	}
+	var userID uint
+	if user != nil {
+		userID = user.ID
+	}
+	authService.auditAuth(auditActionLoginFailed, userID, username, err.Error())
+	return err
+}
+
+func (authService *AuthServiceImpl) Login(username string, password string, client service.ClientInfo) (result *service.LoginResult, err error) {
+	if err = authService.loginGuard.Check(username, client.IP); err != nil {
+		authService.auditAuth(auditActionLoginFailed, 0, username, err.Error())
+		return
+	}
 	var user *model.User
 	user, err = authService.userService.GetUserInfoByUserName(username)
 	if err != nil {
-		err = exceptions.GetDefinedErrors(exception.UserNameOrPassInvalid)
+		err = authService.loginFailed(username, nil, client)
 		return
 	}
 	if user.ActivateStatus == false {
 		err = exceptions.GetDefinedErrors(exception.UserDeactivated)
+		authService.auditAuth(auditActionLoginFailed, user.ID, user.Username, err.Error())
+		return
+	}
+	if !user.CheckPass(password) {
+		err = authService.loginFailed(username, user, client)
 		return
 	}
+	if user.NeedsRehash() {
+		// 升级失败不影响登录，下次登录时重试
+		_ = authService.userService.RehashPassword(user, password)
+	}
 
-	if user.CheckPass(password) {
-		// 生成token
-		var e error
-		token, e = authService.saveAuth(user.ID)
-		if e != nil {
-			err = exceptions.GetDefinedErrors(exception.LoginFailed)
-			return
+	var expired bool
+	expired, err = authService.userService.PasswordExpired(user)
+	if err != nil {
+		return
+	}
+	if expired {
+		return authService.createPasswordChangeChallenge(user.ID, user.Username, client)
+	}
+	return authService.completeLogin(user.ID, user.Username, client)
+}
+
+// 创建短期有效的修改密码challenge token
+func (authService *AuthServiceImpl) createPasswordChangeChallenge(userID uint, username string, client service.ClientInfo) (*service.LoginResult, error) {
+	expire, err := strconv.Atoi(util.GetDefaultEnv("PASSWORD_CHANGE_CHALLENGE_EXPIRE", "600"))
+	if err != nil {
+		return nil, err
+	}
+	token, err := util.RandomToken(32)
+	if err != nil {
+		return nil, exceptions.GetDefinedErrors(exception.LoginFailed)
+	}
+	value, err := json.Marshal(loginChallengeData{UserID: userID, Username: username, Client: client})
+	if err != nil {
+		return nil, err
+	}
+	err = authService.rdb.Set(authService.ctx, passwordChangeKey(util.SHA256Hex(token)), value, time.Duration(expire)*time.Second).Err()
+	if err != nil {
+		return nil, exceptions.GetDefinedErrors(exception.LoginFailed)
+	}
+	return &service.LoginResult{PasswordChangeRequired: true, PasswordChangeToken: token, PasswordChangeExpiresIn: expire}, nil
+}
+
+func (authService *AuthServiceImpl) LoginChangePassword(passwordChangeToken string, newPassword string) (result *service.LoginResult, err error) {
+	key := passwordChangeKey(util.SHA256Hex(passwordChangeToken))
+	var data loginChallengeData
+	// 成功登录由createSession记录，challenge无效时用户未知
+	defer func() {
+		if err != nil {
+			authService.auditAuth(auditActionLoginFailed, data.UserID, data.Username, "password change: "+err.Error())
 		}
-	} else {
-		err = exceptions.GetDefinedErrors(exception.UserNameOrPassInvalid)
+	}()
+	var val string
+	val, err = authService.rdb.Get(authService.ctx, key).Result()
+	if err != nil {
+		if err == redis.Nil {
+			err = exceptions.GetDefinedErrors(exception.PasswordChangeChallengeInvalid)
+		}
+		return
+	}
+	if err = json.Unmarshal([]byte(val), &data); err != nil {
+		return
+	}
+	// 新密码不符合策略时challenge仍然有效，可重新提交
+	if err = authService.userService.SetPassword(data.UserID, newPassword); err != nil {
+		return
+	}
+	var deleted int64
+	deleted, err = authService.rdb.Del(authService.ctx, key).Result()
+	if err != nil {
+		return
+	}
+	if deleted == 0 {
+		err = exceptions.GetDefinedErrors(exception.PasswordChangeChallengeInvalid)
+		return
+	}
+	return authService.completeLogin(data.UserID, data.Username, data.Client)
+}
+
+func (authService *AuthServiceImpl) LoginExternal(userID uint, client service.ClientInfo) (result *service.LoginResult, err error) {
+	var user *model.User
+	user, err = authService.userService.GetUserInfoById(userID)
+	if err != nil {
+		return
+	}
+	if user.ActivateStatus == false {
+		err = exceptions.GetDefinedErrors(exception.UserDeactivated)
+		return
+	}
+	return authService.completeLogin(user.ID, user.Username, client)
+}
+
+// 认证通过后，开启两步验证的用户返回MFA challenge，否则直接创建会话
+// 登录失败记录在签发token后才清除，两步验证失败仍计入失败次数
+func (authService *AuthServiceImpl) completeLogin(userID uint, username string, client service.ClientInfo) (*service.LoginResult, error) {
+	mfaEnabled, err := authService.mfaService.IsEnabled(userID)
+	if err != nil {
+		return nil, err
+	}
+	if mfaEnabled {
+		return authService.createMFAChallenge(userID, username, client)
+	}
+	tokens, err := authService.createSession(userID, client)
+	if err != nil {
+		return nil, err
+	}
+	authService.loginGuard.RecordSuccess(username, client.IP)
+	return &service.LoginResult{Tokens: tokens}, nil
+}
+
+// 创建短期有效的MFA challenge token
+func (authService *AuthServiceImpl) createMFAChallenge(userID uint, username string, client service.ClientInfo) (*service.LoginResult, error) {
+	expire, err := getMFAChallengeExpire()
+	if err != nil {
+		return nil, err
+	}
+	mfaToken, err := util.RandomToken(32)
+	if err != nil {
+		return nil, exceptions.GetDefinedErrors(exception.LoginFailed)
+	}
+	value, err := json.Marshal(loginChallengeData{UserID: userID, Username: username, Client: client})
+	if err != nil {
+		return nil, err
+	}
+	err = authService.rdb.Set(authService.ctx, mfaChallengeKey(util.SHA256Hex(mfaToken)), value, time.Duration(expire)*time.Second).Err()
+	if err != nil {
+		return nil, exceptions.GetDefinedErrors(exception.LoginFailed)
+	}
+	return &service.LoginResult{MFARequired: true, MFAToken: mfaToken, MFAExpiresIn: expire}, nil
+}
+
+func (authService *AuthServiceImpl) LoginMFA(mfaToken string, code string) (tokens *service.TokenPair, err error) {
+	tokenHash := util.SHA256Hex(mfaToken)
+	key := mfaChallengeKey(tokenHash)
+	attemptsKey := mfaChallengeAttemptsKey(tokenHash)
+	var data loginChallengeData
+	// 成功登录由createSession记录，challenge无效时用户未知
+	defer func() {
+		if err != nil {
+			authService.auditAuth(auditActionLoginFailed, data.UserID, data.Username, "mfa: "+err.Error())
+		}
+	}()
+	var val string
+	val, err = authService.rdb.Get(authService.ctx, key).Result()
+	if err != nil {
+		if err == redis.Nil {
+			err = exceptions.GetDefinedErrors(exception.MFAChallengeInvalid)
+		}
+		return
+	}
+	if err = json.Unmarshal([]byte(val), &data); err != nil {
 		return
 	}
+	// 用户或IP已被锁定时不再校验动态码
+	if err = authService.loginGuard.Check(data.Username, data.Client.IP); err != nil {
+		return
+	}
+	// 校验前原子递增尝试次数，并发提交也不会超过最大尝试次数，超过后challenge失效
+	var attempts int64
+	attempts, err = authService.incrMFAChallengeAttempts(attemptsKey)
+	if err != nil {
+		return
+	}
+	if attempts > mfaChallengeMaxAttempts {
+		authService.rdb.Del(authService.ctx, key, attemptsKey)
+		err = exceptions.GetDefinedErrors(exception.MFAChallengeInvalid)
+		return
+	}
+	if err = authService.mfaService.Verify(data.UserID, code); err != nil {
+		// 动态码错误同样计入登录失败，达到阈值时锁定
+		if e := authService.loginGuard.RecordFailure(data.Username, data.Client.IP); e != nil {
+			err = e
+			authService.rdb.Del(authService.ctx, key, attemptsKey)
+		}
+		return
+	}
+	// challenge只能使用一次
+	var deleted int64
+	deleted, err = authService.rdb.Del(authService.ctx, key).Result()
+	if err != nil {
+		return
+	}
+	authService.rdb.Del(authService.ctx, attemptsKey)
+	if deleted == 0 {
+		err = exceptions.GetDefinedErrors(exception.MFAChallengeInvalid)
+		return
+	}
+	tokens, err = authService.createSession(data.UserID, data.Client)
+	if err != nil {
+		return
+	}
+	authService.loginGuard.RecordSuccess(data.Username, data.Client.IP)
 	return
 }
 
-func (authService *AuthServiceImpl) saveAuth(userId uint) (service.TokenString, error) {
-	// 创建token,并存入redis
-	tokenUUID := uuid.New().String()
-	claims := jwt.MapClaims{
-		"tokenUUID": tokenUUID,
+// 尝试次数与challenge有效期相同
+func (authService *AuthServiceImpl) incrMFAChallengeAttempts(attemptsKey string) (int64, error) {
+	expire, err := getMFAChallengeExpire()
+	if err != nil {
+		return 0, err
+	}
+	var attempts *redis.IntCmd
+	_, err = authService.rdb.TxPipelined(authService.ctx, func(pipe redis.Pipeliner) error {
+		attempts = pipe.Incr(authService.ctx, attemptsKey)
+		pipe.Expire(authService.ctx, attemptsKey, time.Duration(expire)*time.Second)
+		return nil
+	})
+	if err != nil {
+		return 0, err
 	}
-	tokenExpireSecond, err := strconv.Atoi(util.GetDefaultEnv("ACCESS_TOKEN_EXPIRE", strconv.Itoa(2*60*60)))
+	return attempts.Val(), nil
+}
+
+// 生成token, 每次登录创建一个新的token family(即会话)
+func (authService *AuthServiceImpl) createSession(userID uint, client service.ClientInfo) (*service.TokenPair, error) {
+	familyID := uuid.New().String()
+	tokens, err := authService.saveAuth(userID, familyID)
 	if err != nil {
-		return "", err
+		return nil, exceptions.GetDefinedErrors(exception.LoginFailed)
 	}
-	var tokenInfo *jwt_tool.TokenInfo
-	tokenInfo, err = jwt_tool.CreateToken(claims, tokenExpireSecond)
+	err = authService.sessionStore.SaveSession(service.Session{
+		ID:        familyID,
+		UserID:    userID,
+		Device:    client.Device,
+		IP:        client.IP,
+		UserAgent: client.UserAgent,
+		LoginAt:   time.Now(),
+	})
 	if err != nil {
-		return "", err
+		_ = authService.sessionStore.RevokeFamily(familyID)
+		return nil, exceptions.GetDefinedErrors(exception.LoginFailed)
+	}
+	authService.auditAuth(auditActionLogin, userID, "", "session: "+familyID)
+	return tokens, nil
+}
+
+func getTokenExpire() (accessExpire int, refreshExpire int, err error) {
+	accessExpire, err = strconv.Atoi(util.GetDefaultEnv("ACCESS_TOKEN_EXPIRE", strconv.Itoa(2*60*60)))
+	if err != nil {
+		return
+	}
+	refreshExpire, err = strconv.Atoi(util.GetDefaultEnv("REFRESH_TOKEN_EXPIRE", strconv.Itoa(7*24*60*60)))
+	return
+}
+
+func (authService *AuthServiceImpl) saveAuth(userId uint, familyID string) (*service.TokenPair, error) {
+	// 创建access token及refresh token,并存入redis
+	accessExpire, refreshExpire, err := getTokenExpire()
+	if err != nil {
+		return nil, err
 	}
 	// use UUID4 to create uuid
+	tokenUUID := uuid.New().String()
+	claims := jwt.MapClaims{
+		"sub": strconv.FormatUint(uint64(userId), 10),
+		"jti": tokenUUID,
+		"sid": familyID,
+	}
+	var tokenInfo *jwt_tool.TokenInfo
+	tokenInfo, err = jwt_tool.CreateToken(claims, accessExpire)
+	if err != nil {
+		return nil, err
+	}
 	expiredAt := time.Unix(tokenInfo.ExpiredAt, 0)
-	v, e := json.Marshal(map[string]uint{
-		"userId": userId,
+	accessValue, err := json.Marshal(service.AccessTokenData{
+		UserID:   userId,
+		FamilyID: familyID,
 	})
-	if e != nil {
-		return "", e
+	if err != nil {
+		return nil, err
 	}
 
-	if err := authService.rdb.Set(authService.ctx, fmt.Sprintf("accessToken:%s", tokenUUID), v, expiredAt.Sub(time.Now())).Err(); err != nil {
-		return "", err
+	// refresh token 为随机字符串，redis中只保存其摘要
+	var refreshToken string
+	refreshToken, err = util.RandomToken(32)
+	if err != nil {
+		return nil, err
 	}
-	return service.TokenString(tokenInfo.Token), err
+	refreshHash := util.SHA256Hex(refreshToken)
+	refreshValue, err := json.Marshal(refreshTokenData{
+		UserID:    userId,
+		FamilyID:  familyID,
+		TokenUUID: tokenUUID,
+	})
+	if err != nil {
+		return nil, err
+	}
+	refreshDuration := time.Duration(refreshExpire) * time.Second
+
+	_, err = authService.rdb.TxPipelined(authService.ctx, func(pipe redis.Pipeliner) error {
+		pipe.Set(authService.ctx, accessTokenKey(tokenUUID), accessValue, expiredAt.Sub(time.Now()))
+		pipe.Set(authService.ctx, refreshTokenKey(refreshHash), refreshValue, refreshDuration)
+		pipe.SAdd(authService.ctx, tokenFamilyKey(familyID), accessTokenKey(tokenUUID), refreshTokenKey(refreshHash), refreshTokenUsedKey(refreshHash))
+		pipe.Expire(authService.ctx, tokenFamilyKey(familyID), refreshDuration)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &service.TokenPair{
+		AccessToken:      service.TokenString(tokenInfo.Token),
+		RefreshToken:     service.TokenString(refreshToken),
+		ExpiresIn:        accessExpire,
+		RefreshExpiresIn: refreshExpire,
+	}, nil
 }
 
-func (authService *AuthServiceImpl) Logout(sessionID string) error {
-	_, err := authService.rdb.Get(authService.ctx, fmt.Sprintf("accessToken:%s", sessionID)).Result()
+func (authService *AuthServiceImpl) RefreshToken(refreshToken string) (tokens *service.TokenPair, err error) {
+	refreshHash := util.SHA256Hex(refreshToken)
+	var val string
+	val, err = authService.rdb.Get(authService.ctx, refreshTokenKey(refreshHash)).Result()
 	if err != nil {
 		if err == redis.Nil {
-			return nil
+			err = exceptions.GetDefinedErrors(exception.RefreshTokenInvalid)
 		}
-		return err
+		return
+	}
+	var data refreshTokenData
+	if err = json.Unmarshal([]byte(val), &data); err != nil {
+		return
 	}
-	_, err = authService.rdb.Del(authService.ctx, fmt.Sprintf("accessToken:%s", sessionID)).Result()
+
+	// 每个refresh token只能使用一次，再次使用视为泄露，注销整个token family
+	var ttl time.Duration
+	ttl, err = authService.rdb.TTL(authService.ctx, refreshTokenKey(refreshHash)).Result()
+	if err != nil {
+		return
+	}
+	if ttl <= 0 {
+		ttl = time.Minute
+	}
+	var first bool
+	first, err = authService.rdb.SetNX(authService.ctx, refreshTokenUsedKey(refreshHash), 1, ttl).Result()
+	if err != nil {
+		return
+	}
+	if !first {
+		if err = authService.sessionStore.RevokeFamily(data.FamilyID); err != nil {
+			return
+		}
+		err = exceptions.GetDefinedErrors(exception.RefreshTokenReused)
+		return
+	}
+
+	var user *model.User
+	user, err = authService.userService.GetUserInfoById(data.UserID)
+	if err != nil {
+		return
+	}
+	if user.ActivateStatus == false {
+		err = exceptions.GetDefinedErrors(exception.UserDeactivated)
+		return
+	}
+
+	// 轮换：旧的access token失效，签发新的token对
+	if err = authService.sessionStore.RevokeAccessToken(data.TokenUUID); err != nil {
+		return
+	}
+	var e error
+	tokens, e = authService.saveAuth(data.UserID, data.FamilyID)
+	if e != nil {
+		err = exceptions.GetDefinedErrors(exception.LoginFailed)
+		return
+	}
+	authService.sessionStore.TouchSession(data.UserID, data.FamilyID)
+	return
+}
+
+func (authService *AuthServiceImpl) ListSessions(userID uint) ([]service.Session, error) {
+	return authService.sessionStore.ListSessions(userID)
+}
+
+func (authService *AuthServiceImpl) RevokeSession(userID uint, sessionID string) error {
+	return authService.sessionStore.RevokeSession(userID, sessionID)
+}
+
+func (authService *AuthServiceImpl) RevokeAllSessions(userID uint, exceptSessionIDs ...string) error {
+	return authService.sessionStore.RevokeAllSessions(userID, exceptSessionIDs...)
+}
+
+func (authService *AuthServiceImpl) Logout(sessionID string) error {
+	val, err := authService.rdb.Get(authService.ctx, accessTokenKey(sessionID)).Result()
 	if err != nil {
 		if err == redis.Nil {
 			return nil
 		}
 		return err
 	}
+	var data service.AccessTokenData
+	if err = json.Unmarshal([]byte(val), &data); err == nil && data.FamilyID != "" {
+		// 登出时同时注销该次登录的refresh token
+		if err = authService.sessionStore.RevokeFamily(data.FamilyID); err != nil {
+			return err
+		}
+		if err = authService.rdb.HDel(authService.ctx, userSessionsKey(data.UserID), data.FamilyID).Err(); err != nil {
+			return err
+		}
+		authService.auditAuth(auditActionLogout, data.UserID, "", "session: "+data.FamilyID)
+		return nil
+	}
+	if err = authService.sessionStore.RevokeAccessToken(sessionID); err != nil {
+		return err
+	}
+	authService.auditAuth(auditActionLogout, data.UserID, "", "")
 	return nil
 }
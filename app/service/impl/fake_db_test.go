@@ -0,0 +1,212 @@
+package impl
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"io"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+
+	"gorm.io/driver/mysql"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+// 测试用的内存数据库，只支持gorm生成的单表等值查询、count及单行插入
+
+var (
+	insertPattern = regexp.MustCompile("^INSERT INTO `(\\w+)` \\((.+)\\) VALUES \\((.+)\\)$")
+	selectPattern = regexp.MustCompile("^SELECT (.+?) FROM `(\\w+)`(?: WHERE (.+?))?(?: ORDER BY .+?)?(?: LIMIT (\\d+))?$")
+	equalPattern  = regexp.MustCompile("^`?(?:\\w+`?\\.`?)?(\\w+)`? = \\?$")
+	isNullPattern = regexp.MustCompile("^`?(?:\\w+`?\\.`?)?(\\w+)`? IS NULL$")
+)
+
+type fakeRow map[string]driver.Value
+
+type fakeDB struct {
+	mu     sync.Mutex
+	tables map[string][]fakeRow
+}
+
+func newFakeDB(t *testing.T) *gorm.DB {
+	t.Helper()
+	store := &fakeDB{tables: map[string][]fakeRow{}}
+	db, err := gorm.Open(mysql.New(mysql.Config{Conn: sql.OpenDB(store), SkipInitializeWithVersion: true}), &gorm.Config{
+		DisableAutomaticPing: true,
+		Logger:               logger.Default.LogMode(logger.Silent),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return db
+}
+
+func (store *fakeDB) Connect(context.Context) (driver.Conn, error) {
+	return &fakeConn{store: store}, nil
+}
+
+func (store *fakeDB) Driver() driver.Driver {
+	return nil
+}
+
+func (store *fakeDB) insert(query string, args []driver.NamedValue) (driver.Result, error) {
+	match := insertPattern.FindStringSubmatch(query)
+	if match == nil {
+		return nil, fmt.Errorf("fake db: unsupported exec %q", query)
+	}
+	columns := strings.Split(match[2], ",")
+	if len(columns) != len(args) {
+		return nil, fmt.Errorf("fake db: only single row inserts are supported: %q", query)
+	}
+	store.mu.Lock()
+	defer store.mu.Unlock()
+	id := int64(len(store.tables[match[1]]) + 1)
+	row := fakeRow{"id": id}
+	for i, column := range columns {
+		row[strings.Trim(column, "`")] = args[i].Value
+	}
+	store.tables[match[1]] = append(store.tables[match[1]], row)
+	return fakeResult(id), nil
+}
+
+func (store *fakeDB) query(query string, args []driver.NamedValue) (driver.Rows, error) {
+	match := selectPattern.FindStringSubmatch(query)
+	if match == nil {
+		return nil, fmt.Errorf("fake db: unsupported query %q", query)
+	}
+	limit := -1
+	if match[4] != "" {
+		limit, _ = strconv.Atoi(match[4])
+	}
+	var conditions []string
+	if match[3] != "" {
+		conditions = strings.Split(match[3], " AND ")
+	}
+	store.mu.Lock()
+	defer store.mu.Unlock()
+	var rows []fakeRow
+	for _, row := range store.tables[match[2]] {
+		ok, err := rowMatches(row, conditions, args)
+		if err != nil {
+			return nil, err
+		}
+		if ok && (limit < 0 || len(rows) < limit) {
+			rows = append(rows, row)
+		}
+	}
+	if strings.HasPrefix(strings.ToLower(match[1]), "count(") {
+		return &fakeRows{columns: []string{"count"}, rows: []fakeRow{{"count": int64(len(rows))}}}, nil
+	}
+	if match[1] != "*" {
+		return nil, fmt.Errorf("fake db: unsupported select %q", query)
+	}
+	columns := []string{"id"}
+	if len(rows) > 0 {
+		for column := range rows[0] {
+			if column != "id" {
+				columns = append(columns, column)
+			}
+		}
+	}
+	return &fakeRows{columns: columns, rows: rows}, nil
+}
+
+func rowMatches(row fakeRow, conditions []string, args []driver.NamedValue) (bool, error) {
+	argIndex := 0
+	for _, condition := range conditions {
+		condition = strings.Trim(condition, "()")
+		if match := equalPattern.FindStringSubmatch(condition); match != nil {
+			if argIndex >= len(args) {
+				return false, errors.New("fake db: not enough args")
+			}
+			if fmt.Sprint(row[match[1]]) != fmt.Sprint(args[argIndex].Value) {
+				return false, nil
+			}
+			argIndex++
+			continue
+		}
+		if match := isNullPattern.FindStringSubmatch(condition); match != nil {
+			if row[match[1]] != nil {
+				return false, nil
+			}
+			continue
+		}
+		return false, fmt.Errorf("fake db: unsupported condition %q", condition)
+	}
+	return true, nil
+}
+
+type fakeConn struct {
+	store *fakeDB
+}
+
+func (conn *fakeConn) Prepare(query string) (driver.Stmt, error) {
+	return nil, errors.New("fake db: prepared statements are not supported")
+}
+
+func (conn *fakeConn) Close() error {
+	return nil
+}
+
+func (conn *fakeConn) Begin() (driver.Tx, error) {
+	return fakeTx{}, nil
+}
+
+func (conn *fakeConn) ExecContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Result, error) {
+	return conn.store.insert(query, args)
+}
+
+func (conn *fakeConn) QueryContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Rows, error) {
+	return conn.store.query(query, args)
+}
+
+type fakeTx struct{}
+
+func (fakeTx) Commit() error {
+	return nil
+}
+
+func (fakeTx) Rollback() error {
+	return nil
+}
+
+type fakeResult int64
+
+func (result fakeResult) LastInsertId() (int64, error) {
+	return int64(result), nil
+}
+
+func (result fakeResult) RowsAffected() (int64, error) {
+	return 1, nil
+}
+
+type fakeRows struct {
+	columns []string
+	rows    []fakeRow
+	pos     int
+}
+
+func (rows *fakeRows) Columns() []string {
+	return rows.columns
+}
+
+func (rows *fakeRows) Close() error {
+	return nil
+}
+
+func (rows *fakeRows) Next(dest []driver.Value) error {
+	if rows.pos >= len(rows.rows) {
+		return io.EOF
+	}
+	for i, column := range rows.columns {
+		dest[i] = rows.rows[rows.pos][column]
+	}
+	rows.pos++
+	return nil
+}
@@ -0,0 +1,276 @@
+package impl
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/go-redis/redis/v8"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+
+	"com.github.gin-common/app/exception"
+	"com.github.gin-common/app/model"
+	"com.github.gin-common/common/exceptions"
+	"com.github.gin-common/util"
+)
+
+// 锁定次数的保留时间，期间再次锁定时锁定时长按指数增长
+const loginLockCountExpire = 24 * time.Hour
+
+type loginGuardConfig struct {
+	window     time.Duration
+	maxPerUser int64
+	maxPerIP   int64
+	lockBase   time.Duration
+	lockMax    time.Duration
+}
+
+func getLoginGuardConfig() (config loginGuardConfig, err error) {
+	values := map[string]int{}
+	for _, item := range []struct {
+		name string
+		def  string
+	}{
+		{"LOGIN_FAIL_WINDOW", "900"},
+		{"LOGIN_FAIL_MAX_USER", "5"},
+		{"LOGIN_FAIL_MAX_IP", "20"},
+		{"LOGIN_LOCK_BASE", "60"},
+		{"LOGIN_LOCK_MAX", "3600"},
+	} {
+		if values[item.name], err = strconv.Atoi(util.GetDefaultEnv(item.name, item.def)); err != nil {
+			return
+		}
+	}
+	config = loginGuardConfig{
+		window:     time.Duration(values["LOGIN_FAIL_WINDOW"]) * time.Second,
+		maxPerUser: int64(values["LOGIN_FAIL_MAX_USER"]),
+		maxPerIP:   int64(values["LOGIN_FAIL_MAX_IP"]),
+		lockBase:   time.Duration(values["LOGIN_LOCK_BASE"]) * time.Second,
+		lockMax:    time.Duration(values["LOGIN_LOCK_MAX"]) * time.Second,
+	}
+	return
+}
+
+func loginFailuresKey(scope string, subject string) string {
+	return fmt.Sprintf("loginFailures:%s:%s", scope, subject)
+}
+
+func loginLockKey(scope string, subject string) string {
+	return fmt.Sprintf("loginLock:%s:%s", scope, subject)
+}
+
+func loginLockCountKey(scope string, subject string) string {
+	return fmt.Sprintf("loginLockCount:%s:%s", scope, subject)
+}
+
+type LoginGuardServiceImpl struct {
+	session *gorm.DB
+	rdb     *redis.Client
+	ctx     context.Context
+	logger  zap.Logger
+}
+
+func (guard *LoginGuardServiceImpl) Init(session *gorm.DB, rdb *redis.Client, ctx context.Context, logger zap.Logger) {
+	guard.session = session
+	guard.rdb = rdb
+	guard.ctx = ctx
+	guard.logger = logger
+}
+
+func accountLocked(retryAfter time.Duration) error {
+	return exceptions.NewError(exception.AccountLocked, exceptions.WithData(gin.H{
+		"retry_after": int(math.Ceil(retryAfter.Seconds())),
+	}))()
+}
+
+func (guard *LoginGuardServiceImpl) Check(username string, ip string) error {
+	for _, key := range []string{loginLockKey(model.LockoutScopeUser, username), loginLockKey(model.LockoutScopeIP, ip)} {
+		ttl, err := guard.rdb.PTTL(guard.ctx, key).Result()
+		if err != nil {
+			return err
+		}
+		if ttl > 0 {
+			return accountLocked(ttl)
+		}
+	}
+	return nil
+}
+
+// 滑动窗口内的失败次数
+func (guard *LoginGuardServiceImpl) addFailure(scope string, subject string, window time.Duration) (int64, error) {
+	now := time.Now()
+	key := loginFailuresKey(scope, subject)
+	var count *redis.IntCmd
+	_, err := guard.rdb.TxPipelined(guard.ctx, func(pipe redis.Pipeliner) error {
+		pipe.ZAdd(guard.ctx, key, &redis.Z{Score: float64(now.UnixNano()), Member: now.UnixNano()})
+		pipe.ZRemRangeByScore(guard.ctx, key, "-inf", strconv.FormatInt(now.Add(-window).UnixNano(), 10))
+		count = pipe.ZCard(guard.ctx, key)
+		pipe.Expire(guard.ctx, key, window)
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+	return count.Val(), nil
+}
+
+// 锁定，锁定时长随连续锁定次数指数增长
+func (guard *LoginGuardServiceImpl) lock(config loginGuardConfig, scope string, subject string, username string, ip string, failures int64) (time.Duration, error) {
+	lockCount, err := guard.rdb.Incr(guard.ctx, loginLockCountKey(scope, subject)).Result()
+	if err != nil {
+		return 0, err
+	}
+	duration := config.lockBase
+	for i := int64(1); i < lockCount && duration < config.lockMax; i++ {
+		duration *= 2
+	}
+	if duration > config.lockMax {
+		duration = config.lockMax
+	}
+	_, err = guard.rdb.TxPipelined(guard.ctx, func(pipe redis.Pipeliner) error {
+		pipe.Expire(guard.ctx, loginLockCountKey(scope, subject), loginLockCountExpire)
+		pipe.Set(guard.ctx, loginLockKey(scope, subject), failures, duration)
+		pipe.Del(guard.ctx, loginFailuresKey(scope, subject))
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+	record := &model.LoginLockout{
+		Scope:       scope,
+		Subject:     subject,
+		Username:    username,
+		IP:          ip,
+		Failures:    int(failures),
+		LockCount:   int(lockCount),
+		LockedUntil: time.Now().Add(duration),
+	}
+	if result := guard.session.Create(record); result.Error != nil {
+		guard.logger.Error(result.Error.Error())
+	}
+	guard.logger.Warn("login locked", zap.String("scope", scope), zap.String("subject", subject),
+		zap.Int64("failures", failures), zap.Duration("duration", duration))
+	return duration, nil
+}
+
+func (guard *LoginGuardServiceImpl) RecordFailure(username string, ip string) error {
+	config, err := getLoginGuardConfig()
+	if err != nil {
+		return err
+	}
+	var lockedFor time.Duration
+	for _, item := range []struct {
+		scope   string
+		subject string
+		max     int64
+	}{
+		{model.LockoutScopeUser, username, config.maxPerUser},
+		{model.LockoutScopeIP, ip, config.maxPerIP},
+	} {
+		if item.subject == "" || item.max <= 0 {
+			continue
+		}
+		failures, err := guard.addFailure(item.scope, item.subject, config.window)
+		if err != nil {
+			return err
+		}
+		if failures < item.max {
+			continue
+		}
+		duration, err := guard.lock(config, item.scope, item.subject, username, ip, failures)
+		if err != nil {
+			return err
+		}
+		if duration > lockedFor {
+			lockedFor = duration
+		}
+	}
+	if lockedFor > 0 {
+		return accountLocked(lockedFor)
+	}
+	return nil
+}
+
+// 找回密码请求与登录失败共用滑动窗口计数，仅以scope前缀区分
+const passwordResetScopePrefix = "passwordReset:"
+
+func (guard *LoginGuardServiceImpl) ThrottlePasswordReset(email string, ip string) error {
+	config, err := getLoginGuardConfig()
+	if err != nil {
+		return err
+	}
+	maxPerEmail, err := strconv.ParseInt(util.GetDefaultEnv("PASSWORD_FORGOT_MAX_EMAIL", "3"), 10, 64)
+	if err != nil {
+		return err
+	}
+	maxPerIP, err := strconv.ParseInt(util.GetDefaultEnv("PASSWORD_FORGOT_MAX_IP", "10"), 10, 64)
+	if err != nil {
+		return err
+	}
+	for _, item := range []struct {
+		scope   string
+		subject string
+		max     int64
+	}{
+		{passwordResetScopePrefix + model.LockoutScopeUser, strings.ToLower(email), maxPerEmail},
+		{passwordResetScopePrefix + model.LockoutScopeIP, ip, maxPerIP},
+	} {
+		if item.subject == "" || item.max <= 0 {
+			continue
+		}
+		count, err := guard.addFailure(item.scope, item.subject, config.window)
+		if err != nil {
+			return err
+		}
+		if count > item.max {
+			return exceptions.GetDefinedErrors(exception.PasswordResetThrottled)
+		}
+	}
+	return nil
+}
+
+func (guard *LoginGuardServiceImpl) RecordSuccess(username string, ip string) {
+	guard.rdb.Del(guard.ctx, loginFailuresKey(model.LockoutScopeUser, username), loginLockCountKey(model.LockoutScopeUser, username))
+}
+
+func (guard *LoginGuardServiceImpl) Unlock(username string, ip string, operatorID uint) error {
+	now := time.Now()
+	for _, item := range []struct {
+		scope   string
+		subject string
+	}{
+		{model.LockoutScopeUser, username},
+		{model.LockoutScopeIP, ip},
+	} {
+		if item.subject == "" {
+			continue
+		}
+		err := guard.rdb.Del(guard.ctx, loginLockKey(item.scope, item.subject), loginFailuresKey(item.scope, item.subject),
+			loginLockCountKey(item.scope, item.subject)).Err()
+		if err != nil {
+			return err
+		}
+		result := guard.session.Model(&model.LoginLockout{}).
+			Where("scope = ? AND subject = ? AND unlocked_at IS NULL AND locked_until > ?", item.scope, item.subject, now).
+			Updates(map[string]interface{}{"unlocked_at": now, "unlocked_by": operatorID})
+		if result.Error != nil {
+			return result.Error
+		}
+		guard.logger.Info("login unlocked", zap.String("scope", item.scope), zap.String("subject", item.subject),
+			zap.Uint("operator", operatorID))
+	}
+	return nil
+}
+
+func (guard *LoginGuardServiceImpl) ListLockouts(limit int) ([]model.LoginLockout, error) {
+	var lockouts []model.LoginLockout
+	if result := guard.session.Order("id desc").Limit(limit).Find(&lockouts); result.Error != nil {
+		return nil, result.Error
+	}
+	return lockouts, nil
+}
@@ -0,0 +1,214 @@
+package impl
+
+import (
+	"errors"
+	"strings"
+	"time"
+
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+
+	"com.github.gin-common/app/exception"
+	"com.github.gin-common/app/model"
+	"com.github.gin-common/app/service"
+	"com.github.gin-common/common/exceptions"
+	"com.github.gin-common/tools/totp_tool"
+	"com.github.gin-common/util"
+)
+
+const recoveryCodeCount = 10
+
+type MFAServiceImpl struct {
+	session *gorm.DB
+	logger  zap.Logger
+}
+
+func (mfaService *MFAServiceImpl) Init(session *gorm.DB, logger zap.Logger) {
+	mfaService.session = session
+	mfaService.logger = logger
+}
+
+func normalizeRecoveryCode(code string) string {
+	return strings.ToLower(strings.ReplaceAll(strings.TrimSpace(code), "-", ""))
+}
+
+func (mfaService *MFAServiceImpl) getUserMFA(userID uint) (*model.UserMFA, error) {
+	mfa := &model.UserMFA{}
+	if result := mfaService.session.Where("user_id = ?", userID).First(mfa); result.Error != nil {
+		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+			return nil, exceptions.GetDefinedErrors(exception.MFANotEnrolled)
+		}
+		return nil, result.Error
+	}
+	return mfa, nil
+}
+
+func (mfaService *MFAServiceImpl) Enroll(userID uint, account string) (*service.MFAEnrollment, error) {
+	mfa := &model.UserMFA{}
+	result := mfaService.session.Where("user_id = ?", userID).First(mfa)
+	if result.Error != nil && !errors.Is(result.Error, gorm.ErrRecordNotFound) {
+		return nil, result.Error
+	}
+	if result.Error == nil && mfa.Enabled {
+		return nil, exceptions.GetDefinedErrors(exception.MFAAlreadyEnabled)
+	}
+	secret, err := totp_tool.GenerateSecret()
+	if err != nil {
+		return nil, exceptions.GetDefinedErrors(exception.MFAUpdateFailed)
+	}
+	mfa.UserID = userID
+	mfa.Secret = secret
+	mfa.LastCounter = 0
+	if result = mfaService.session.Save(mfa); result.Error != nil {
+		mfaService.logger.Error(result.Error.Error())
+		return nil, exceptions.GetDefinedErrors(exception.MFAUpdateFailed)
+	}
+	return &service.MFAEnrollment{
+		Secret: secret,
+		URI:    totp_tool.URI(util.GetDefaultEnv("MFA_ISSUER", "gin-common"), account, secret),
+	}, nil
+}
+
+// 校验动态码并记录使用的时间窗口，同一窗口的动态码只能使用一次
+func (mfaService *MFAServiceImpl) verifyTOTP(session *gorm.DB, mfa *model.UserMFA, code string) (bool, error) {
+	counter, ok := totp_tool.Validate(mfa.Secret, strings.TrimSpace(code), time.Now())
+	if !ok {
+		return false, nil
+	}
+	result := session.Model(&model.UserMFA{}).Where("id = ? AND last_counter < ?", mfa.ID, counter).
+		UpdateColumn("last_counter", counter)
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected == 1, nil
+}
+
+// 使用恢复码，每个恢复码只能使用一次
+func (mfaService *MFAServiceImpl) useRecoveryCode(session *gorm.DB, userID uint, code string) (bool, error) {
+	result := session.Model(&model.MFARecoveryCode{}).
+		Where("user_id = ? AND code_hash = ? AND used_at IS NULL", userID, util.SHA256Hex(normalizeRecoveryCode(code))).
+		UpdateColumn("used_at", time.Now())
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected == 1, nil
+}
+
+func (mfaService *MFAServiceImpl) generateRecoveryCodes(tx *gorm.DB, userID uint) ([]string, error) {
+	if err := tx.Where("user_id = ?", userID).Delete(&model.MFARecoveryCode{}).Error; err != nil {
+		return nil, err
+	}
+	codes := make([]string, 0, recoveryCodeCount)
+	records := make([]model.MFARecoveryCode, 0, recoveryCodeCount)
+	for i := 0; i < recoveryCodeCount; i++ {
+		raw, err := randomHex(5)
+		if err != nil {
+			return nil, err
+		}
+		codes = append(codes, raw[:5]+"-"+raw[5:])
+		records = append(records, model.MFARecoveryCode{UserID: userID, CodeHash: util.SHA256Hex(raw)})
+	}
+	if err := tx.Create(&records).Error; err != nil {
+		return nil, err
+	}
+	return codes, nil
+}
+
+func (mfaService *MFAServiceImpl) Confirm(userID uint, code string) (recoveryCodes []string, err error) {
+	mfa, err := mfaService.getUserMFA(userID)
+	if err != nil {
+		return
+	}
+	if mfa.Enabled {
+		return nil, exceptions.GetDefinedErrors(exception.MFAAlreadyEnabled)
+	}
+	err = mfaService.session.Transaction(func(tx *gorm.DB) error {
+		ok, err := mfaService.verifyTOTP(tx, mfa, code)
+		if err != nil {
+			return err
+		}
+		if !ok {
+			return exceptions.GetDefinedErrors(exception.MFACodeInvalid)
+		}
+		if recoveryCodes, err = mfaService.generateRecoveryCodes(tx, userID); err != nil {
+			return err
+		}
+		return tx.Model(mfa).Updates(map[string]interface{}{"enabled": true, "confirmed_at": time.Now()}).Error
+	})
+	if err != nil {
+		var apiErr *exceptions.ApiError
+		if errors.As(err, &apiErr) {
+			return nil, err
+		}
+		mfaService.logger.Error(err.Error())
+		return nil, exceptions.GetDefinedErrors(exception.MFAUpdateFailed)
+	}
+	return
+}
+
+func (mfaService *MFAServiceImpl) Disable(userID uint, code string) error {
+	if err := mfaService.Verify(userID, code); err != nil {
+		return err
+	}
+	err := mfaService.session.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("user_id = ?", userID).Delete(&model.MFARecoveryCode{}).Error; err != nil {
+			return err
+		}
+		return tx.Where("user_id = ?", userID).Delete(&model.UserMFA{}).Error
+	})
+	if err != nil {
+		mfaService.logger.Error(err.Error())
+		return exceptions.GetDefinedErrors(exception.MFAUpdateFailed)
+	}
+	return nil
+}
+
+func (mfaService *MFAServiceImpl) Verify(userID uint, code string) error {
+	mfa, err := mfaService.getUserMFA(userID)
+	if err != nil {
+		return err
+	}
+	if !mfa.Enabled {
+		return exceptions.GetDefinedErrors(exception.MFANotEnabled)
+	}
+	ok, err := mfaService.verifyTOTP(mfaService.session, mfa, code)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		if ok, err = mfaService.useRecoveryCode(mfaService.session, userID, code); err != nil {
+			return err
+		}
+	}
+	if !ok {
+		return exceptions.GetDefinedErrors(exception.MFACodeInvalid)
+	}
+	return nil
+}
+
+func (mfaService *MFAServiceImpl) IsEnabled(userID uint) (bool, error) {
+	var count int64
+	result := mfaService.session.Model(&model.UserMFA{}).Where("user_id = ? AND enabled = ?", userID, true).Count(&count)
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return count > 0, nil
+}
+
+func (mfaService *MFAServiceImpl) Status(userID uint) (*service.MFAStatus, error) {
+	enabled, err := mfaService.IsEnabled(userID)
+	if err != nil {
+		return nil, err
+	}
+	status := &service.MFAStatus{Enabled: enabled}
+	if !enabled {
+		return status, nil
+	}
+	var count int64
+	result := mfaService.session.Model(&model.MFARecoveryCode{}).Where("user_id = ? AND used_at IS NULL", userID).Count(&count)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	status.RecoveryCodesLeft = int(count)
+	return status, nil
+}
@@ -0,0 +1,646 @@
+package impl
+
+import (
+	"context"
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/dgrijalva/jwt-go"
+	"github.com/go-redis/redis/v8"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+
+	"com.github.gin-common/app/exception"
+	"com.github.gin-common/app/model"
+	"com.github.gin-common/app/service"
+	"com.github.gin-common/common/exceptions"
+	"com.github.gin-common/tools/jwt_tool"
+	"com.github.gin-common/tools/oidc_tool"
+	"com.github.gin-common/util"
+)
+
+// 授权码数据，存放于redis oauthCode:<sha256(code)>，一次性使用
+type oauthCodeData struct {
+	ClientID         string `json:"clientId"`
+	UserID           uint   `json:"userId"`
+	RedirectURI      string `json:"redirectUri"`
+	RedirectURIGiven bool   `json:"redirectUriGiven"` // 授权请求显式传递了redirect_uri，换取token时必须传递相同的值
+	Scope            string `json:"scope"`
+	CodeChallenge    string `json:"codeChallenge"`
+}
+
+// access token数据，存放于redis oauthAccessToken:<jti>，删除即注销
+type oauthAccessData struct {
+	ClientID string `json:"clientId"`
+	UserID   uint   `json:"userId"`
+	Scope    string `json:"scope"`
+}
+
+// refresh token数据，存放于redis oauthRefreshToken:<sha256(token)>
+type oauthRefreshData struct {
+	ClientID  string `json:"clientId"`
+	UserID    uint   `json:"userId"`
+	Scope     string `json:"scope"`
+	AccessJTI string `json:"accessJti"`
+}
+
+func oauthCodeKey(code string) string {
+	return fmt.Sprintf("oauthCode:%s", util.SHA256Hex(code))
+}
+
+func oauthAccessTokenKey(jti string) string {
+	return fmt.Sprintf("oauthAccessToken:%s", jti)
+}
+
+func oauthRefreshTokenKey(token string) string {
+	return fmt.Sprintf("oauthRefreshToken:%s", util.SHA256Hex(token))
+}
+
+func oauthExpire(name string, defaultValue string) (int, error) {
+	return strconv.Atoi(util.GetDefaultEnv(name, defaultValue))
+}
+
+type OAuthServiceImpl struct {
+	session     *gorm.DB
+	rdb         *redis.Client
+	ctx         context.Context
+	logger      zap.Logger
+	userService service.UserService
+}
+
+func (oauthService *OAuthServiceImpl) Init(session *gorm.DB, rdb *redis.Client, ctx context.Context, logger zap.Logger, userService service.UserService) {
+	oauthService.session = session
+	oauthService.rdb = rdb
+	oauthService.ctx = ctx
+	oauthService.logger = logger
+	oauthService.userService = userService
+}
+
+func (oauthService *OAuthServiceImpl) CreateClient(client *model.OAuthClient) (*model.OAuthClient, string, error) {
+	clientID, err := randomHex(12)
+	if err != nil {
+		return nil, "", exceptions.GetDefinedErrors(exception.OAuthClientCreateFailed)
+	}
+	client.ClientID = clientID
+	if client.Type == "" {
+		client.Type = model.OAuthClientConfidential
+	}
+	if client.GrantTypes == "" {
+		client.GrantTypes = strings.Join([]string{model.GrantAuthorizationCode, model.GrantRefreshToken, model.GrantClientCredentials}, " ")
+	}
+	var secret string
+	if client.IsPublic() {
+		// public客户端无法保存secret，不允许client_credentials
+		var grants []string
+		for _, grant := range strings.Fields(client.GrantTypes) {
+			if grant != model.GrantClientCredentials {
+				grants = append(grants, grant)
+			}
+		}
+		client.GrantTypes = strings.Join(grants, " ")
+	} else {
+		secret, err = util.RandomToken(32)
+		if err != nil {
+			return nil, "", exceptions.GetDefinedErrors(exception.OAuthClientCreateFailed)
+		}
+		client.SecretHash = util.SHA256Hex(secret)
+	}
+	if result := oauthService.session.Create(client); result.Error != nil {
+		oauthService.logger.Error(result.Error.Error())
+		return nil, "", exceptions.GetDefinedErrors(exception.OAuthClientCreateFailed)
+	}
+	return client, secret, nil
+}
+
+func (oauthService *OAuthServiceImpl) ListClients() ([]model.OAuthClient, error) {
+	var clients []model.OAuthClient
+	if result := oauthService.session.Order("id").Find(&clients); result.Error != nil {
+		return nil, exceptions.GetDefinedErrors(exceptions.ServerError)
+	}
+	return clients, nil
+}
+
+func (oauthService *OAuthServiceImpl) DeleteClient(id uint) error {
+	client := &model.OAuthClient{}
+	if result := oauthService.session.First(client, id); result.Error != nil {
+		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+			return exceptions.GetDefinedErrors(exception.OAuthClientNotFound)
+		}
+		return exceptions.GetDefinedErrors(exceptions.ServerError)
+	}
+	err := oauthService.session.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("client_id = ?", client.ClientID).Delete(&model.OAuthConsent{}).Error; err != nil {
+			return err
+		}
+		return tx.Delete(client).Error
+	})
+	if err != nil {
+		return exceptions.GetDefinedErrors(exceptions.ServerError)
+	}
+	return nil
+}
+
+func (oauthService *OAuthServiceImpl) getClient(clientID string) (*model.OAuthClient, error) {
+	client := &model.OAuthClient{}
+	result := oauthService.session.Where("client_id = ?", clientID).First(client)
+	if result.Error != nil {
+		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, exceptions.GetDefinedErrors(exceptions.ServerError)
+	}
+	return client, nil
+}
+
+// 校验客户端身份，public客户端只需client_id
+func (oauthService *OAuthServiceImpl) authenticateClient(credentials service.OAuthClientCredentials) (*model.OAuthClient, error) {
+	if credentials.ClientID == "" {
+		return nil, service.NewOAuthError("invalid_client", "client authentication required")
+	}
+	client, err := oauthService.getClient(credentials.ClientID)
+	if err != nil {
+		return nil, err
+	}
+	if client == nil {
+		return nil, service.NewOAuthError("invalid_client", "unknown client")
+	}
+	if client.IsPublic() {
+		return client, nil
+	}
+	if subtle.ConstantTimeCompare([]byte(util.SHA256Hex(credentials.ClientSecret)), []byte(client.SecretHash)) != 1 {
+		return nil, service.NewOAuthError("invalid_client", "client authentication failed")
+	}
+	return client, nil
+}
+
+// 解析请求的授权范围，为空时使用客户端的全部范围
+func parseOAuthScopes(scope string, allowed func(string) bool, defaults []string) ([]string, bool) {
+	scopes := strings.Fields(scope)
+	if len(scopes) == 0 {
+		return defaults, true
+	}
+	for _, s := range scopes {
+		if !allowed(s) {
+			return nil, false
+		}
+	}
+	return scopes, true
+}
+
+func invalidAuthorize(message string) error {
+	return exceptions.NewError(exception.OAuthAuthorizeInvalid, exceptions.WithError(errors.New(message)))()
+}
+
+func (oauthService *OAuthServiceImpl) validateAuthorize(req service.OAuthAuthorizeRequest) (*model.OAuthClient, string, []string, error) {
+	client, err := oauthService.getClient(req.ClientID)
+	if err != nil {
+		return nil, "", nil, err
+	}
+	if client == nil {
+		return nil, "", nil, invalidAuthorize("client_id无效")
+	}
+	redirectURI := req.RedirectURI
+	if redirectURI == "" && len(client.RedirectURIList()) == 1 {
+		redirectURI = client.RedirectURIList()[0]
+	}
+	if !client.AllowsRedirectURI(redirectURI) {
+		return nil, "", nil, invalidAuthorize("redirect_uri未注册")
+	}
+	if req.ResponseType != "code" || !client.AllowsGrant(model.GrantAuthorizationCode) {
+		return nil, "", nil, invalidAuthorize("不支持的response_type")
+	}
+	if req.CodeChallenge == "" || req.CodeChallengeMethod != "S256" {
+		return nil, "", nil, invalidAuthorize("需要使用S256 code_challenge")
+	}
+	scopes, ok := parseOAuthScopes(req.Scope, client.AllowsScope, client.ScopeList())
+	if !ok {
+		return nil, "", nil, invalidAuthorize("scope无效")
+	}
+	return client, redirectURI, scopes, nil
+}
+
+func (oauthService *OAuthServiceImpl) getConsent(userID uint, clientID string) (*model.OAuthConsent, error) {
+	consent := &model.OAuthConsent{}
+	result := oauthService.session.Where("user_id = ? AND client_id = ?", userID, clientID).First(consent)
+	if result.Error != nil {
+		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, exceptions.GetDefinedErrors(exceptions.ServerError)
+	}
+	return consent, nil
+}
+
+func (oauthService *OAuthServiceImpl) AuthorizeInfo(req service.OAuthAuthorizeRequest, userID uint) (*service.OAuthAuthorizeInfo, error) {
+	client, redirectURI, scopes, err := oauthService.validateAuthorize(req)
+	if err != nil {
+		return nil, err
+	}
+	consent, err := oauthService.getConsent(userID, client.ClientID)
+	if err != nil {
+		return nil, err
+	}
+	return &service.OAuthAuthorizeInfo{
+		Client:          client,
+		Scopes:          scopes,
+		RedirectURI:     redirectURI,
+		ConsentRequired: consent == nil || !consent.Covers(scopes),
+	}, nil
+}
+
+// 记录用户同意的授权范围(与已有范围合并)
+func (oauthService *OAuthServiceImpl) saveConsent(userID uint, clientID string, scopes []string) error {
+	consent, err := oauthService.getConsent(userID, clientID)
+	if err != nil {
+		return err
+	}
+	if consent == nil {
+		consent = &model.OAuthConsent{UserID: userID, ClientID: clientID, Scopes: strings.Join(scopes, " ")}
+		if result := oauthService.session.Create(consent); result.Error != nil {
+			return exceptions.GetDefinedErrors(exceptions.ServerError)
+		}
+		return nil
+	}
+	if consent.Covers(scopes) {
+		return nil
+	}
+	merged := consent.Scopes
+	for _, scope := range scopes {
+		if !consent.Covers([]string{scope}) {
+			merged = strings.TrimSpace(merged + " " + scope)
+		}
+	}
+	if result := oauthService.session.Model(consent).Update("scopes", merged); result.Error != nil {
+		return exceptions.GetDefinedErrors(exceptions.ServerError)
+	}
+	return nil
+}
+
+func redirectWithParams(redirectURI string, params map[string]string) (string, error) {
+	u, err := url.Parse(redirectURI)
+	if err != nil {
+		return "", err
+	}
+	query := u.Query()
+	for k, v := range params {
+		if v != "" {
+			query.Set(k, v)
+		}
+	}
+	u.RawQuery = query.Encode()
+	return u.String(), nil
+}
+
+func (oauthService *OAuthServiceImpl) Authorize(req service.OAuthAuthorizeRequest, userID uint, approved bool) (string, error) {
+	client, redirectURI, scopes, err := oauthService.validateAuthorize(req)
+	if err != nil {
+		return "", err
+	}
+	if !approved {
+		return redirectWithParams(redirectURI, map[string]string{"error": "access_denied", "state": req.State})
+	}
+	if err = oauthService.saveConsent(userID, client.ClientID, scopes); err != nil {
+		return "", err
+	}
+	expire, err := oauthExpire("OAUTH_CODE_EXPIRE", "60")
+	if err != nil {
+		return "", err
+	}
+	code, err := util.RandomToken(32)
+	if err != nil {
+		return "", err
+	}
+	value, err := json.Marshal(oauthCodeData{
+		ClientID:         client.ClientID,
+		UserID:           userID,
+		RedirectURI:      redirectURI,
+		RedirectURIGiven: req.RedirectURI != "",
+		Scope:            strings.Join(scopes, " "),
+		CodeChallenge:    req.CodeChallenge,
+	})
+	if err != nil {
+		return "", err
+	}
+	if err = oauthService.rdb.Set(oauthService.ctx, oauthCodeKey(code), value, time.Duration(expire)*time.Second).Err(); err != nil {
+		return "", err
+	}
+	return redirectWithParams(redirectURI, map[string]string{"code": code, "state": req.State})
+}
+
+// 取出并删除redis中的一次性数据
+func (oauthService *OAuthServiceImpl) consume(key string, v interface{}) (bool, error) {
+	var get *redis.StringCmd
+	_, err := oauthService.rdb.TxPipelined(oauthService.ctx, func(pipe redis.Pipeliner) error {
+		get = pipe.Get(oauthService.ctx, key)
+		pipe.Del(oauthService.ctx, key)
+		return nil
+	})
+	if err != nil {
+		if err == redis.Nil {
+			return false, nil
+		}
+		return false, err
+	}
+	return true, json.Unmarshal([]byte(get.Val()), v)
+}
+
+func (oauthService *OAuthServiceImpl) Token(req service.OAuthTokenRequest) (*service.OAuthToken, error) {
+	client, err := oauthService.authenticateClient(req.OAuthClientCredentials)
+	if err != nil {
+		return nil, err
+	}
+	switch req.GrantType {
+	case model.GrantAuthorizationCode, model.GrantClientCredentials, model.GrantRefreshToken:
+	default:
+		return nil, service.NewOAuthError("unsupported_grant_type", "")
+	}
+	if !client.AllowsGrant(req.GrantType) {
+		return nil, service.NewOAuthError("unauthorized_client", "")
+	}
+	switch req.GrantType {
+	case model.GrantAuthorizationCode:
+		return oauthService.exchangeCode(client, req)
+	case model.GrantClientCredentials:
+		scopes, ok := parseOAuthScopes(req.Scope, client.AllowsScope, client.ScopeList())
+		if !ok {
+			return nil, service.NewOAuthError("invalid_scope", "")
+		}
+		return oauthService.issueToken(client, 0, strings.Join(scopes, " "), false)
+	default:
+		return oauthService.refresh(client, req)
+	}
+}
+
+func (oauthService *OAuthServiceImpl) checkUserActive(userID uint) error {
+	user, err := oauthService.userService.GetUserInfoById(userID)
+	if err != nil || !user.ActivateStatus {
+		return service.NewOAuthError("invalid_grant", "user is not active")
+	}
+	return nil
+}
+
+func (oauthService *OAuthServiceImpl) exchangeCode(client *model.OAuthClient, req service.OAuthTokenRequest) (*service.OAuthToken, error) {
+	var data oauthCodeData
+	ok, err := oauthService.consume(oauthCodeKey(req.Code), &data)
+	if err != nil {
+		return nil, err
+	}
+	if !ok || data.ClientID != client.ClientID {
+		return nil, service.NewOAuthError("invalid_grant", "invalid authorization code")
+	}
+	if (data.RedirectURIGiven || req.RedirectURI != "") && req.RedirectURI != data.RedirectURI {
+		return nil, service.NewOAuthError("invalid_grant", "redirect_uri mismatch")
+	}
+	if req.CodeVerifier == "" || oidc_tool.CodeChallengeS256(req.CodeVerifier) != data.CodeChallenge {
+		return nil, service.NewOAuthError("invalid_grant", "pkce verification failed")
+	}
+	if err = oauthService.checkUserActive(data.UserID); err != nil {
+		return nil, err
+	}
+	return oauthService.issueToken(client, data.UserID, data.Scope, client.AllowsGrant(model.GrantRefreshToken))
+}
+
+// refresh token每次使用后轮换，原access token同时失效
+func (oauthService *OAuthServiceImpl) refresh(client *model.OAuthClient, req service.OAuthTokenRequest) (*service.OAuthToken, error) {
+	var data oauthRefreshData
+	ok, err := oauthService.consume(oauthRefreshTokenKey(req.RefreshToken), &data)
+	if err != nil {
+		return nil, err
+	}
+	if !ok || data.ClientID != client.ClientID {
+		return nil, service.NewOAuthError("invalid_grant", "invalid refresh token")
+	}
+	oauthService.rdb.Del(oauthService.ctx, oauthAccessTokenKey(data.AccessJTI))
+	granted := strings.Fields(data.Scope)
+	scopes, ok := parseOAuthScopes(req.Scope, func(scope string) bool {
+		for _, s := range granted {
+			if s == scope {
+				return true
+			}
+		}
+		return false
+	}, granted)
+	if !ok {
+		return nil, service.NewOAuthError("invalid_scope", "")
+	}
+	if err = oauthService.checkUserActive(data.UserID); err != nil {
+		return nil, err
+	}
+	return oauthService.issueToken(client, data.UserID, strings.Join(scopes, " "), true)
+}
+
+// 使用jwt_tool签发access token(aud为client_id，不会通过本服务AuthMiddleware的受众校验)
+func (oauthService *OAuthServiceImpl) issueToken(client *model.OAuthClient, userID uint, scope string, withRefresh bool) (*service.OAuthToken, error) {
+	accessExpire, err := oauthExpire("OAUTH_ACCESS_TOKEN_EXPIRE", "3600")
+	if err != nil {
+		return nil, err
+	}
+	refreshExpire, err := oauthExpire("OAUTH_REFRESH_TOKEN_EXPIRE", strconv.Itoa(30*24*60*60))
+	if err != nil {
+		return nil, err
+	}
+	sub := client.ClientID
+	if userID != 0 {
+		sub = strconv.Itoa(int(userID))
+	}
+	claims := jwt.MapClaims{
+		"sub":       sub,
+		"aud":       client.ClientID,
+		"client_id": client.ClientID,
+		"scope":     scope,
+	}
+	tokenInfo, err := jwt_tool.CreateToken(claims, accessExpire)
+	if err != nil {
+		return nil, err
+	}
+	jti := claims["jti"].(string)
+	token := &service.OAuthToken{
+		AccessToken: tokenInfo.Token,
+		TokenType:   "Bearer",
+		ExpiresIn:   accessExpire,
+		Scope:       scope,
+	}
+	accessValue, err := json.Marshal(oauthAccessData{ClientID: client.ClientID, UserID: userID, Scope: scope})
+	if err != nil {
+		return nil, err
+	}
+	var refreshValue []byte
+	if withRefresh {
+		if token.RefreshToken, err = util.RandomToken(32); err != nil {
+			return nil, err
+		}
+		refreshValue, err = json.Marshal(oauthRefreshData{ClientID: client.ClientID, UserID: userID, Scope: scope, AccessJTI: jti})
+		if err != nil {
+			return nil, err
+		}
+	}
+	_, err = oauthService.rdb.TxPipelined(oauthService.ctx, func(pipe redis.Pipeliner) error {
+		pipe.Set(oauthService.ctx, oauthAccessTokenKey(jti), accessValue, time.Duration(accessExpire)*time.Second)
+		if withRefresh {
+			pipe.Set(oauthService.ctx, oauthRefreshTokenKey(token.RefreshToken), refreshValue, time.Duration(refreshExpire)*time.Second)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return token, nil
+}
+
+// OAuth access token的受众为各客户端，只校验签发者及必须的声明
+func oauthClaimsPolicy() (*jwt_tool.ClaimsPolicy, error) {
+	p, err := jwt_tool.DefaultClaimsPolicy()
+	if err != nil {
+		return nil, err
+	}
+	policy := *p
+	policy.AllowedAudiences = nil
+	return &policy, nil
+}
+
+func int64Claim(claims jwt.MapClaims, name string) int64 {
+	switch v := claims[name].(type) {
+	case float64:
+		return int64(v)
+	case json.Number:
+		n, _ := v.Int64()
+		return n
+	}
+	return 0
+}
+
+func isJWT(token string) bool {
+	return strings.Count(token, ".") == 2
+}
+
+func (oauthService *OAuthServiceImpl) username(userID uint) string {
+	if userID == 0 {
+		return ""
+	}
+	user, err := oauthService.userService.GetUserInfoById(userID)
+	if err != nil {
+		return ""
+	}
+	return user.Username
+}
+
+// 解析并校验access token，已注销或无效时返回nil
+func (oauthService *OAuthServiceImpl) accessToken(token string) (jwt.MapClaims, *oauthAccessData, error) {
+	policy, err := oauthClaimsPolicy()
+	if err != nil {
+		return nil, nil, err
+	}
+	claims, err := jwt_tool.ParseToken(token, policy)
+	if err != nil {
+		return nil, nil, nil
+	}
+	jti, _ := claims["jti"].(string)
+	val, err := oauthService.rdb.Get(oauthService.ctx, oauthAccessTokenKey(jti)).Result()
+	if err != nil {
+		if err == redis.Nil {
+			return nil, nil, nil
+		}
+		return nil, nil, err
+	}
+	data := &oauthAccessData{}
+	if err = json.Unmarshal([]byte(val), data); err != nil {
+		return nil, nil, err
+	}
+	return claims, data, nil
+}
+
+func (oauthService *OAuthServiceImpl) refreshToken(token string) (*oauthRefreshData, time.Duration, error) {
+	key := oauthRefreshTokenKey(token)
+	var get *redis.StringCmd
+	var ttl *redis.DurationCmd
+	_, err := oauthService.rdb.TxPipelined(oauthService.ctx, func(pipe redis.Pipeliner) error {
+		get = pipe.Get(oauthService.ctx, key)
+		ttl = pipe.TTL(oauthService.ctx, key)
+		return nil
+	})
+	if err != nil {
+		if err == redis.Nil {
+			return nil, 0, nil
+		}
+		return nil, 0, err
+	}
+	data := &oauthRefreshData{}
+	if err = json.Unmarshal([]byte(get.Val()), data); err != nil {
+		return nil, 0, err
+	}
+	return data, ttl.Val(), nil
+}
+
+func (oauthService *OAuthServiceImpl) Introspect(credentials service.OAuthClientCredentials, token string, tokenTypeHint string) (*service.OAuthIntrospection, error) {
+	// 只允许confidential客户端(资源服务)查询
+	client, err := oauthService.authenticateClient(credentials)
+	if err != nil {
+		return nil, err
+	}
+	if client.IsPublic() {
+		return nil, service.NewOAuthError("invalid_client", "public clients cannot introspect tokens")
+	}
+	inactive := &service.OAuthIntrospection{Active: false}
+	if isJWT(token) {
+		claims, data, err := oauthService.accessToken(token)
+		if err != nil || data == nil {
+			return inactive, err
+		}
+		sub, _ := claims["sub"].(string)
+		aud, _ := claims["aud"].(string)
+		iss, _ := claims["iss"].(string)
+		jti, _ := claims["jti"].(string)
+		return &service.OAuthIntrospection{
+			Active:    true,
+			Scope:     data.Scope,
+			ClientID:  data.ClientID,
+			Username:  oauthService.username(data.UserID),
+			TokenType: "Bearer",
+			Exp:       int64Claim(claims, "exp"),
+			Iat:       int64Claim(claims, "iat"),
+			Sub:       sub,
+			Aud:       aud,
+			Iss:       iss,
+			Jti:       jti,
+		}, nil
+	}
+	data, ttl, err := oauthService.refreshToken(token)
+	if err != nil || data == nil {
+		return inactive, err
+	}
+	return &service.OAuthIntrospection{
+		Active:    true,
+		Scope:     data.Scope,
+		ClientID:  data.ClientID,
+		Username:  oauthService.username(data.UserID),
+		TokenType: "refresh_token",
+		Exp:       time.Now().Add(ttl).Unix(),
+	}, nil
+}
+
+func (oauthService *OAuthServiceImpl) Revoke(credentials service.OAuthClientCredentials, token string, tokenTypeHint string) error {
+	// 无效或不属于该客户端的token直接忽略(RFC 7009)
+	client, err := oauthService.authenticateClient(credentials)
+	if err != nil {
+		return err
+	}
+	if isJWT(token) {
+		claims, data, err := oauthService.accessToken(token)
+		if err != nil || data == nil || data.ClientID != client.ClientID {
+			return err
+		}
+		jti, _ := claims["jti"].(string)
+		return oauthService.rdb.Del(oauthService.ctx, oauthAccessTokenKey(jti)).Err()
+	}
+	data, _, err := oauthService.refreshToken(token)
+	if err != nil || data == nil || data.ClientID != client.ClientID {
+		return err
+	}
+	return oauthService.rdb.Del(oauthService.ctx, oauthRefreshTokenKey(token), oauthAccessTokenKey(data.AccessJTI)).Err()
+}
@@ -0,0 +1,314 @@
+package impl
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-redis/redis/v8"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+
+	"com.github.gin-common/app/exception"
+	"com.github.gin-common/app/model"
+	"com.github.gin-common/app/service"
+	"com.github.gin-common/common/exceptions"
+	"com.github.gin-common/tools/oidc_tool"
+	"com.github.gin-common/tools/password_tool"
+	"com.github.gin-common/util"
+)
+
+// 请求身份提供方的超时时间
+const oidcRequestTimeout = 10 * time.Second
+
+// 授权请求数据，存放于redis oidcState:<state> 中，回调时一次性取出
+type oidcStateData struct {
+	Provider     string `json:"provider"`
+	Nonce        string `json:"nonce"`
+	CodeVerifier string `json:"codeVerifier"`
+	LinkUserID   uint   `json:"linkUserId"`
+}
+
+func oidcStateKey(state string) string {
+	return fmt.Sprintf("oidcState:%s", state)
+}
+
+type OIDCServiceImpl struct {
+	session     *gorm.DB
+	rdb         *redis.Client
+	ctx         context.Context
+	logger      zap.Logger
+	userService service.UserService
+	authService service.AuthService
+}
+
+func (oidcService *OIDCServiceImpl) Init(session *gorm.DB, rdb *redis.Client, ctx context.Context, logger zap.Logger, userService service.UserService, authService service.AuthService) {
+	oidcService.session = session
+	oidcService.rdb = rdb
+	oidcService.ctx = ctx
+	oidcService.logger = logger
+	oidcService.userService = userService
+	oidcService.authService = authService
+}
+
+func (oidcService *OIDCServiceImpl) getProvider(name string) (*oidc_tool.Client, error) {
+	client, err := oidc_tool.GetProvider(name)
+	if err != nil {
+		return nil, exceptions.GetDefinedErrors(exception.OIDCProviderNotFound)
+	}
+	return client, nil
+}
+
+func (oidcService *OIDCServiceImpl) AuthorizationURL(provider string, linkUserID uint) (string, error) {
+	client, err := oidcService.getProvider(provider)
+	if err != nil {
+		return "", err
+	}
+	expire, err := strconv.Atoi(util.GetDefaultEnv("OIDC_STATE_EXPIRE", "600"))
+	if err != nil {
+		return "", err
+	}
+	state, err := oidc_tool.RandomState()
+	if err != nil {
+		return "", err
+	}
+	nonce, err := oidc_tool.RandomState()
+	if err != nil {
+		return "", err
+	}
+	verifier, err := oidc_tool.NewCodeVerifier()
+	if err != nil {
+		return "", err
+	}
+	ctx, cancel := context.WithTimeout(oidcService.ctx, oidcRequestTimeout)
+	defer cancel()
+	authURL, err := client.AuthCodeURL(ctx, state, nonce, verifier)
+	if err != nil {
+		oidcService.logger.Error("oidc discovery failed", zap.String("provider", provider), zap.Error(err))
+		return "", exceptions.GetDefinedErrors(exception.OIDCLoginFailed)
+	}
+	value, err := json.Marshal(oidcStateData{Provider: provider, Nonce: nonce, CodeVerifier: verifier, LinkUserID: linkUserID})
+	if err != nil {
+		return "", err
+	}
+	if err = oidcService.rdb.Set(oidcService.ctx, oidcStateKey(state), value, time.Duration(expire)*time.Second).Err(); err != nil {
+		return "", err
+	}
+	return authURL, nil
+}
+
+// 取出并删除state，防止重放
+func (oidcService *OIDCServiceImpl) consumeState(state string) (*oidcStateData, error) {
+	if state == "" {
+		return nil, exceptions.GetDefinedErrors(exception.OIDCStateInvalid)
+	}
+	var get *redis.StringCmd
+	_, err := oidcService.rdb.TxPipelined(oidcService.ctx, func(pipe redis.Pipeliner) error {
+		get = pipe.Get(oidcService.ctx, oidcStateKey(state))
+		pipe.Del(oidcService.ctx, oidcStateKey(state))
+		return nil
+	})
+	if err != nil {
+		if err == redis.Nil {
+			return nil, exceptions.GetDefinedErrors(exception.OIDCStateInvalid)
+		}
+		return nil, err
+	}
+	data := &oidcStateData{}
+	if err = json.Unmarshal([]byte(get.Val()), data); err != nil {
+		return nil, err
+	}
+	return data, nil
+}
+
+func (oidcService *OIDCServiceImpl) Callback(provider string, state string, code string, client service.ClientInfo) (*service.OIDCCallbackResult, error) {
+	data, err := oidcService.consumeState(state)
+	if err != nil {
+		return nil, err
+	}
+	if data.Provider != provider {
+		return nil, exceptions.GetDefinedErrors(exception.OIDCStateInvalid)
+	}
+	oidcClient, err := oidcService.getProvider(provider)
+	if err != nil {
+		return nil, err
+	}
+	ctx, cancel := context.WithTimeout(oidcService.ctx, oidcRequestTimeout)
+	defer cancel()
+	token, err := oidcClient.Exchange(ctx, code, data.CodeVerifier)
+	if err != nil {
+		oidcService.logger.Warn("oidc code exchange failed", zap.String("provider", provider), zap.Error(err))
+		return nil, exceptions.GetDefinedErrors(exception.OIDCLoginFailed)
+	}
+	claims, err := oidcClient.VerifyIDToken(ctx, token.IDToken, data.Nonce)
+	if err != nil {
+		oidcService.logger.Warn("oidc id token invalid", zap.String("provider", provider), zap.Error(err))
+		return nil, exceptions.GetDefinedErrors(exception.OIDCLoginFailed)
+	}
+
+	if data.LinkUserID != 0 {
+		identity, err := oidcService.link(data.LinkUserID, provider, claims)
+		if err != nil {
+			return nil, err
+		}
+		return &service.OIDCCallbackResult{Linked: true, Identity: identity}, nil
+	}
+	identity, err := oidcService.resolveIdentity(provider, claims)
+	if err != nil {
+		return nil, err
+	}
+	result, err := oidcService.authService.LoginExternal(identity.UserID, client)
+	if err != nil {
+		return nil, err
+	}
+	return &service.OIDCCallbackResult{Login: result, Identity: identity}, nil
+}
+
+func (oidcService *OIDCServiceImpl) findIdentity(provider string, subject string) (*model.UserIdentity, error) {
+	identity := &model.UserIdentity{}
+	result := oidcService.session.Where("provider = ? AND subject = ?", provider, subject).First(identity)
+	if result.Error != nil {
+		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, exceptions.GetDefinedErrors(exceptions.ServerError)
+	}
+	return identity, nil
+}
+
+func (oidcService *OIDCServiceImpl) createIdentity(userID uint, provider string, claims *oidc_tool.IDTokenClaims) (*model.UserIdentity, error) {
+	identity := &model.UserIdentity{
+		UserID:   userID,
+		Provider: provider,
+		Subject:  claims.Subject,
+		Email:    claims.Email,
+	}
+	if result := oidcService.session.Create(identity); result.Error != nil {
+		oidcService.logger.Error(result.Error.Error())
+		return nil, exceptions.GetDefinedErrors(exception.OIDCLoginFailed)
+	}
+	return identity, nil
+}
+
+// 将外部身份绑定到已登录用户
+func (oidcService *OIDCServiceImpl) link(userID uint, provider string, claims *oidc_tool.IDTokenClaims) (*model.UserIdentity, error) {
+	identity, err := oidcService.findIdentity(provider, claims.Subject)
+	if err != nil {
+		return nil, err
+	}
+	if identity != nil {
+		if identity.UserID != userID {
+			return nil, exceptions.GetDefinedErrors(exception.OIDCIdentityLinked)
+		}
+		return identity, nil
+	}
+	return oidcService.createIdentity(userID, provider, claims)
+}
+
+// 查找外部身份对应的用户: 已绑定 > 按已验证邮箱绑定(OIDC_LINK_BY_EMAIL) > 自动创建(OIDC_AUTO_CREATE)
+func (oidcService *OIDCServiceImpl) resolveIdentity(provider string, claims *oidc_tool.IDTokenClaims) (*model.UserIdentity, error) {
+	identity, err := oidcService.findIdentity(provider, claims.Subject)
+	if err != nil || identity != nil {
+		return identity, err
+	}
+	if util.GetDefaultEnv("OIDC_LINK_BY_EMAIL", "false") == "true" && claims.Email != "" && claims.EmailVerified {
+		// 仅当唯一的本地用户使用该邮箱且已验证时绑定，否则邮箱可能被他人占用
+		var users []model.User
+		result := oidcService.session.Where("email = ?", claims.Email).Limit(2).Find(&users)
+		if result.Error != nil {
+			return nil, exceptions.GetDefinedErrors(exceptions.ServerError)
+		}
+		if len(users) == 1 && users[0].EmailVerified() {
+			return oidcService.createIdentity(users[0].ID, provider, claims)
+		}
+		if len(users) > 0 {
+			return nil, exceptions.GetDefinedErrors(exception.OIDCUserNotFound)
+		}
+	}
+	if util.GetDefaultEnv("OIDC_AUTO_CREATE", "true") != "true" {
+		return nil, exceptions.GetDefinedErrors(exception.OIDCUserNotFound)
+	}
+	user, err := oidcService.createUser(provider, claims)
+	if err != nil {
+		return nil, err
+	}
+	return oidcService.createIdentity(user.ID, provider, claims)
+}
+
+// 首次登录时创建本地用户，密码随机(只能通过第三方登录，或重置密码后使用密码登录)
+func (oidcService *OIDCServiceImpl) createUser(provider string, claims *oidc_tool.IDTokenClaims) (*model.User, error) {
+	username, err := oidcService.availableUsername(provider, claims)
+	if err != nil {
+		return nil, err
+	}
+	password, err := password_tool.Generate(32)
+	if err != nil {
+		return nil, err
+	}
+	user := &model.User{
+		Username: username,
+		Name:     claims.Name,
+		Email:    claims.Email,
+	}
+	return oidcService.userService.CreateUser(user, password)
+}
+
+func (oidcService *OIDCServiceImpl) usernameTaken(username string) (bool, error) {
+	var count int64
+	if result := oidcService.session.Model(&model.User{}).Where("username = ?", username).Count(&count); result.Error != nil {
+		return false, exceptions.GetDefinedErrors(exceptions.ServerError)
+	}
+	return count > 0, nil
+}
+
+func (oidcService *OIDCServiceImpl) availableUsername(provider string, claims *oidc_tool.IDTokenClaims) (string, error) {
+	base := claims.PreferredUsername
+	if base == "" && claims.Email != "" {
+		base = strings.SplitN(claims.Email, "@", 2)[0]
+	}
+	if base == "" {
+		base = fmt.Sprintf("%s_%s", provider, claims.Subject)
+	}
+	if len(base) > 64 {
+		base = base[:64]
+	}
+	username := base
+	for i := 0; i < 5; i++ {
+		taken, err := oidcService.usernameTaken(username)
+		if err != nil {
+			return "", err
+		}
+		if !taken {
+			return username, nil
+		}
+		suffix, err := randomHex(3)
+		if err != nil {
+			return "", err
+		}
+		username = fmt.Sprintf("%s_%s", base, suffix)
+	}
+	return "", exceptions.GetDefinedErrors(exception.UserNameDuplicate)
+}
+
+func (oidcService *OIDCServiceImpl) ListIdentities(userID uint) ([]model.UserIdentity, error) {
+	var identities []model.UserIdentity
+	if result := oidcService.session.Where("user_id = ?", userID).Order("id").Find(&identities); result.Error != nil {
+		return nil, exceptions.GetDefinedErrors(exceptions.ServerError)
+	}
+	return identities, nil
+}
+
+func (oidcService *OIDCServiceImpl) Unlink(userID uint, identityID uint) error {
+	result := oidcService.session.Where("id = ? AND user_id = ?", identityID, userID).Delete(&model.UserIdentity{})
+	if result.Error != nil {
+		return exceptions.GetDefinedErrors(exceptions.ServerError)
+	}
+	if result.RowsAffected == 0 {
+		return exceptions.GetDefinedErrors(exception.OIDCIdentityNotFound)
+	}
+	return nil
+}
@@ -0,0 +1,232 @@
+package impl
+
+import (
+	"context"
+	"errors"
+	"os"
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+
+	"com.github.gin-common/app/exception"
+	"com.github.gin-common/app/model"
+	"com.github.gin-common/app/service"
+	"com.github.gin-common/common/exceptions"
+	"com.github.gin-common/tools/oidc_tool"
+	"com.github.gin-common/tools/oidc_tool/oidctest"
+)
+
+// 只实现第三方登录用到的CreateUser
+type oidcTestUserService struct {
+	service.UserService
+	session *gorm.DB
+}
+
+func (userService *oidcTestUserService) CreateUser(user *model.User, password string) (*model.User, error) {
+	if err := userService.session.Create(user).Error; err != nil {
+		return nil, err
+	}
+	return user, nil
+}
+
+func setEnv(t *testing.T, key string, value string) {
+	t.Helper()
+	old, ok := os.LookupEnv(key)
+	if err := os.Setenv(key, value); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() {
+		if ok {
+			_ = os.Setenv(key, old)
+		} else {
+			_ = os.Unsetenv(key)
+		}
+	})
+}
+
+func newOIDCTestService(t *testing.T) (*OIDCServiceImpl, *gorm.DB) {
+	t.Helper()
+	session := newFakeDB(t)
+	oidcService := &OIDCServiceImpl{}
+	oidcService.Init(session, nil, context.Background(), *zap.NewNop(), &oidcTestUserService{session: session}, nil)
+	return oidcService, session
+}
+
+// 通过oidctest完成授权码+PKCE流程并校验ID token
+func oidcTestClaims(t *testing.T, claims map[string]interface{}) *oidc_tool.IDTokenClaims {
+	t.Helper()
+	server, err := oidctest.NewServer("client", "secret")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(server.Close)
+	if claims != nil {
+		server.SetClaims(claims)
+	}
+	client := oidc_tool.NewClient(server.Config("test", "http://localhost/oidc/test/callback"), nil)
+	ctx := context.Background()
+	nonce, _ := oidc_tool.RandomState()
+	verifier, _ := oidc_tool.NewCodeVerifier()
+	authURL, err := client.AuthCodeURL(ctx, "state", nonce, verifier)
+	if err != nil {
+		t.Fatal(err)
+	}
+	code, _, err := server.Authorize(authURL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tokens, err := client.Exchange(ctx, code, verifier)
+	if err != nil {
+		t.Fatal(err)
+	}
+	idTokenClaims, err := client.VerifyIDToken(ctx, tokens.IDToken, nonce)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return idTokenClaims
+}
+
+func TestOIDCResolveIdentityCreatesUser(t *testing.T) {
+	setEnv(t, "OIDC_LINK_BY_EMAIL", "false")
+	setEnv(t, "OIDC_AUTO_CREATE", "true")
+	oidcService, session := newOIDCTestService(t)
+	claims := oidcTestClaims(t, nil)
+
+	identity, err := oidcService.resolveIdentity("test", claims)
+	if err != nil {
+		t.Fatal(err)
+	}
+	user := &model.User{}
+	if err = session.Where("id = ?", identity.UserID).First(user).Error; err != nil {
+		t.Fatal(err)
+	}
+	if user.Username != "oidctest" || user.Email != "oidctest@example.com" || user.Name != "OIDC Test" {
+		t.Errorf("unexpected user: %+v", user)
+	}
+	if identity.Provider != "test" || identity.Subject != "oidctest-user" {
+		t.Errorf("unexpected identity: %+v", identity)
+	}
+
+	// 再次登录使用已绑定的身份，不再创建用户
+	again, err := oidcService.resolveIdentity("test", claims)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if again.ID != identity.ID || again.UserID != identity.UserID {
+		t.Errorf("expected existing identity %d, got %d", identity.ID, again.ID)
+	}
+	var count int64
+	session.Model(&model.User{}).Where("username = ?", "oidctest").Count(&count)
+	if count != 1 {
+		t.Errorf("expected 1 user, got %d", count)
+	}
+
+	// 用户名已被占用时追加随机后缀
+	other := oidcTestClaims(t, map[string]interface{}{"sub": "other-user", "preferred_username": "oidctest"})
+	otherIdentity, err := oidcService.resolveIdentity("test", other)
+	if err != nil {
+		t.Fatal(err)
+	}
+	otherUser := &model.User{}
+	if err = session.Where("id = ?", otherIdentity.UserID).First(otherUser).Error; err != nil {
+		t.Fatal(err)
+	}
+	if otherUser.ID == user.ID || otherUser.Username == "oidctest" {
+		t.Errorf("expected a new user with a unique username, got %+v", otherUser)
+	}
+}
+
+func TestOIDCResolveIdentityWithoutAutoCreate(t *testing.T) {
+	setEnv(t, "OIDC_LINK_BY_EMAIL", "false")
+	setEnv(t, "OIDC_AUTO_CREATE", "false")
+	oidcService, _ := newOIDCTestService(t)
+	_, err := oidcService.resolveIdentity("test", oidcTestClaims(t, nil))
+	if !errors.Is(err, exceptions.GetDefinedErrors(exception.OIDCUserNotFound)) {
+		t.Errorf("expected OIDCUserNotFound, got %v", err)
+	}
+}
+
+func TestOIDCLinkConflict(t *testing.T) {
+	oidcService, _ := newOIDCTestService(t)
+	claims := oidcTestClaims(t, nil)
+
+	identity, err := oidcService.link(1, "test", claims)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if identity.UserID != 1 {
+		t.Errorf("expected identity linked to user 1, got %d", identity.UserID)
+	}
+	// 重复绑定到同一用户返回已有身份
+	again, err := oidcService.link(1, "test", claims)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if again.ID != identity.ID {
+		t.Errorf("expected existing identity %d, got %d", identity.ID, again.ID)
+	}
+	// 已绑定其他用户
+	if _, err = oidcService.link(2, "test", claims); !errors.Is(err, exceptions.GetDefinedErrors(exception.OIDCIdentityLinked)) {
+		t.Errorf("expected OIDCIdentityLinked, got %v", err)
+	}
+}
+
+func TestOIDCLinkByEmail(t *testing.T) {
+	setEnv(t, "OIDC_LINK_BY_EMAIL", "true")
+	setEnv(t, "OIDC_AUTO_CREATE", "true")
+	verifiedAt := time.Now()
+
+	t.Run("verified", func(t *testing.T) {
+		oidcService, session := newOIDCTestService(t)
+		user := &model.User{Username: "local", Email: "oidctest@example.com", EmailVerifiedAt: &verifiedAt}
+		if err := session.Create(user).Error; err != nil {
+			t.Fatal(err)
+		}
+		identity, err := oidcService.resolveIdentity("test", oidcTestClaims(t, nil))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if identity.UserID != user.ID {
+			t.Errorf("expected identity linked to user %d, got %d", user.ID, identity.UserID)
+		}
+	})
+	t.Run("unverified", func(t *testing.T) {
+		oidcService, session := newOIDCTestService(t)
+		if err := session.Create(&model.User{Username: "local", Email: "oidctest@example.com"}).Error; err != nil {
+			t.Fatal(err)
+		}
+		_, err := oidcService.resolveIdentity("test", oidcTestClaims(t, nil))
+		if !errors.Is(err, exceptions.GetDefinedErrors(exception.OIDCUserNotFound)) {
+			t.Errorf("expected OIDCUserNotFound, got %v", err)
+		}
+	})
+	t.Run("ambiguous", func(t *testing.T) {
+		oidcService, session := newOIDCTestService(t)
+		for _, username := range []string{"local1", "local2"} {
+			if err := session.Create(&model.User{Username: username, Email: "oidctest@example.com", EmailVerifiedAt: &verifiedAt}).Error; err != nil {
+				t.Fatal(err)
+			}
+		}
+		_, err := oidcService.resolveIdentity("test", oidcTestClaims(t, nil))
+		if !errors.Is(err, exceptions.GetDefinedErrors(exception.OIDCUserNotFound)) {
+			t.Errorf("expected OIDCUserNotFound, got %v", err)
+		}
+	})
+	t.Run("provider email not verified", func(t *testing.T) {
+		oidcService, session := newOIDCTestService(t)
+		user := &model.User{Username: "local", Email: "oidctest@example.com", EmailVerifiedAt: &verifiedAt}
+		if err := session.Create(user).Error; err != nil {
+			t.Fatal(err)
+		}
+		claims := oidcTestClaims(t, map[string]interface{}{"sub": "oidctest-user", "email": "oidctest@example.com", "email_verified": false, "preferred_username": "oidctest"})
+		identity, err := oidcService.resolveIdentity("test", claims)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if identity.UserID == user.ID {
+			t.Error("expected a new user instead of linking by an unverified provider email")
+		}
+	})
+}
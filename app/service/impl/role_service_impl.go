@@ -0,0 +1,283 @@
+package impl
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/go-redis/redis/v8"
+
+	"com.github.gin-common/app/exception"
+	"com.github.gin-common/app/model"
+	"com.github.gin-common/common/exceptions"
+	"com.github.gin-common/common/models"
+	"gorm.io/gorm"
+)
+
+const userPermissionsExpires = 5 * time.Minute
+
+type RoleServiceImpl struct {
+	session *gorm.DB
+	rdb     *redis.Client
+	ctx     context.Context
+	logger  zap.Logger
+}
+
+func (service *RoleServiceImpl) Init(session *gorm.DB, rdb *redis.Client, ctx context.Context, logger zap.Logger) {
+	service.session = session
+	service.rdb = rdb
+	service.ctx = ctx
+	service.logger = logger
+}
+
+func userPermissionsKey(userID uint) string {
+	return fmt.Sprintf("userPermissions:%d", userID)
+}
+
+func (service *RoleServiceImpl) ensurePermissions(session *gorm.DB, codes []string) ([]model.Permission, error) {
+	// 获取权限，不存在时创建
+	permissions := make([]model.Permission, 0, len(codes))
+	var flag = make(map[string]bool)
+	for _, code := range codes {
+		if flag[code] {
+			continue
+		}
+		flag[code] = true
+		permission := model.Permission{}
+		if result := session.Where(model.Permission{Code: code}).FirstOrCreate(&permission); result.Error != nil {
+			return nil, result.Error
+		}
+		permissions = append(permissions, permission)
+	}
+	return permissions, nil
+}
+
+func (service *RoleServiceImpl) CreateRole(role *model.Role, permissions []string) (*model.Role, error) {
+	// 创建角色
+	err := service.session.Transaction(func(tx *gorm.DB) error {
+		perms, err := service.ensurePermissions(tx, permissions)
+		if err != nil {
+			return err
+		}
+		role.Permissions = perms
+		return tx.Create(role).Error
+	})
+	if err != nil {
+		if models.IsDuplicateError(err) {
+			return nil, exceptions.GetDefinedErrors(exception.RoleNameDuplicate)
+		}
+		return nil, exceptions.GetDefinedErrors(exception.RoleCreateFailed)
+	}
+	return role, nil
+}
+
+func (service *RoleServiceImpl) UpdateRole(id uint, description string, permissions []string) (*model.Role, error) {
+	// 更新角色，权限整体替换
+	role, err := service.GetRole(id)
+	if err != nil {
+		return nil, err
+	}
+	err = service.session.Transaction(func(tx *gorm.DB) error {
+		perms, err := service.ensurePermissions(tx, permissions)
+		if err != nil {
+			return err
+		}
+		if err = tx.Model(role).Update("description", description).Error; err != nil {
+			return err
+		}
+		return tx.Model(role).Association("Permissions").Replace(perms)
+	})
+	if err != nil {
+		return nil, exceptions.GetDefinedErrors(exception.RoleUpdateFailed)
+	}
+	service.evictRoleUsers(id)
+	return service.GetRole(id)
+}
+
+func (service *RoleServiceImpl) DeleteRole(id uint) error {
+	// 删除角色及其与用户、权限的关联
+	role, err := service.GetRole(id)
+	if err != nil {
+		return err
+	}
+	userIDs, err := service.roleUserIDs(id)
+	if err != nil {
+		return exceptions.GetDefinedErrors(exception.RoleDeleteFailed)
+	}
+	err = service.session.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(role).Association("Permissions").Clear(); err != nil {
+			return err
+		}
+		if err := tx.Where("role_id=?", id).Delete(&model.UserRole{}).Error; err != nil {
+			return err
+		}
+		return tx.Delete(role).Error
+	})
+	if err != nil {
+		return exceptions.GetDefinedErrors(exception.RoleDeleteFailed)
+	}
+	service.evictUserPermissions(userIDs...)
+	return nil
+}
+
+func (service *RoleServiceImpl) GetRole(id uint) (*model.Role, error) {
+	role := &model.Role{}
+	if result := service.session.Preload("Permissions").Where("id=?", id).Take(role); result.Error != nil {
+		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+			return nil, exceptions.GetDefinedErrors(exception.RoleNotFound)
+		}
+		return nil, result.Error
+	}
+	return role, nil
+}
+
+func (service *RoleServiceImpl) ListRoles() ([]model.Role, error) {
+	var roles []model.Role
+	if result := service.session.Preload("Permissions").Order("id").Find(&roles); result.Error != nil {
+		return nil, result.Error
+	}
+	return roles, nil
+}
+
+func (service *RoleServiceImpl) ListPermissions() ([]model.Permission, error) {
+	var permissions []model.Permission
+	if result := service.session.Order("code").Find(&permissions); result.Error != nil {
+		return nil, result.Error
+	}
+	return permissions, nil
+}
+
+func (service *RoleServiceImpl) AssignRoles(userID uint, roleIDs ...uint) error {
+	// 为用户分配角色(已分配的角色忽略)
+	err := service.session.Transaction(func(tx *gorm.DB) error {
+		for _, roleID := range roleIDs {
+			var count int64
+			if err := tx.Model(&model.Role{}).Where("id=?", roleID).Count(&count).Error; err != nil {
+				return err
+			}
+			if count == 0 {
+				return exceptions.GetDefinedErrors(exception.RoleNotFound)
+			}
+			userRole := model.UserRole{}
+			if err := tx.Where(model.UserRole{UserID: userID, RoleID: roleID}).FirstOrCreate(&userRole).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		if e, ok := err.(*exceptions.ApiError); ok {
+			return e
+		}
+		return exceptions.GetDefinedErrors(exception.AssignRoleFailed)
+	}
+	service.evictUserPermissions(userID)
+	return nil
+}
+
+func (service *RoleServiceImpl) UnassignRole(userID uint, roleID uint) error {
+	if result := service.session.Where("user_id=? AND role_id=?", userID, roleID).Delete(&model.UserRole{}); result.Error != nil {
+		return exceptions.GetDefinedErrors(exception.AssignRoleFailed)
+	}
+	service.evictUserPermissions(userID)
+	return nil
+}
+
+func (service *RoleServiceImpl) GetUserRoles(userID uint) ([]model.Role, error) {
+	var roles []model.Role
+	result := service.session.Preload("Permissions").
+		Joins("JOIN user_roles ON user_roles.role_id = roles.id").
+		Where("user_roles.user_id=?", userID).Order("roles.id").Find(&roles)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return roles, nil
+}
+
+func (service *RoleServiceImpl) GetUserPermissions(userID uint) ([]string, error) {
+	// 优先从缓存中获取用户权限
+	var permissions []string
+	val, err := service.rdb.Get(service.ctx, userPermissionsKey(userID)).Result()
+	if err == nil {
+		if err = json.Unmarshal([]byte(val), &permissions); err == nil {
+			return permissions, nil
+		}
+	} else if err != redis.Nil {
+		service.logger.Warn("get user permissions from cache failed", zap.Error(err))
+	}
+
+	result := service.session.Model(&model.Permission{}).Distinct("permissions.code").
+		Joins("JOIN role_permissions ON role_permissions.permission_id = permissions.id").
+		Joins("JOIN user_roles ON user_roles.role_id = role_permissions.role_id").
+		Where("user_roles.user_id=?", userID).Pluck("permissions.code", &permissions)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	if permissions == nil {
+		permissions = []string{}
+	}
+	if v, e := json.Marshal(permissions); e == nil {
+		if e = service.rdb.Set(service.ctx, userPermissionsKey(userID), v, userPermissionsExpires).Err(); e != nil {
+			service.logger.Warn("set user permissions cache failed", zap.Error(e))
+		}
+	}
+	return permissions, nil
+}
+
+func (service *RoleServiceImpl) HasPermission(userID uint, permission string) (bool, error) {
+	permissions, err := service.GetUserPermissions(userID)
+	if err != nil {
+		return false, err
+	}
+	for _, p := range permissions {
+		if p == permission || p == model.PermissionAll {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func (service *RoleServiceImpl) HasRole(userID uint, roleName string) (bool, error) {
+	var count int64
+	result := service.session.Model(&model.UserRole{}).
+		Joins("JOIN roles ON roles.id = user_roles.role_id").
+		Where("user_roles.user_id=? AND roles.name=?", userID, roleName).Count(&count)
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return count > 0, nil
+}
+
+func (service *RoleServiceImpl) roleUserIDs(roleID uint) ([]uint, error) {
+	var userIDs []uint
+	if result := service.session.Model(&model.UserRole{}).Where("role_id=?", roleID).Pluck("user_id", &userIDs); result.Error != nil {
+		return nil, result.Error
+	}
+	return userIDs, nil
+}
+
+func (service *RoleServiceImpl) evictRoleUsers(roleID uint) {
+	userIDs, err := service.roleUserIDs(roleID)
+	if err != nil {
+		service.logger.Warn("get role users failed", zap.Error(err))
+		return
+	}
+	service.evictUserPermissions(userIDs...)
+}
+
+func (service *RoleServiceImpl) evictUserPermissions(userIDs ...uint) {
+	if len(userIDs) == 0 {
+		return
+	}
+	keys := make([]string, 0, len(userIDs))
+	for _, userID := range userIDs {
+		keys = append(keys, userPermissionsKey(userID))
+	}
+	if err := service.rdb.Del(service.ctx, keys...).Err(); err != nil {
+		service.logger.Warn("evict user permissions cache failed", zap.Error(err))
+	}
+}
@@ -0,0 +1,194 @@
+package impl
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"com.github.gin-common/app/exception"
+	"com.github.gin-common/app/service"
+	"com.github.gin-common/common/exceptions"
+	"com.github.gin-common/tools/jwt_tool"
+
+	"github.com/go-redis/redis/v8"
+)
+
+// 基于redis的token及登录会话存储，供AuthService与UserService共用
+type SessionStore struct {
+	ctx context.Context
+	rdb *redis.Client
+}
+
+func (store *SessionStore) Init(rdb *redis.Client, ctx context.Context) {
+	store.rdb = rdb
+	store.ctx = ctx
+}
+
+const accessTokenKeyPrefix = "accessToken:"
+
+func accessTokenKey(tokenUUID string) string {
+	return accessTokenKeyPrefix + tokenUUID
+}
+
+func refreshTokenKey(tokenHash string) string {
+	return fmt.Sprintf("refreshToken:%s", tokenHash)
+}
+
+func refreshTokenUsedKey(tokenHash string) string {
+	return fmt.Sprintf("refreshTokenUsed:%s", tokenHash)
+}
+
+func tokenFamilyKey(familyID string) string {
+	return fmt.Sprintf("tokenFamily:%s", familyID)
+}
+
+// 用户会话索引 hash: sessionID(token family) -> 会话信息
+func userSessionsKey(userID uint) string {
+	return fmt.Sprintf("userSessions:%d", userID)
+}
+
+func (store *SessionStore) SaveSession(session service.Session) error {
+	_, refreshExpire, err := getTokenExpire()
+	if err != nil {
+		return err
+	}
+	value, err := json.Marshal(session)
+	if err != nil {
+		return err
+	}
+	_, err = store.rdb.TxPipelined(store.ctx, func(pipe redis.Pipeliner) error {
+		pipe.HSet(store.ctx, userSessionsKey(session.UserID), session.ID, value)
+		pipe.Expire(store.ctx, userSessionsKey(session.UserID), time.Duration(refreshExpire)*time.Second)
+		return nil
+	})
+	return err
+}
+
+// 刷新token时更新会话的刷新时间
+func (store *SessionStore) TouchSession(userID uint, sessionID string) {
+	val, err := store.rdb.HGet(store.ctx, userSessionsKey(userID), sessionID).Result()
+	if err != nil {
+		return
+	}
+	var session service.Session
+	if err = json.Unmarshal([]byte(val), &session); err != nil {
+		return
+	}
+	now := time.Now()
+	session.RefreshedAt = &now
+	_ = store.SaveSession(session)
+}
+
+func (store *SessionStore) ListSessions(userID uint) ([]service.Session, error) {
+	values, err := store.rdb.HGetAll(store.ctx, userSessionsKey(userID)).Result()
+	if err != nil {
+		return nil, err
+	}
+	sessions := make([]service.Session, 0, len(values))
+	var expired []string
+	for sessionID, val := range values {
+		// token family已过期或被注销的会话从索引中清理
+		exists, err := store.rdb.Exists(store.ctx, tokenFamilyKey(sessionID)).Result()
+		if err != nil {
+			return nil, err
+		}
+		var session service.Session
+		if exists == 0 || json.Unmarshal([]byte(val), &session) != nil {
+			expired = append(expired, sessionID)
+			continue
+		}
+		sessions = append(sessions, session)
+	}
+	if len(expired) > 0 {
+		store.rdb.HDel(store.ctx, userSessionsKey(userID), expired...)
+	}
+	sort.Slice(sessions, func(i, j int) bool {
+		return sessions[i].LoginAt.After(sessions[j].LoginAt)
+	})
+	return sessions, nil
+}
+
+func (store *SessionStore) RevokeSession(userID uint, sessionID string) error {
+	exists, err := store.rdb.HExists(store.ctx, userSessionsKey(userID), sessionID).Result()
+	if err != nil {
+		return err
+	}
+	if !exists {
+		return exceptions.GetDefinedErrors(exception.SessionNotFound)
+	}
+	if err = store.RevokeFamily(sessionID); err != nil {
+		return err
+	}
+	return store.rdb.HDel(store.ctx, userSessionsKey(userID), sessionID).Err()
+}
+
+func (store *SessionStore) RevokeAllSessions(userID uint, exceptSessionIDs ...string) error {
+	sessionIDs, err := store.rdb.HKeys(store.ctx, userSessionsKey(userID)).Result()
+	if err != nil {
+		return err
+	}
+	except := make(map[string]bool, len(exceptSessionIDs))
+	for _, id := range exceptSessionIDs {
+		except[id] = true
+	}
+	for _, sessionID := range sessionIDs {
+		if except[sessionID] {
+			continue
+		}
+		if err = store.RevokeFamily(sessionID); err != nil {
+			return err
+		}
+		if err = store.rdb.HDel(store.ctx, userSessionsKey(userID), sessionID).Err(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (store *SessionStore) RevokeFamily(familyID string) error {
+	// 删除token family中的全部token
+	keys, err := store.rdb.SMembers(store.ctx, tokenFamilyKey(familyID)).Result()
+	if err != nil {
+		return err
+	}
+	var tokenUUIDs []string
+	for _, key := range keys {
+		if strings.HasPrefix(key, accessTokenKeyPrefix) {
+			tokenUUIDs = append(tokenUUIDs, strings.TrimPrefix(key, accessTokenKeyPrefix))
+		}
+	}
+	if err = store.denyAccessTokens(tokenUUIDs...); err != nil {
+		return err
+	}
+	keys = append(keys, tokenFamilyKey(familyID))
+	return store.rdb.Del(store.ctx, keys...).Err()
+}
+
+// 注销单个access token
+func (store *SessionStore) RevokeAccessToken(tokenUUID string) error {
+	if err := store.denyAccessTokens(tokenUUID); err != nil {
+		return err
+	}
+	return store.rdb.Del(store.ctx, accessTokenKey(tokenUUID)).Err()
+}
+
+// 将access token加入注销列表，使无状态模式下已签发的token同样失效
+func (store *SessionStore) denyAccessTokens(tokenUUIDs ...string) error {
+	if len(tokenUUIDs) == 0 {
+		return nil
+	}
+	accessExpire, _, err := getTokenExpire()
+	if err != nil {
+		return err
+	}
+	expiresAt := time.Now().Add(time.Duration(accessExpire) * time.Second)
+	for _, tokenUUID := range tokenUUIDs {
+		if err = jwt_tool.DefaultDenylist().Revoke(store.ctx, tokenUUID, expiresAt); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -2,12 +2,24 @@ package impl
 
 import (
 	"context"
+	"encoding/base64"
+	"encoding/json"
 	"errors"
 	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
 	"time"
 
+	"com.github.gin-common/tools/jwt_tool"
+	"com.github.gin-common/tools/mail_tool"
+	"com.github.gin-common/tools/password_tool"
 	"com.github.gin-common/util"
 
+	"github.com/dgrijalva/jwt-go"
+	"github.com/gin-gonic/gin"
+
 	"go.uber.org/zap"
 
 	"github.com/go-redis/redis/v8"
@@ -15,11 +27,12 @@ import (
 	"com.github.gin-common/common/caches"
 
 	"com.github.gin-common/common/models"
+	"com.github.gin-common/common/resp"
 
 	"com.github.gin-common/app/exception"
 	"com.github.gin-common/app/model"
+	"com.github.gin-common/app/service"
 	"com.github.gin-common/common/exceptions"
-	"github.com/go-sql-driver/mysql"
 	"gorm.io/gorm"
 )
 
@@ -28,85 +41,183 @@ type UserServiceImpl struct {
 	rdb     *redis.Client
 	ctx     context.Context
 	logger  zap.Logger
+	mailer  mail_tool.Mailer
+	users   *models.Repository
 }
 
-func (service *UserServiceImpl) Init(session *gorm.DB, rdb *redis.Client, ctx context.Context, logger zap.Logger) {
+func (service *UserServiceImpl) Init(session *gorm.DB, rdb *redis.Client, ctx context.Context, logger zap.Logger, mailer mail_tool.Mailer) {
 	service.session = session
 	service.rdb = rdb
 	service.ctx = ctx
 	service.logger = logger
+	service.mailer = mailer
+	service.users = models.NewRepository(session, &model.User{},
+		models.WithNotFoundError(exception.UserNotFound), models.WithDuplicateError(exception.UserNameDuplicate))
+}
+
+// 记录不存在、用户名重复等ApiError原样返回，其他错误转换为failed
+func userRepositoryError(err error, failed exceptions.ApiErrorDefFunc) error {
+	if _, ok := err.(*exceptions.ApiError); ok {
+		return err
+	}
+	return exceptions.GetDefinedErrors(failed)
 }
 
 func (service *UserServiceImpl) CreateUser(user *model.User, password string) (*model.User, error) {
 	// 创建用户
-	e := user.SetPass(password)
-	if e != nil {
-		return nil, exceptions.GetDefinedErrors(exception.UserCreateFailed)
+	if err := service.applyPassword(service.session, user, password); err != nil {
+		return nil, err
 	}
 	user.ActivateAt = time.Now()
-	result := service.session.Create(user)
-	if result.Error != nil {
-		if err, ok := result.Error.(*mysql.MySQLError); ok {
-			if err.Number == uint16(1062) {
-				return nil, exceptions.GetDefinedErrors(exception.UserNameDuplicate)
-			}
-		}
-		return nil, exceptions.GetDefinedErrors(exception.UserCreateFailed)
+	if err := service.users.Create(user); err != nil {
+		return nil, userRepositoryError(err, exception.UserCreateFailed)
 	}
-
 	return user, nil
 }
 
-func (service *UserServiceImpl) UpdateUser(id uint, updateInfo model.User) (*model.User, error) {
+func (service *UserServiceImpl) UpdateUser(id uint, updateInfo model.User, expectedVersion *uint) (*model.User, error) {
 	// 更新用户
-
-	var user, err = service.GetUserInfoById(id)
-	if err != nil {
-		return nil, err
+	user := &model.User{}
+	if err := service.users.Get(id, user); err != nil {
+		return nil, userRepositoryError(err, exception.UserUpdateFailed)
 	}
-	if result := service.session.Model(user).Updates(updateInfo); result.Error != nil {
-		return nil, exceptions.GetDefinedErrors(exception.UserUpdateFailed)
+	if expectedVersion != nil {
+		// 按客户端读取时的版本更新，期间被修改过则返回Conflict
+		user.Version = *expectedVersion
 	}
-	return user, nil
+	emailChanged := updateInfo.Email != "" && updateInfo.Email != user.Email
+	if err := service.users.Update(user, updateInfo); err != nil {
+		return nil, userRepositoryError(err, exception.UserUpdateFailed)
+	}
+	if emailChanged {
+		// 修改邮箱后需重新验证
+		if err := service.users.Update(user, map[string]interface{}{"email_verified_at": nil}); err != nil {
+			return nil, userRepositoryError(err, exception.UserUpdateFailed)
+		}
+	}
+	return user, service.deleteUserCache(id)
 }
 
 func (service *UserServiceImpl) DeleteUser(id uint) error {
 	// 删除用户
-	user, err := service.GetUserInfoById(id)
-	if err != nil {
-		return err
+	if err := service.users.SoftDelete(id, nil); err != nil {
+		return userRepositoryError(err, exception.DeleteUserFailed)
 	}
-	if result := models.Delete(service.session, id, user); result.Error != nil {
-		return exceptions.GetDefinedErrors(exception.DeleteUserFailed)
+	return service.invalidateUser(id)
+}
+
+func (service *UserServiceImpl) RestoreUser(id uint) (*model.User, error) {
+	// 恢复已删除的用户
+	user := &model.User{}
+	if err := service.users.Restore(id, user); err != nil {
+		return nil, userRepositoryError(err, exception.RestoreUserFailed)
 	}
-	return nil
+	return user, service.deleteUserCache(id)
+}
+
+// 用户的关联数据，物理删除用户时一并删除
+var userOwnedModels = []interface{}{
+	&model.UserRole{}, &model.ApiKey{}, &model.UserMFA{}, &model.MFARecoveryCode{},
+	&model.UserIdentity{}, &model.OAuthConsent{}, &model.PasswordHistory{},
+}
+
+func (service *UserServiceImpl) PurgeDeletedUsers(before time.Time, limit int) (int64, error) {
+	ids, err := service.users.DeletedBefore(before, limit)
+	if err != nil || len(ids) == 0 {
+		return 0, err
+	}
+	var purged int64
+	err = service.session.Transaction(func(tx *gorm.DB) error {
+		for _, m := range userOwnedModels {
+			if err := tx.Where("user_id IN ?", ids).Delete(m).Error; err != nil {
+				return err
+			}
+		}
+		var err error
+		purged, err = service.users.WithSession(tx).Purge(ids)
+		return err
+	})
+	return purged, err
 }
 
 func (service *UserServiceImpl) ActivateUser(id uint) (*model.User, error) {
 	// 启用用户
-	user, err := service.GetUserInfoById(id)
-	if err != nil {
-		return nil, err
-	}
-	if result := models.Activate(service.session, id, user); result.Error != nil {
-		return nil, exceptions.GetDefinedErrors(exception.ActivateUserFailed)
+	user := &model.User{}
+	if err := service.users.Activate(id, user); err != nil {
+		return nil, userRepositoryError(err, exception.ActivateUserFailed)
 	}
-	return user, nil
+	return user, service.deleteUserCache(id)
 }
 
 func (service *UserServiceImpl) DeactivateUser(id uint) (*model.User, error) {
 	// 禁用用户
-	user, err := service.GetUserInfoById(id)
-	if err != nil {
-		return nil, err
+	user := &model.User{}
+	if err := service.users.Deactivate(id, user); err != nil {
+		return nil, userRepositoryError(err, exception.DeActivateUserFailed)
 	}
-	if result := models.Deactivate(service.session, id, user); result.Error != nil {
-		return nil, exceptions.GetDefinedErrors(exception.DeActivateUserFailed)
+	if err := service.invalidateUser(id); err != nil {
+		return nil, err
 	}
 	return user, nil
 }
 
+// 无状态模式下鉴权不再访问redis，用户信息缓存在进程内
+// 用户变更时通过注销列表的频道通知各实例清除，过期时间兜底pub/sub断线期间丢失的通知
+const localUserExpires = time.Minute
+
+type localUser struct {
+	user      model.User
+	expiresAt time.Time
+}
+
+var (
+	localUsers     = map[uint]localUser{}
+	localUsersMu   sync.RWMutex
+	localUsersOnce sync.Once
+)
+
+func getLocalUser(id uint) (*model.User, bool) {
+	localUsersMu.RLock()
+	cached, ok := localUsers[id]
+	localUsersMu.RUnlock()
+	if !ok || time.Now().After(cached.expiresAt) {
+		return nil, false
+	}
+	user := cached.user
+	return &user, true
+}
+
+func setLocalUser(user *model.User) {
+	localUsersOnce.Do(func() {
+		jwt_tool.DefaultDenylist().OnUserChanged(deleteLocalUser)
+	})
+	localUsersMu.Lock()
+	defer localUsersMu.Unlock()
+	localUsers[user.ID] = localUser{user: *user, expiresAt: time.Now().Add(localUserExpires)}
+}
+
+func deleteLocalUser(id uint) {
+	localUsersMu.Lock()
+	defer localUsersMu.Unlock()
+	delete(localUsers, id)
+}
+
 func (service *UserServiceImpl) GetUserInfoById(id uint) (*model.User, error) {
+	if !jwt_tool.StatelessMode() {
+		return service.getCachedUser(id)
+	}
+	if user, ok := getLocalUser(id); ok {
+		return user, nil
+	}
+	user, err := service.getCachedUser(id)
+	if err != nil {
+		return nil, err
+	}
+	setLocalUser(user)
+	return user, nil
+}
+
+func (service *UserServiceImpl) getCachedUser(id uint) (*model.User, error) {
 	user := &model.User{}
 
 	bridge := func(process func(id uint) (*model.User, error), id uint) func() (interface{}, error) {
@@ -141,11 +252,8 @@ func (service *UserServiceImpl) GetUserInfoById(id uint) (*model.User, error) {
 
 func (service *UserServiceImpl) getUserInfoById(id uint) (*model.User, error) {
 	user := &model.User{}
-	if result := service.session.Where("ID=?", id).Take(user); result.Error != nil {
-		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
-			return nil, exceptions.GetDefinedErrors(exception.UserNotFound)
-		}
-		return nil, result.Error
+	if err := service.users.Get(id, user); err != nil {
+		return nil, err
 	}
 	return user, nil
 }
@@ -162,19 +270,556 @@ func (service *UserServiceImpl) GetUserInfoByUserName(username string) (*model.U
 	return user, nil
 }
 
-func (service *UserServiceImpl) ChangePassword(id uint, oldPass string, newPass string) error {
-	// 修改密码
-	user, err := service.GetUserInfoById(id)
+func (service *UserServiceImpl) ChangePassword(id uint, oldPass string, newPass string, keepSessionIDs ...string) error {
+	// 修改密码，使用数据库中的最新数据避免覆盖并发修改
+	user, err := service.getUserInfoById(id)
 	if err != nil {
 		return err
 	}
-	if user.CheckPass(oldPass) {
-		if err := user.SetPass(newPass); err != nil {
-			return exceptions.GetDefinedErrors(exception.ChangePassFailed)
-		}
-	} else {
+	if !user.CheckPass(oldPass) {
 		return exceptions.GetDefinedErrors(exception.OldPassInvalid)
 	}
-	service.session.Save(user)
+	if err = service.savePassword(user, newPass); err != nil {
+		return err
+	}
+	return service.invalidateUser(id, keepSessionIDs...)
+}
+
+func (service *UserServiceImpl) SetPassword(id uint, newPass string) error {
+	// 不校验旧密码直接设置(用于密码过期后强制修改)
+	user, err := service.getUserInfoById(id)
+	if err != nil {
+		return err
+	}
+	if err = service.savePassword(user, newPass); err != nil {
+		return err
+	}
+	return service.invalidateUser(id)
+}
+
+func (service *UserServiceImpl) PasswordExpired(user *model.User) (bool, error) {
+	policy, err := password_tool.DefaultPolicy()
+	if err != nil {
+		return false, err
+	}
+	return policy.Expired(user.PasswordChangedTime(), time.Now()), nil
+}
+
+// 按密码策略校验并设置新密码，已创建的用户会检查并记录历史密码
+func (service *UserServiceImpl) applyPassword(tx *gorm.DB, user *model.User, newPass string) error {
+	policy, err := password_tool.DefaultPolicy()
+	if err != nil {
+		return err
+	}
+	if violations := policy.Validate(newPass, user.Username); len(violations) > 0 {
+		return exceptions.NewError(exception.PasswordPolicyViolated, exceptions.WithData(gin.H{
+			"violations": violations,
+		}))()
+	}
+	oldHash := user.Password
+	if user.ID != 0 && policy.HistorySize > 0 {
+		hashers, err := password_tool.DefaultHashers()
+		if err != nil {
+			return err
+		}
+		var history []model.PasswordHistory
+		if result := tx.Where("user_id = ?", user.ID).Order("id desc").Limit(policy.HistorySize).Find(&history); result.Error != nil {
+			return result.Error
+		}
+		hashes := []string{oldHash}
+		for _, h := range history {
+			hashes = append(hashes, h.Hash)
+		}
+		for _, hash := range hashes {
+			if hash != "" && hashers.Verify(hash, newPass) {
+				return exceptions.GetDefinedErrors(exception.PasswordReused)
+			}
+		}
+	}
+	if err = user.SetPass(newPass); err != nil {
+		return exceptions.GetDefinedErrors(exception.ChangePassFailed)
+	}
+	now := time.Now()
+	user.PasswordChangedAt = &now
+	if user.ID == 0 || oldHash == "" || policy.HistorySize <= 0 {
+		return nil
+	}
+	if result := tx.Create(&model.PasswordHistory{UserID: user.ID, Hash: oldHash}); result.Error != nil {
+		return result.Error
+	}
+	// 只保留最近HistorySize条
+	var expired []uint
+	if result := tx.Model(&model.PasswordHistory{}).Where("user_id = ?", user.ID).Order("id desc").Offset(policy.HistorySize).Pluck("id", &expired); result.Error != nil {
+		return result.Error
+	}
+	if len(expired) > 0 {
+		return tx.Where("id IN ?", expired).Delete(&model.PasswordHistory{}).Error
+	}
+	return nil
+}
+
+func (service *UserServiceImpl) savePassword(user *model.User, newPass string) error {
+	err := service.session.Transaction(func(tx *gorm.DB) error {
+		if err := service.applyPassword(tx, user, newPass); err != nil {
+			return err
+		}
+		return service.users.WithSession(tx).WithAction("change_password").Update(user, map[string]interface{}{
+			"password":            user.Password,
+			"password_changed_at": user.PasswordChangedAt,
+		})
+	})
+	if err != nil {
+		if _, ok := err.(*exceptions.ApiError); ok {
+			return err
+		}
+		return exceptions.GetDefinedErrors(exception.ChangePassFailed)
+	}
+	return nil
+}
+
+func (service *UserServiceImpl) RehashPassword(user *model.User, password string) error {
+	// 使用当前首选算法重新哈希，不修改密码修改时间和历史记录
+	if err := user.SetPass(password); err != nil {
+		return err
+	}
+	if err := service.users.WithAction("rehash_password").Update(user, map[string]interface{}{"password": user.Password}); err != nil {
+		return err
+	}
+	return service.deleteUserCache(user.ID)
+}
+
+func (service *UserServiceImpl) invalidateUser(id uint, keepSessionIDs ...string) error {
+	// 注销用户的登录会话(keepSessionIDs除外)，并清除用户缓存
+	store := new(SessionStore)
+	store.Init(service.rdb, service.ctx)
+	if err := store.RevokeAllSessions(id, keepSessionIDs...); err != nil {
+		return err
+	}
+	return service.deleteUserCache(id)
+}
+
+func (service *UserServiceImpl) deleteUserCache(id uint) error {
+	redisCache := new(caches.RedisCache)
+	redisCache.Init(service.rdb, service.ctx)
+	if err := redisCache.Delete(fmt.Sprintf("user:%d", id)); err != nil {
+		return err
+	}
+	if jwt_tool.StatelessMode() {
+		return jwt_tool.DefaultDenylist().NotifyUserChanged(service.ctx, id)
+	}
 	return nil
 }
+
+const (
+	userTokenPasswordReset = "password_reset"
+	userTokenEmailVerify   = "email_verify"
+)
+
+// 记录未使用的token，存放于redis userToken:<jti>，使用后删除
+func userTokenKey(jti string) string {
+	return fmt.Sprintf("userToken:%s", jti)
+}
+
+// token与用户当前状态绑定：修改密码后重置链接失效，修改邮箱后验证链接失效
+func userTokenBinding(user *model.User, purpose string) string {
+	if purpose == userTokenPasswordReset {
+		return util.SHA256Hex(user.Password)[:16]
+	}
+	return util.SHA256Hex(user.Email)[:16]
+}
+
+// 用途作为受众的一部分，与登录token互不通用
+func userTokenPolicy(purpose string) (*jwt_tool.ClaimsPolicy, error) {
+	p, err := jwt_tool.DefaultClaimsPolicy()
+	if err != nil {
+		return nil, err
+	}
+	policy := *p
+	policy.Audience = fmt.Sprintf("%s:%s", p.Audience, purpose)
+	policy.AllowedAudiences = []string{policy.Audience}
+	return &policy, nil
+}
+
+// 创建一次性、限时的签名token
+func (service *UserServiceImpl) createUserToken(user *model.User, purpose string, expire int) (string, error) {
+	policy, err := userTokenPolicy(purpose)
+	if err != nil {
+		return "", err
+	}
+	claims := jwt.MapClaims{
+		"sub":  strconv.Itoa(int(user.ID)),
+		"aud":  policy.Audience,
+		"bind": userTokenBinding(user, purpose),
+	}
+	tokenInfo, err := jwt_tool.CreateToken(claims, expire)
+	if err != nil {
+		return "", err
+	}
+	err = service.rdb.Set(service.ctx, userTokenKey(claims["jti"].(string)), purpose, time.Duration(expire)*time.Second).Err()
+	if err != nil {
+		return "", err
+	}
+	return tokenInfo.Token, nil
+}
+
+// 校验token，返回对应用户及jti(调用consumeUserToken后才失效)
+func (service *UserServiceImpl) verifyUserToken(token string, purpose string) (*model.User, string, error) {
+	policy, err := userTokenPolicy(purpose)
+	if err != nil {
+		return nil, "", err
+	}
+	claims, err := jwt_tool.ParseToken(token, policy)
+	if err != nil {
+		return nil, "", exceptions.GetDefinedErrors(exception.UserTokenInvalid)
+	}
+	jti, _ := claims["jti"].(string)
+	sub, _ := claims["sub"].(string)
+	bind, _ := claims["bind"].(string)
+	userID, err := strconv.Atoi(sub)
+	if err != nil {
+		return nil, "", exceptions.GetDefinedErrors(exception.UserTokenInvalid)
+	}
+	stored, err := service.rdb.Get(service.ctx, userTokenKey(jti)).Result()
+	if err != nil || stored != purpose {
+		return nil, "", exceptions.GetDefinedErrors(exception.UserTokenInvalid)
+	}
+	user, err := service.getUserInfoById(uint(userID))
+	if err != nil || userTokenBinding(user, purpose) != bind {
+		return nil, "", exceptions.GetDefinedErrors(exception.UserTokenInvalid)
+	}
+	return user, jti, nil
+}
+
+// 仅当token仍对应该用途时删除，比较与删除须原子执行
+var consumeUserTokenScript = redis.NewScript(`
+if redis.call("GET", KEYS[1]) == ARGV[1] then
+	return redis.call("DEL", KEYS[1])
+end
+return 0`)
+
+// 在数据库事务提交前调用：更新成功后才使token失效，失效失败时回滚更新
+func (service *UserServiceImpl) consumeUserToken(jti string, purpose string) error {
+	deleted, err := consumeUserTokenScript.Run(service.ctx, service.rdb, []string{userTokenKey(jti)}, purpose).Int64()
+	if err != nil {
+		return err
+	}
+	if deleted == 0 {
+		return exceptions.GetDefinedErrors(exception.UserTokenInvalid)
+	}
+	return nil
+}
+
+func userTokenLink(base string, token string) (string, error) {
+	u, err := url.Parse(base)
+	if err != nil {
+		return "", err
+	}
+	query := u.Query()
+	query.Set("token", token)
+	u.RawQuery = query.Encode()
+	return u.String(), nil
+}
+
+func (service *UserServiceImpl) sendUserTokenMail(user *model.User, purpose string, subject string, body string) error {
+	expireEnv, urlEnv, defaultExpire, defaultURL := "PASSWORD_RESET_EXPIRE", "PASSWORD_RESET_URL", "1800", "http://localhost:8080/password/reset"
+	if purpose == userTokenEmailVerify {
+		expireEnv, urlEnv, defaultExpire, defaultURL = "EMAIL_VERIFY_EXPIRE", "EMAIL_VERIFY_URL", "86400", "http://localhost:8080/email/verify"
+	}
+	expire, err := strconv.Atoi(util.GetDefaultEnv(expireEnv, defaultExpire))
+	if err != nil {
+		return err
+	}
+	token, err := service.createUserToken(user, purpose, expire)
+	if err != nil {
+		return err
+	}
+	link, err := userTokenLink(util.GetDefaultEnv(urlEnv, defaultURL), token)
+	if err != nil {
+		return err
+	}
+	err = service.mailer.Send(service.ctx, mail_tool.Message{
+		To:      []string{user.Email},
+		Subject: subject,
+		Body:    fmt.Sprintf(body, user.Username, link, expire/60),
+	})
+	if err != nil {
+		service.logger.Error("send mail failed", zap.String("purpose", purpose), zap.Error(err))
+		return exceptions.GetDefinedErrors(exception.MailSendFailed)
+	}
+	return nil
+}
+
+func (service *UserServiceImpl) RequestPasswordReset(email string) error {
+	var users []model.User
+	if result := service.session.Where("email = ?", email).Find(&users); result.Error != nil {
+		return result.Error
+	}
+	// 异步发送且不返回发送结果，避免通过响应时间或错误判断邮箱是否已注册
+	go func() {
+		for i := range users {
+			if !users[i].ActivateStatus {
+				continue
+			}
+			err := service.sendUserTokenMail(&users[i], userTokenPasswordReset, "重置密码",
+				"%s，您好：\n\n请点击以下链接重置密码：\n%s\n\n链接%d分钟内有效且只能使用一次，如非本人操作请忽略此邮件。\n")
+			if err != nil {
+				service.logger.Error("send password reset mail failed", zap.Uint("user", users[i].ID), zap.Error(err))
+			}
+		}
+	}()
+	return nil
+}
+
+func (service *UserServiceImpl) ResetPassword(token string, newPass string) error {
+	user, jti, err := service.verifyUserToken(token, userTokenPasswordReset)
+	if err != nil {
+		return err
+	}
+	err = service.session.Transaction(func(tx *gorm.DB) error {
+		if err := service.applyPassword(tx, user, newPass); err != nil {
+			return err
+		}
+		err := service.users.WithSession(tx).WithAction("reset_password").Update(user, map[string]interface{}{
+			"password":            user.Password,
+			"password_changed_at": user.PasswordChangedAt,
+		})
+		if err != nil {
+			return err
+		}
+		return service.consumeUserToken(jti, userTokenPasswordReset)
+	})
+	if err != nil {
+		if _, ok := err.(*exceptions.ApiError); ok {
+			return err
+		}
+		return exceptions.GetDefinedErrors(exception.ChangePassFailed)
+	}
+	return service.invalidateUser(user.ID)
+}
+
+func (service *UserServiceImpl) SendEmailVerification(id uint) error {
+	user, err := service.getUserInfoById(id)
+	if err != nil {
+		return err
+	}
+	if user.Email == "" {
+		return exceptions.GetDefinedErrors(exception.EmailNotSet)
+	}
+	if user.EmailVerified() {
+		return exceptions.GetDefinedErrors(exception.EmailAlreadyVerified)
+	}
+	return service.sendUserTokenMail(user, userTokenEmailVerify, "验证邮箱",
+		"%s，您好：\n\n请点击以下链接验证邮箱：\n%s\n\n链接%d分钟内有效且只能使用一次。\n")
+}
+
+func (service *UserServiceImpl) VerifyEmail(token string) error {
+	user, jti, err := service.verifyUserToken(token, userTokenEmailVerify)
+	if err != nil {
+		return err
+	}
+	err = service.session.Transaction(func(tx *gorm.DB) error {
+		err := service.users.WithSession(tx).WithAction("verify_email").Update(user, map[string]interface{}{"email_verified_at": time.Now()})
+		if err != nil {
+			return userRepositoryError(err, exception.UserUpdateFailed)
+		}
+		return service.consumeUserToken(jti, userTokenEmailVerify)
+	})
+	if err != nil {
+		return err
+	}
+	return service.deleteUserCache(user.ID)
+}
+
+func (service *UserServiceImpl) ListUsers(query service.UserListQuery) (*service.UserListResult, error) {
+	return listUsers(service.session, query)
+}
+
+// 用户列表允许过滤及排序的字段
+var userQuerySchema = models.QuerySchema{
+	Fields: map[string]models.QueryField{
+		"id":              {Type: models.FieldInt, Filterable: true, Sortable: true},
+		"username":        {Type: models.FieldString, Filterable: true, Sortable: true},
+		"name":            {Type: models.FieldString, Filterable: true},
+		"email":           {Type: models.FieldString, Filterable: true},
+		"activate_status": {Type: models.FieldBool, Filterable: true},
+		"created_at":      {Type: models.FieldTime, Filterable: true, Sortable: true},
+		"updated_at":      {Type: models.FieldTime, Filterable: true, Sortable: true},
+	},
+	DefaultSort:     "-id",
+	DefaultPageSize: 20,
+	MaxPageSize:     100,
+}
+
+// cursor分页位置: 上一页最后一条记录的排序字段值和ID
+type userListCursor struct {
+	Value string `json:"v,omitempty"`
+	ID    uint   `json:"id"`
+}
+
+func encodeUserListCursor(user *model.User, field string) (string, error) {
+	cursor := userListCursor{ID: user.ID}
+	switch field {
+	case "username":
+		cursor.Value = user.Username
+	case "created_at":
+		cursor.Value = user.CreatedAt.Format(time.RFC3339Nano)
+	case "updated_at":
+		cursor.Value = user.UpdatedAt.Format(time.RFC3339Nano)
+	}
+	value, err := json.Marshal(cursor)
+	if err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(value), nil
+}
+
+// 解析cursor，返回排序字段的比较值
+func decodeUserListCursor(raw string, field string) (*userListCursor, interface{}, error) {
+	invalid := exceptions.NewError(exceptions.BadRequest, exceptions.WithError(errors.New("cursor无效")))()
+	value, err := base64.RawURLEncoding.DecodeString(raw)
+	if err != nil {
+		return nil, nil, invalid
+	}
+	cursor := &userListCursor{}
+	if err = json.Unmarshal(value, cursor); err != nil {
+		return nil, nil, invalid
+	}
+	switch field {
+	case "username":
+		return cursor, cursor.Value, nil
+	case "created_at", "updated_at":
+		t, err := time.Parse(time.RFC3339Nano, cursor.Value)
+		if err != nil {
+			return nil, nil, invalid
+		}
+		return cursor, t, nil
+	}
+	return cursor, nil, nil
+}
+
+// 将列表条件转换为查询参数，与请求中的filter[...]、page[...]参数合并
+func userListValues(query service.UserListQuery) url.Values {
+	values := url.Values{}
+	for key, items := range query.Values {
+		if strings.HasPrefix(key, "filter[") || strings.HasPrefix(key, "page[") {
+			values[key] = items
+		}
+	}
+	if query.ActivateStatus != nil {
+		values.Add("filter[activate_status]", strconv.FormatBool(*query.ActivateStatus))
+	}
+	if query.CreatedAfter != nil {
+		values.Add("filter[created_at][gte]", query.CreatedAfter.Format(time.RFC3339Nano))
+	}
+	if query.CreatedBefore != nil {
+		values.Add("filter[created_at][lt]", query.CreatedBefore.Format(time.RFC3339Nano))
+	}
+	if query.Sort != "" {
+		values.Set("sort", query.Sort)
+	}
+	if query.Page > 0 {
+		values.Set("page[number]", strconv.Itoa(query.Page))
+	}
+	if query.PageSize > 0 {
+		values.Set("page[size]", strconv.Itoa(query.PageSize))
+	}
+	return values
+}
+
+// QuerySchema无法表达的条件：删除状态及跨字段的模糊搜索
+func userListScope(query service.UserListQuery) func(db *gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		if query.DeleteStatus != nil {
+			if *query.DeleteStatus {
+				db = db.Unscoped()
+			}
+			db = db.Where("delete_status = ?", *query.DeleteStatus)
+		}
+		if query.Search != "" {
+			pattern := "%" + models.EscapeLike(query.Search) + "%"
+			db = db.Where("username LIKE ? OR name LIKE ? OR email LIKE ?", pattern, pattern, pattern)
+		}
+		return db
+	}
+}
+
+func listUsers(session *gorm.DB, query service.UserListQuery) (*service.UserListResult, error) {
+	parsed, err := userQuerySchema.Parse(userListValues(query))
+	if err != nil {
+		return nil, err
+	}
+	if len(parsed.Sorts) == 0 {
+		parsed.Sorts = []models.Sort{{Column: "id", Desc: true}}
+	}
+	scopes := []func(*gorm.DB) *gorm.DB{userListScope(query), parsed.FilterScope()}
+	result := &service.UserListResult{}
+	if !query.CursorMode {
+		return listUsersByOffset(session, parsed, scopes, result)
+	}
+	return listUsersByCursor(session, query.Cursor, parsed, scopes, result)
+}
+
+func listUsersByOffset(session *gorm.DB, parsed *models.Query, scopes []func(*gorm.DB) *gorm.DB, result *service.UserListResult) (*service.UserListResult, error) {
+	// 排序字段相同时按id排序，保证翻页稳定
+	tiebreak := true
+	for _, s := range parsed.Sorts {
+		if s.Column == "id" {
+			tiebreak = false
+		}
+	}
+	if tiebreak {
+		parsed.Sorts = append(parsed.Sorts, models.Sort{Column: "id", Desc: parsed.Sorts[0].Desc})
+	}
+	var total int64
+	if err := session.Model(&model.User{}).Scopes(scopes...).Count(&total).Error; err != nil {
+		return nil, exceptions.GetDefinedErrors(exceptions.ServerError)
+	}
+	if err := session.Model(&model.User{}).Scopes(scopes...).Scopes(parsed.PageScope()).Find(&result.Users).Error; err != nil {
+		return nil, exceptions.GetDefinedErrors(exceptions.ServerError)
+	}
+	result.Pagination = parsed.Pagination(total)
+	return result, nil
+}
+
+// cursor分页按(排序字段, id)定位，只支持单个排序字段
+func listUsersByCursor(session *gorm.DB, rawCursor string, parsed *models.Query, scopes []func(*gorm.DB) *gorm.DB, result *service.UserListResult) (*service.UserListResult, error) {
+	if len(parsed.Sorts) != 1 {
+		return nil, exceptions.NewError(exceptions.BadRequest, exceptions.WithError(errors.New("cursor分页只支持单个排序字段")))()
+	}
+	field, direction, compare := parsed.Sorts[0].Column, "ASC", ">"
+	if parsed.Sorts[0].Desc {
+		direction, compare = "DESC", "<"
+	}
+	db := session.Model(&model.User{}).Scopes(scopes...)
+	if rawCursor != "" {
+		cursor, value, err := decodeUserListCursor(rawCursor, field)
+		if err != nil {
+			return nil, err
+		}
+		if field == "id" {
+			db = db.Where(fmt.Sprintf("id %s ?", compare), cursor.ID)
+		} else {
+			db = db.Where(fmt.Sprintf("%s %s ? OR (%s = ? AND id %s ?)", field, compare, field, compare), value, value, cursor.ID)
+		}
+	}
+	order := fmt.Sprintf("%s %s", field, direction)
+	if field != "id" {
+		order = fmt.Sprintf("%s, id %s", order, direction)
+	}
+	// 多查一条用于判断是否还有下一页
+	if err := db.Order(order).Limit(parsed.PageSize + 1).Find(&result.Users).Error; err != nil {
+		return nil, exceptions.GetDefinedErrors(exceptions.ServerError)
+	}
+	hasMore := len(result.Users) > parsed.PageSize
+	var nextCursor string
+	if hasMore {
+		result.Users = result.Users[:parsed.PageSize]
+		cursor, err := encodeUserListCursor(&result.Users[len(result.Users)-1], field)
+		if err != nil {
+			return nil, err
+		}
+		nextCursor = cursor
+	}
+	result.Pagination = resp.CursorPagination(parsed.PageSize, nextCursor, hasMore)
+	return result, nil
+}
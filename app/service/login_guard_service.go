@@ -0,0 +1,19 @@
+package service
+
+import "com.github.gin-common/app/model"
+
+// 登录防暴力破解：按用户名及IP统计失败次数并临时锁定
+type LoginGuardService interface {
+	// 登录前检查用户名或IP是否被锁定
+	Check(username string, ip string) error
+	// 记录一次失败登录，达到阈值时锁定并返回AccountLocked
+	RecordFailure(username string, ip string) error
+	// 登录成功后清除用户名的失败记录
+	RecordSuccess(username string, ip string)
+	// 管理员解锁用户名和/或IP
+	Unlock(username string, ip string, operatorID uint) error
+	// 找回密码请求按邮箱及IP限流，超过阈值时返回PasswordResetThrottled
+	ThrottlePasswordReset(email string, ip string) error
+	// 获取最近的锁定记录
+	ListLockouts(limit int) ([]model.LoginLockout, error)
+}
@@ -0,0 +1,29 @@
+package service
+
+// 两步验证绑定信息，secret及uri仅在绑定时返回
+type MFAEnrollment struct {
+	Secret string `json:"secret"`
+	// otpauth URI，同时作为二维码内容
+	URI string `json:"uri"`
+}
+
+type MFAStatus struct {
+	Enabled bool `json:"enabled"`
+	// 剩余可用的恢复码数量
+	RecoveryCodesLeft int `json:"recovery_codes_left"`
+}
+
+type MFAService interface {
+	// 生成新的TOTP密钥(确认前不生效)
+	Enroll(userID uint, account string) (*MFAEnrollment, error)
+	// 使用动态码确认绑定，返回一次性恢复码
+	Confirm(userID uint, code string) (recoveryCodes []string, err error)
+	// 使用动态码或恢复码关闭两步验证
+	Disable(userID uint, code string) error
+	// 校验动态码或恢复码
+	Verify(userID uint, code string) error
+	// 是否开启了两步验证
+	IsEnabled(userID uint) (bool, error)
+	// 获取两步验证状态
+	Status(userID uint) (*MFAStatus, error)
+}
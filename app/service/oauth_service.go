@@ -0,0 +1,108 @@
+package service
+
+import (
+	"net/http"
+
+	"com.github.gin-common/app/model"
+)
+
+// 授权端点的请求参数
+type OAuthAuthorizeRequest struct {
+	ResponseType        string
+	ClientID            string
+	RedirectURI         string
+	Scope               string
+	State               string
+	CodeChallenge       string
+	CodeChallengeMethod string
+}
+
+// 授权确认页所需信息
+type OAuthAuthorizeInfo struct {
+	Client          *model.OAuthClient `json:"client"`
+	Scopes          []string           `json:"scopes"`
+	RedirectURI     string             `json:"redirect_uri"`
+	ConsentRequired bool               `json:"consent_required"`
+}
+
+// 客户端认证信息(Basic认证或表单参数)
+type OAuthClientCredentials struct {
+	ClientID     string
+	ClientSecret string
+}
+
+// token端点的请求参数
+type OAuthTokenRequest struct {
+	OAuthClientCredentials
+	GrantType    string
+	Code         string
+	RedirectURI  string
+	CodeVerifier string
+	RefreshToken string
+	Scope        string
+}
+
+// RFC 6749 token响应
+type OAuthToken struct {
+	AccessToken  string `json:"access_token"`
+	TokenType    string `json:"token_type"`
+	ExpiresIn    int    `json:"expires_in"`
+	RefreshToken string `json:"refresh_token,omitempty"`
+	Scope        string `json:"scope,omitempty"`
+}
+
+// RFC 7662 introspection响应
+type OAuthIntrospection struct {
+	Active    bool   `json:"active"`
+	Scope     string `json:"scope,omitempty"`
+	ClientID  string `json:"client_id,omitempty"`
+	Username  string `json:"username,omitempty"`
+	TokenType string `json:"token_type,omitempty"`
+	Exp       int64  `json:"exp,omitempty"`
+	Iat       int64  `json:"iat,omitempty"`
+	Sub       string `json:"sub,omitempty"`
+	Aud       string `json:"aud,omitempty"`
+	Iss       string `json:"iss,omitempty"`
+	Jti       string `json:"jti,omitempty"`
+}
+
+// RFC 6749 错误响应，token、introspect、revoke端点按标准格式输出
+type OAuthError struct {
+	Code        string `json:"error"`
+	Description string `json:"error_description,omitempty"`
+	Status      int    `json:"-"`
+}
+
+func (e *OAuthError) Error() string {
+	if e.Description != "" {
+		return e.Code + ": " + e.Description
+	}
+	return e.Code
+}
+
+func NewOAuthError(code string, description string) *OAuthError {
+	status := http.StatusBadRequest
+	if code == "invalid_client" {
+		status = http.StatusUnauthorized
+	}
+	return &OAuthError{Code: code, Description: description, Status: status}
+}
+
+type OAuthService interface {
+	// 注册客户端，明文secret仅在创建时返回(public客户端为空)
+	CreateClient(client *model.OAuthClient) (created *model.OAuthClient, secret string, err error)
+	// 获取全部客户端
+	ListClients() ([]model.OAuthClient, error)
+	// 删除客户端
+	DeleteClient(id uint) error
+	// 校验授权请求，返回授权确认页所需信息
+	AuthorizeInfo(req OAuthAuthorizeRequest, userID uint) (*OAuthAuthorizeInfo, error)
+	// 用户确认(或拒绝)授权，返回携带code(或错误)的回调地址
+	Authorize(req OAuthAuthorizeRequest, userID uint, approved bool) (redirectTo string, err error)
+	// token端点，支持authorization_code、client_credentials、refresh_token
+	Token(req OAuthTokenRequest) (*OAuthToken, error)
+	// 查询token状态(RFC 7662)
+	Introspect(credentials OAuthClientCredentials, token string, tokenTypeHint string) (*OAuthIntrospection, error)
+	// 注销token(RFC 7009)
+	Revoke(credentials OAuthClientCredentials, token string, tokenTypeHint string) error
+}
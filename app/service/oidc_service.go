@@ -0,0 +1,21 @@
+package service
+
+import "com.github.gin-common/app/model"
+
+// 第三方登录回调结果，绑定流程只返回Identity
+type OIDCCallbackResult struct {
+	Login    *LoginResult
+	Linked   bool
+	Identity *model.UserIdentity
+}
+
+type OIDCService interface {
+	// 生成跳转到身份提供方的授权地址，linkUserID不为0时为绑定流程
+	AuthorizationURL(provider string, linkUserID uint) (string, error)
+	// 处理授权回调：校验ID token，查找/绑定/创建用户并登录
+	Callback(provider string, state string, code string, client ClientInfo) (*OIDCCallbackResult, error)
+	// 获取用户绑定的第三方账号
+	ListIdentities(userID uint) ([]model.UserIdentity, error)
+	// 解除绑定
+	Unlink(userID uint, identityID uint) error
+}
@@ -0,0 +1,32 @@
+package service
+
+import (
+	"com.github.gin-common/app/model"
+)
+
+type RoleService interface {
+	// 创建角色
+	CreateRole(role *model.Role, permissions []string) (*model.Role, error)
+	// 更新角色描述及权限(权限整体替换)
+	UpdateRole(id uint, description string, permissions []string) (*model.Role, error)
+	// 删除角色
+	DeleteRole(id uint) error
+	// 通过ID获取角色
+	GetRole(id uint) (*model.Role, error)
+	// 获取所有角色
+	ListRoles() ([]model.Role, error)
+	// 获取所有已定义的权限
+	ListPermissions() ([]model.Permission, error)
+	// 为用户分配角色
+	AssignRoles(userID uint, roleIDs ...uint) error
+	// 取消用户的角色
+	UnassignRole(userID uint, roleID uint) error
+	// 获取用户的角色
+	GetUserRoles(userID uint) ([]model.Role, error)
+	// 获取用户拥有的权限(带缓存)
+	GetUserPermissions(userID uint) ([]string, error)
+	// 判断用户是否拥有权限
+	HasPermission(userID uint, permission string) (bool, error)
+	// 判断用户是否拥有角色
+	HasRole(userID uint, roleName string) (bool, error)
+}
@@ -1,24 +1,74 @@
 package service
 
 import (
+	"net/url"
+	"time"
+
 	"com.github.gin-common/app/model"
+	"com.github.gin-common/common/resp"
 )
 
+// 用户列表查询条件
+type UserListQuery struct {
+	// 模糊匹配用户名、姓名或邮箱
+	Search         string
+	ActivateStatus *bool
+	// 为空时只查询未删除的用户
+	DeleteStatus  *bool
+	CreatedAfter  *time.Time
+	CreatedBefore *time.Time
+	// 排序字段，前缀-表示倒序
+	Sort     string
+	PageSize int
+	// offset分页的页码，从1开始
+	Page int
+	// 请求中的filter[...]、page[...]参数，与以上条件合并后按用户的QuerySchema解析
+	Values url.Values
+	// 使用cursor分页
+	CursorMode bool
+	Cursor     string
+}
+
+type UserListResult struct {
+	Users      []model.User
+	Pagination resp.Pagination
+}
+
 type UserService interface {
 	// 创建用户
 	CreateUser(user *model.User, password string) (*model.User, error)
-	// 更新用户
-	UpdateUser(id uint, updateInfo model.User) (*model.User, error)
-	// 删除用户
+	// 更新用户，expectedVersion不为空时与当前版本号不一致返回Conflict
+	UpdateUser(id uint, updateInfo model.User, expectedVersion *uint) (*model.User, error)
+	// 删除用户，并注销该用户的全部登录会话
 	DeleteUser(id uint) error
+	// 恢复已删除的用户，用户名已被占用时返回UserNameDuplicate
+	RestoreUser(id uint) (*model.User, error)
+	// 物理删除删除时间早于before的用户(最多limit个)及其关联数据，返回删除的数量
+	PurgeDeletedUsers(before time.Time, limit int) (int64, error)
 	// 激活用户
 	ActivateUser(id uint) (*model.User, error)
-	// 禁用用户
+	// 禁用用户，并注销该用户的全部登录会话
 	DeactivateUser(id uint) (*model.User, error)
 	// 通过ID获取用户信息
 	GetUserInfoById(id uint) (*model.User, error)
-	// 通过ID修改用户密码
-	ChangePassword(id uint, oldPass string, newPass string) error
+	// 通过ID修改用户密码，并注销该用户的登录会话(keepSessionIDs中的会话除外)
+	ChangePassword(id uint, oldPass string, newPass string, keepSessionIDs ...string) error
+	// 不校验旧密码直接设置新密码(密码过期后强制修改)，并注销该用户的全部登录会话
+	SetPassword(id uint, newPass string) error
+	// 使用当前首选哈希算法重新哈希密码(登录成功后升级旧算法的哈希值)
+	RehashPassword(user *model.User, password string) error
+	// 密码是否已过期
+	PasswordExpired(user *model.User) (bool, error)
 	// 通过用户名获取用户信息
 	GetUserInfoByUserName(username string) (*model.User, error)
+	// 向邮箱发送重置密码邮件，邮箱不存在时同样返回成功(避免枚举用户)
+	RequestPasswordReset(email string) error
+	// 使用重置密码token设置新密码，并注销该用户的全部登录会话
+	ResetPassword(token string, newPass string) error
+	// 发送邮箱验证邮件
+	SendEmailVerification(id uint) error
+	// 使用邮箱验证token完成验证
+	VerifyEmail(token string) error
+	// 查询用户列表
+	ListUsers(query UserListQuery) (*UserListResult, error)
 }
@@ -0,0 +1,95 @@
+package audit
+
+import (
+	"context"
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+	"time"
+
+	"com.github.gin-common/common/request_scope"
+	"gorm.io/gorm"
+)
+
+// 无请求上下文(如定时任务)时的操作人
+const SystemActor = "system"
+
+// 字段变更，脱敏字段的值为MaskedValue
+type Change struct {
+	Field  string      `json:"field"`
+	Before interface{} `json:"before"`
+	After  interface{} `json:"after"`
+}
+
+type Changes []Change
+
+func (changes Changes) Value() (driver.Value, error) {
+	if changes == nil {
+		return nil, nil
+	}
+	b, err := json.Marshal(changes)
+	return string(b), err
+}
+
+func (changes *Changes) Scan(value interface{}) error {
+	var b []byte
+	switch v := value.(type) {
+	case nil:
+		*changes = nil
+		return nil
+	case []byte:
+		b = v
+	case string:
+		b = []byte(v)
+	default:
+		return errors.New("audit: unsupported changes type")
+	}
+	return json.Unmarshal(b, changes)
+}
+
+// 审计日志只追加不修改
+type AuditLog struct {
+	ID        uint      `gorm:"primaryKey;autoIncrement" json:"id"`
+	CreatedAt time.Time `gorm:"index" json:"created_at"`
+	// 匿名操作(如登录失败)或系统操作时为空
+	ActorID   *uint  `gorm:"index" json:"actor_id"`
+	ActorName string `gorm:"size:256;not null;default:''" json:"actor_name"`
+	Action    string `gorm:"size:64;not null;index" json:"action"`
+	// 目标模型及ID，如 user、1
+	TargetType string  `gorm:"size:64;not null;default:'';index:idx_audit_logs_target" json:"target_type"`
+	TargetID   string  `gorm:"size:64;not null;default:'';index:idx_audit_logs_target" json:"target_id"`
+	Changes    Changes `gorm:"type:json" json:"changes"`
+	Detail     string  `gorm:"size:512;not null;default:''" json:"detail"`
+	IP         string  `gorm:"size:64;not null;default:''" json:"ip"`
+	UserAgent  string  `gorm:"size:512;not null;default:''" json:"user_agent"`
+	RequestID  string  `gorm:"size:64;not null;default:'';index" json:"request_id"`
+}
+
+// 按请求上下文补全操作人、IP及请求ID，已指定的操作人不会被覆盖
+func (log *AuditLog) fillFromContext(ctx context.Context) {
+	scope := request_scope.FromContext(ctx)
+	if scope == nil {
+		if log.ActorID == nil && log.ActorName == "" {
+			log.ActorName = SystemActor
+		}
+		return
+	}
+	log.IP, log.UserAgent, log.RequestID = scope.IP, truncate(scope.UserAgent, 512), scope.RequestID
+	if log.ActorID == nil && log.ActorName == "" && scope.Authenticated() {
+		id := scope.UserID
+		log.ActorID, log.ActorName = &id, scope.Username
+	}
+}
+
+func truncate(s string, n int) string {
+	if len(s) > n {
+		return s[:n]
+	}
+	return s
+}
+
+// 记录非数据变更事件(如登录、登出)，使用db所在的事务
+func Record(db *gorm.DB, log *AuditLog) error {
+	log.fillFromContext(db.Statement.Context)
+	return db.Session(&gorm.Session{}).Create(log).Error
+}
@@ -0,0 +1,260 @@
+package audit
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"gorm.io/gorm/schema"
+)
+
+// 实现Auditable的模型通过gorm增删改时自动记录审计日志，操作人等信息取自会话context中的request_scope.Scope
+// 字段标签 audit:"-" 不记录该字段，audit:"mask" 只记录字段被修改而不记录值
+type Auditable interface {
+	// 审计日志中的目标类型，如 user
+	AuditTarget() string
+}
+
+// 会话中指定审计动作的key，未指定时为create/update/delete，如 db.Set(audit.ActionKey, "deactivate")
+const ActionKey = "audit:action"
+
+const (
+	ActionCreate = "create"
+	ActionUpdate = "update"
+	ActionDelete = "delete"
+)
+
+const MaskedValue = "******"
+
+const (
+	changesKey   = "audit:changes"
+	targetIDsKey = "audit:target_ids"
+)
+
+type Plugin struct{}
+
+func (Plugin) Name() string {
+	return "audit"
+}
+
+func (Plugin) Initialize(db *gorm.DB) error {
+	if err := db.Callback().Create().After("gorm:create").Register("audit:after_create", afterCreate); err != nil {
+		return err
+	}
+	if err := db.Callback().Update().Before("gorm:update").Register("audit:before_update", beforeUpdate); err != nil {
+		return err
+	}
+	if err := db.Callback().Update().After("gorm:update").Register("audit:after_update", afterUpdate); err != nil {
+		return err
+	}
+	if err := db.Callback().Delete().Before("gorm:delete").Register("audit:before_delete", beforeDelete); err != nil {
+		return err
+	}
+	return db.Callback().Delete().After("gorm:delete").Register("audit:after_delete", afterDelete)
+}
+
+func auditTarget(db *gorm.DB) (string, bool) {
+	if db.Error != nil || db.Statement.Schema == nil || db.Statement.Model == nil {
+		return "", false
+	}
+	if m, ok := db.Statement.Model.(Auditable); ok {
+		return m.AuditTarget(), true
+	}
+	return "", false
+}
+
+func action(db *gorm.DB, defaultAction string) string {
+	if v, ok := db.Get(ActionKey); ok {
+		if a, ok := v.(string); ok && a != "" {
+			return a
+		}
+	}
+	return defaultAction
+}
+
+func fieldAudit(field *schema.Field) string {
+	return field.Tag.Get("audit")
+}
+
+func primaryKey(stmt *gorm.Statement, value reflect.Value) string {
+	field := stmt.Schema.PrioritizedPrimaryField
+	if field == nil {
+		return ""
+	}
+	if v, isZero := field.ValueOf(value); !isZero {
+		return fmt.Sprint(v)
+	}
+	return ""
+}
+
+// 按JSON序列化结果比较，map中的值与字段类型可能不同
+func sameValue(a interface{}, b interface{}) bool {
+	ja, errA := json.Marshal(a)
+	jb, errB := json.Marshal(b)
+	return errA == nil && errB == nil && string(ja) == string(jb)
+}
+
+func change(field *schema.Field, before interface{}, after interface{}) Change {
+	if fieldAudit(field) == "mask" {
+		return Change{Field: field.DBName, Before: MaskedValue, After: MaskedValue}
+	}
+	return Change{Field: field.DBName, Before: before, After: after}
+}
+
+func writeLogs(db *gorm.DB, logs []*AuditLog) {
+	if len(logs) == 0 {
+		return
+	}
+	for _, log := range logs {
+		log.fillFromContext(db.Statement.Context)
+	}
+	// 与原操作使用同一连接(事务)
+	db.AddError(db.Session(&gorm.Session{}).Create(&logs).Error)
+}
+
+func indirect(value reflect.Value) reflect.Value {
+	for value.Kind() == reflect.Ptr {
+		value = value.Elem()
+	}
+	return value
+}
+
+func afterCreate(db *gorm.DB) {
+	target, ok := auditTarget(db)
+	if !ok {
+		return
+	}
+	stmt := db.Statement
+	var values []reflect.Value
+	switch stmt.ReflectValue.Kind() {
+	case reflect.Slice, reflect.Array:
+		for i := 0; i < stmt.ReflectValue.Len(); i++ {
+			values = append(values, indirect(stmt.ReflectValue.Index(i)))
+		}
+	case reflect.Struct:
+		values = append(values, stmt.ReflectValue)
+	}
+	var logs []*AuditLog
+	for _, value := range values {
+		var changes Changes
+		for _, field := range stmt.Schema.Fields {
+			if field.DBName == "" || fieldAudit(field) == "-" {
+				continue
+			}
+			if v, isZero := field.ValueOf(value); !isZero {
+				changes = append(changes, change(field, nil, v))
+			}
+		}
+		logs = append(logs, &AuditLog{Action: action(db, ActionCreate), TargetType: target, TargetID: primaryKey(stmt, value), Changes: changes})
+	}
+	writeLogs(db, logs)
+}
+
+// 在执行更新前对比模型当前值与更新的值，模型需为已查询的记录
+func beforeUpdate(db *gorm.DB) {
+	if _, ok := auditTarget(db); !ok {
+		return
+	}
+	stmt := db.Statement
+	model := indirect(reflect.ValueOf(stmt.Model))
+	if model.Kind() != reflect.Struct {
+		return
+	}
+	var changes Changes
+	switch updates := stmt.Dest.(type) {
+	case map[string]interface{}:
+		for name, after := range updates {
+			field := stmt.Schema.LookUpField(name)
+			if field == nil || fieldAudit(field) == "-" {
+				continue
+			}
+			before, _ := field.ValueOf(model)
+			if !sameValue(before, after) {
+				changes = append(changes, change(field, before, after))
+			}
+		}
+	default:
+		// 结构体只更新非零值字段，Save(dest与模型相同)时无法获取修改前的值
+		dest := indirect(reflect.ValueOf(stmt.Dest))
+		if dest.Kind() != reflect.Struct || dest.Type() != model.Type() {
+			break
+		}
+		sameRecord := reflect.ValueOf(stmt.Dest).Pointer() == reflect.ValueOf(stmt.Model).Pointer()
+		for _, field := range stmt.Schema.Fields {
+			if field.DBName == "" || field.PrimaryKey || fieldAudit(field) == "-" {
+				continue
+			}
+			after, isZero := field.ValueOf(dest)
+			if isZero {
+				continue
+			}
+			var before interface{}
+			if !sameRecord {
+				if before, _ = field.ValueOf(model); sameValue(before, after) {
+					continue
+				}
+			}
+			changes = append(changes, change(field, before, after))
+		}
+	}
+	// map无序，按字段名排序
+	sort.Slice(changes, func(i, j int) bool { return changes[i].Field < changes[j].Field })
+	db.InstanceSet(changesKey, changes)
+}
+
+func afterUpdate(db *gorm.DB) {
+	target, ok := auditTarget(db)
+	if !ok || db.RowsAffected == 0 {
+		return
+	}
+	v, _ := db.InstanceGet(changesKey)
+	changes, _ := v.(Changes)
+	id := primaryKey(db.Statement, indirect(reflect.ValueOf(db.Statement.Model)))
+	writeLogs(db, []*AuditLog{{Action: action(db, ActionUpdate), TargetType: target, TargetID: id, Changes: changes}})
+}
+
+// 模型未指定主键时(按条件批量删除)先查询将被删除的记录ID
+func beforeDelete(db *gorm.DB) {
+	if _, ok := auditTarget(db); !ok {
+		return
+	}
+	stmt := db.Statement
+	if id := primaryKey(stmt, indirect(reflect.ValueOf(stmt.Model))); id != "" {
+		db.InstanceSet(targetIDsKey, []string{id})
+		return
+	}
+	field := stmt.Schema.PrioritizedPrimaryField
+	where, ok := stmt.Clauses["WHERE"].Expression.(clause.Where)
+	if !ok || field == nil {
+		return
+	}
+	tx := db.Session(&gorm.Session{}).Model(stmt.Model)
+	tx.Statement.Unscoped = stmt.Unscoped
+	ids := reflect.New(reflect.SliceOf(field.FieldType))
+	if err := tx.Clauses(where).Pluck(field.DBName, ids.Interface()).Error; err != nil {
+		db.AddError(err)
+		return
+	}
+	targetIDs := make([]string, 0, ids.Elem().Len())
+	for i := 0; i < ids.Elem().Len(); i++ {
+		targetIDs = append(targetIDs, fmt.Sprint(ids.Elem().Index(i).Interface()))
+	}
+	db.InstanceSet(targetIDsKey, targetIDs)
+}
+
+func afterDelete(db *gorm.DB) {
+	target, ok := auditTarget(db)
+	if !ok || db.RowsAffected == 0 {
+		return
+	}
+	v, _ := db.InstanceGet(targetIDsKey)
+	ids, _ := v.([]string)
+	logs := make([]*AuditLog, 0, len(ids))
+	for _, id := range ids {
+		logs = append(logs, &AuditLog{Action: action(db, ActionDelete), TargetType: target, TargetID: id})
+	}
+	writeLogs(db, logs)
+}
@@ -0,0 +1,86 @@
+package client_ip
+
+import (
+	"net"
+	"net/http"
+	"strings"
+	"sync"
+
+	"com.github.gin-common/util"
+	"github.com/gin-gonic/gin"
+)
+
+// 受信任的反向代理，TRUSTED_PROXIES为逗号分隔的IP或CIDR，如 127.0.0.1,10.0.0.0/8
+// 只有直连地址为受信任代理时才使用X-Forwarded-For/X-Real-IP，否则使用连接的对端地址
+var (
+	trustedProxies []*net.IPNet
+	loadOnce       sync.Once
+)
+
+func loadTrustedProxies() {
+	for _, item := range strings.Split(util.GetDefaultEnv("TRUSTED_PROXIES", ""), ",") {
+		item = strings.TrimSpace(item)
+		if item == "" {
+			continue
+		}
+		if !strings.Contains(item, "/") {
+			if ip := net.ParseIP(item); ip != nil {
+				if ip.To4() != nil {
+					item += "/32"
+				} else {
+					item += "/128"
+				}
+			}
+		}
+		if _, network, err := net.ParseCIDR(item); err == nil {
+			trustedProxies = append(trustedProxies, network)
+		}
+	}
+}
+
+func trusted(ip net.IP) bool {
+	loadOnce.Do(loadTrustedProxies)
+	for _, network := range trustedProxies {
+		if network.Contains(ip) {
+			return true
+		}
+	}
+	return false
+}
+
+func remoteIP(r *http.Request) string {
+	host, _, err := net.SplitHostPort(strings.TrimSpace(r.RemoteAddr))
+	if err != nil {
+		return strings.TrimSpace(r.RemoteAddr)
+	}
+	return host
+}
+
+// 获取请求的客户端IP
+func FromRequest(r *http.Request) string {
+	remote := remoteIP(r)
+	if ip := net.ParseIP(remote); ip == nil || !trusted(ip) {
+		return remote
+	}
+	// 从右向左取第一个非受信任代理的地址，左侧的值可由客户端伪造
+	if forwarded := r.Header.Get("X-Forwarded-For"); forwarded != "" {
+		items := strings.Split(forwarded, ",")
+		for i := len(items) - 1; i >= 0; i-- {
+			ip := net.ParseIP(strings.TrimSpace(items[i]))
+			if ip == nil {
+				break
+			}
+			if !trusted(ip) || i == 0 {
+				return ip.String()
+			}
+		}
+	}
+	if ip := net.ParseIP(strings.TrimSpace(r.Header.Get("X-Real-IP"))); ip != nil {
+		return ip.String()
+	}
+	return remote
+}
+
+func Get(c *gin.Context) string {
+	return FromRequest(c.Request)
+}
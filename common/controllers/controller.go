@@ -11,8 +11,9 @@ import (
 	"github.com/go-playground/validator/v10"
 )
 
-type ControllerFunc func() Controller
-type MiddlewareFunc func() MiddleWare
+// 每个请求创建新的控制器及中间件，依赖的gorm会话使用该请求的context(见request_scope)
+type ControllerFunc func(context *gin.Context) Controller
+type MiddlewareFunc func(context *gin.Context) MiddleWare
 
 type ContextOption interface {
 	Apply(context *gin.Context)
@@ -56,11 +57,14 @@ func getErrRender(err error) resp.RenderFunc {
 
 func ControllerHandler(controllerFunc ControllerFunc) gin.HandlerFunc {
 	return func(context *gin.Context) {
-		controller := controllerFunc()
+		controller := controllerFunc(context)
 		var r resp.RenderFunc
 		data, err := controller.DoRequest(context)
 		if err != nil {
 			r = getErrRender(err)
+		} else if data == nil && context.Writer.Written() {
+			// 控制器已自行输出响应(如JWKS等标准格式)
+			return
 		} else {
 			r = data.RenderJSONFunc()
 		}
@@ -105,7 +109,7 @@ func processMiddlewareFunc(phase int, middleware MiddleWare, context *gin.Contex
 
 func MiddlewareHandler(middlewareFunc MiddlewareFunc) gin.HandlerFunc {
 	return func(context *gin.Context) {
-		mid := middlewareFunc()
+		mid := middlewareFunc(context)
 		if ok := processMiddlewareFunc(phaseBefore, mid, context); !ok {
 			return
 		}
@@ -0,0 +1,31 @@
+package controllers
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"com.github.gin-common/common/exceptions"
+	"github.com/gin-gonic/gin"
+)
+
+// 使用版本号作为ETag
+func SetVersionETag(context *gin.Context, version uint) {
+	context.Header("ETag", fmt.Sprintf(`"%d"`, version))
+}
+
+// 解析If-Match请求头中的版本号，未传入或为*时返回nil
+func IfMatchVersion(context *gin.Context) (*uint, error) {
+	header := strings.TrimSpace(context.GetHeader("If-Match"))
+	if header == "" || header == "*" {
+		return nil, nil
+	}
+	tag := strings.Trim(strings.TrimPrefix(header, "W/"), `"`)
+	version, err := strconv.ParseUint(tag, 10, 32)
+	if err != nil {
+		return nil, exceptions.NewError(exceptions.BadRequest, exceptions.WithError(errors.New("If-Match格式错误")))()
+	}
+	v := uint(version)
+	return &v, nil
+}
@@ -41,6 +41,12 @@ func WithError(err error) ApiErrorModifyFunc {
 	}
 }
 
+func WithData(data gin.H) ApiErrorModifyFunc {
+	return func(e *ApiError) {
+		e.Data = data
+	}
+}
+
 func WithValidateError(err error) ApiErrorModifyFunc {
 	return func(e *ApiError) {
 		if validateErrors, ok := err.(validator.ValidationErrors); ok {
@@ -138,6 +144,22 @@ func BadRequest() *ApiError {
 	}
 }
 
+func NotFound() *ApiError {
+	return &ApiError{
+		Code:          "100404",
+		HttpCode:      http.StatusNotFound,
+		DefaultErrMsg: "记录不存在",
+	}
+}
+
+func Conflict() *ApiError {
+	return &ApiError{
+		Code:          "100409",
+		HttpCode:      http.StatusConflict,
+		DefaultErrMsg: "数据冲突",
+	}
+}
+
 func Timeout() *ApiError {
 	return &ApiError{
 		Code:          "100501",
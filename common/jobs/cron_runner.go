@@ -1,8 +1,12 @@
 package jobs
 
 import (
+	"context"
+	"errors"
 	"os"
 	"sync"
+	"sync/atomic"
+	"syscall"
 	"time"
 
 	"go.uber.org/zap"
@@ -15,6 +19,8 @@ import (
 
 var once sync.Once
 var c *cron.Cron
+var cronLogger *cron_logger.CronLogger
+var stopping int32
 
 func GetCron() *cron.Cron {
 	once.Do(func() {
@@ -26,13 +32,53 @@ func GetCron() *cron.Cron {
 		}
 		l := util.GetDefaultEnv("CRON_LOG_LEVEL", "warn")
 		logLevel := util.GetLogLevel(l)
-		c = cron.New(cron.WithParser(cron.NewParser(
-			cron.SecondOptional|cron.Minute|cron.Hour|cron.Dom|cron.Month|cron.Dow|cron.Descriptor,
-		)), cron.WithLocation(loc), cron.WithLogger(cron_logger.New(cron_logger.Config{
+		cronLogger = cron_logger.New(cron_logger.Config{
 			LogLevel: logLevel,
 			Writer:   os.Stdout,
 			Options:  []zap.Option{zap.AddCaller()},
-		})))
+		})
+		c = cron.New(cron.WithParser(cron.NewParser(
+			cron.SecondOptional|cron.Minute|cron.Hour|cron.Dom|cron.Month|cron.Dow|cron.Descriptor,
+		)), cron.WithLocation(loc), cron.WithLogger(cronLogger))
 	})
 	return c
 }
+
+// 是否正在停止定时任务，停止过程中不再执行新的任务
+func Stopping() bool {
+	return atomic.LoadInt32(&stopping) == 1
+}
+
+// 定时任务生命周期组件，随应用启动，收到退出信号时停止
+type CronComponent struct{}
+
+func (CronComponent) Name() string {
+	return "cron"
+}
+
+func (CronComponent) Start() error {
+	atomic.StoreInt32(&stopping, 0)
+	GetCron().Start()
+	return nil
+}
+
+// 停止调度并等待执行中的任务结束(最长至ctx超时)，随后刷新任务执行记录及日志
+func (CronComponent) Stop(ctx context.Context) error {
+	atomic.StoreInt32(&stopping, 1)
+	var err error
+	select {
+	case <-GetCron().Stop().Done():
+	case <-ctx.Done():
+		err = ctx.Err()
+	}
+	FlushWorkflowRuns()
+	if e := cronLogger.Sync(); e != nil && err == nil && !isSyncNotSupported(e) {
+		err = e
+	}
+	return err
+}
+
+// 标准输出不支持Sync时会返回EINVAL，可忽略
+func isSyncNotSupported(err error) bool {
+	return errors.Is(err, syscall.EINVAL) || errors.Is(err, syscall.ENOTTY)
+}
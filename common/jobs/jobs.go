@@ -93,6 +93,9 @@ func (j *Job) Valid(jobId int) bool {
 
 func (j *Job) funcJob() {
 	var err error
+	if Stopping() {
+		return
+	}
 	if j.hook != nil {
 		err = j.hook.process(j)
 		if err != nil {
@@ -0,0 +1,457 @@
+package jobs
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"com.github.gin-common/util"
+	"github.com/google/uuid"
+)
+
+// 工作流(DAG)：上游任务全部执行成功后才会触发下游任务
+
+const (
+	WorkflowStatusPending     = "pending"
+	WorkflowStatusRunning     = "running"
+	WorkflowStatusSuccess     = "success"
+	WorkflowStatusFailed      = "failed"
+	WorkflowStatusSkipped     = "skipped"
+	WorkflowStatusInterrupted = "interrupted"
+)
+
+var (
+	ErrWorkflowJobExists   = errors.New("workflow: job already exists")
+	ErrWorkflowJobNotFound = errors.New("workflow: job not found")
+	ErrWorkflowCycle       = errors.New("workflow: dependency cycle detected")
+	ErrWorkflowRunning     = errors.New("workflow: previous run is still running")
+	ErrWorkflowExists      = errors.New("workflow: workflow already registered")
+	ErrWorkflowStopping    = errors.New("workflow: cron is stopping")
+)
+
+type WorkflowJob interface {
+	Run() error
+}
+
+type workflowNode struct {
+	name       string
+	job        WorkflowJob
+	upstream   []string
+	downstream []string
+}
+
+type NodeRun struct {
+	Status     string     `json:"status"`
+	StartedAt  *time.Time `json:"started_at"`
+	FinishedAt *time.Time `json:"finished_at"`
+	Error      string     `json:"error"`
+}
+
+type WorkflowRun struct {
+	ID         string              `json:"id"`
+	Workflow   string              `json:"workflow"`
+	Status     string              `json:"status"`
+	StartedAt  time.Time           `json:"started_at"`
+	FinishedAt *time.Time          `json:"finished_at"`
+	Nodes      map[string]*NodeRun `json:"nodes"`
+}
+
+func (r *WorkflowRun) copy() WorkflowRun {
+	c := *r
+	c.Nodes = make(map[string]*NodeRun, len(r.Nodes))
+	for name, node := range r.Nodes {
+		n := *node
+		c.Nodes[name] = &n
+	}
+	return c
+}
+
+type WorkflowGraphNode struct {
+	Name       string   `json:"name"`
+	DependsOn  []string `json:"depends_on"`
+	Downstream []string `json:"downstream"`
+}
+
+type WorkflowGraph struct {
+	Name  string              `json:"name"`
+	Spec  string              `json:"spec"`
+	Nodes []WorkflowGraphNode `json:"nodes"`
+	Edges [][2]string         `json:"edges"`
+}
+
+type Workflow struct {
+	name    string
+	spec    string
+	nodes   map[string]*workflowNode
+	order   []string
+	runs    []*WorkflowRun
+	maxRuns int
+	running bool
+	mu      sync.RWMutex
+	runMu   sync.Mutex
+}
+
+func NewWorkflow(name string, spec string) *Workflow {
+	maxRuns, err := strconv.Atoi(util.GetDefaultEnv("WORKFLOW_RUN_HISTORY", "20"))
+	if err != nil || maxRuns <= 0 {
+		maxRuns = 20
+	}
+	return &Workflow{
+		name:    name,
+		spec:    spec,
+		nodes:   map[string]*workflowNode{},
+		maxRuns: maxRuns,
+	}
+}
+
+func (w *Workflow) Name() string {
+	return w.name
+}
+
+func (w *Workflow) Spec() string {
+	return w.spec
+}
+
+// 添加任务，dependsOn中的任务需已存在
+func (w *Workflow) AddJob(name string, job WorkflowJob, dependsOn ...string) error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if _, ok := w.nodes[name]; ok {
+		return fmt.Errorf("%w: %s", ErrWorkflowJobExists, name)
+	}
+	for _, up := range dependsOn {
+		if _, ok := w.nodes[up]; !ok {
+			return fmt.Errorf("%w: %s", ErrWorkflowJobNotFound, up)
+		}
+	}
+	w.nodes[name] = &workflowNode{name: name, job: job}
+	w.order = append(w.order, name)
+	for _, up := range dependsOn {
+		w.addEdge(up, name)
+	}
+	return nil
+}
+
+// 为已存在的两个任务添加依赖关系，若形成环则返回ErrWorkflowCycle
+func (w *Workflow) AddDependency(name string, dependsOn string) error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if _, ok := w.nodes[name]; !ok {
+		return fmt.Errorf("%w: %s", ErrWorkflowJobNotFound, name)
+	}
+	if _, ok := w.nodes[dependsOn]; !ok {
+		return fmt.Errorf("%w: %s", ErrWorkflowJobNotFound, dependsOn)
+	}
+	if name == dependsOn || w.reachable(name, dependsOn) {
+		return fmt.Errorf("%w: %s -> %s", ErrWorkflowCycle, dependsOn, name)
+	}
+	for _, up := range w.nodes[name].upstream {
+		if up == dependsOn {
+			return nil
+		}
+	}
+	w.addEdge(dependsOn, name)
+	return nil
+}
+
+func (w *Workflow) addEdge(from string, to string) {
+	w.nodes[from].downstream = append(w.nodes[from].downstream, to)
+	w.nodes[to].upstream = append(w.nodes[to].upstream, from)
+}
+
+// 判断from沿下游方向能否到达to
+func (w *Workflow) reachable(from string, to string) bool {
+	visited := map[string]bool{}
+	stack := []string{from}
+	for len(stack) > 0 {
+		current := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+		if current == to {
+			return true
+		}
+		if visited[current] {
+			continue
+		}
+		visited[current] = true
+		stack = append(stack, w.nodes[current].downstream...)
+	}
+	return false
+}
+
+func (w *Workflow) Run() error {
+	if Stopping() {
+		return ErrWorkflowStopping
+	}
+	w.runMu.Lock()
+	if w.running {
+		w.runMu.Unlock()
+		return ErrWorkflowRunning
+	}
+	w.running = true
+	w.runMu.Unlock()
+	defer func() {
+		w.runMu.Lock()
+		w.running = false
+		w.runMu.Unlock()
+	}()
+
+	w.mu.RLock()
+	run := &WorkflowRun{
+		ID:        uuid.New().String(),
+		Workflow:  w.name,
+		Status:    WorkflowStatusRunning,
+		StartedAt: time.Now(),
+		Nodes:     make(map[string]*NodeRun, len(w.nodes)),
+	}
+	pending := make(map[string]int, len(w.nodes))
+	for _, name := range w.order {
+		run.Nodes[name] = &NodeRun{Status: WorkflowStatusPending}
+		pending[name] = len(w.nodes[name].upstream)
+	}
+	w.mu.RUnlock()
+	w.appendRun(run)
+
+	// 每个任务完成后立即启动依赖已全部完成的下游任务，不等待同批其他任务
+	var failed []string
+	done := make(chan string)
+	running := 0
+	start := func(name string) {
+		running++
+		go func() {
+			w.runNode(run, name)
+			done <- name
+		}()
+	}
+	for _, name := range w.roots() {
+		start(name)
+	}
+	for running > 0 {
+		name := <-done
+		running--
+		var next []string
+		w.mu.Lock()
+		nodeRun := run.Nodes[name]
+		if nodeRun.Status == WorkflowStatusFailed {
+			failed = append(failed, name)
+		}
+		for _, down := range w.nodes[name].downstream {
+			if nodeRun.Status != WorkflowStatusSuccess {
+				w.skip(run, down)
+			}
+			pending[down]--
+			if pending[down] == 0 && run.Nodes[down].Status == WorkflowStatusPending {
+				next = append(next, down)
+			}
+		}
+		w.mu.Unlock()
+		for _, down := range next {
+			start(down)
+		}
+	}
+
+	w.mu.Lock()
+	finishedAt := time.Now()
+	run.FinishedAt = &finishedAt
+	if len(failed) > 0 {
+		run.Status = WorkflowStatusFailed
+	} else {
+		run.Status = WorkflowStatusSuccess
+	}
+	w.mu.Unlock()
+	if len(failed) > 0 {
+		return fmt.Errorf("workflow %s run %s failed: %s", w.name, run.ID, strings.Join(failed, ","))
+	}
+	return nil
+}
+
+func (w *Workflow) roots() []string {
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+	var roots []string
+	for _, name := range w.order {
+		if len(w.nodes[name].upstream) == 0 {
+			roots = append(roots, name)
+		}
+	}
+	return roots
+}
+
+// 上游失败时，跳过所有下游任务
+func (w *Workflow) skip(run *WorkflowRun, name string) {
+	if run.Nodes[name].Status != WorkflowStatusPending {
+		return
+	}
+	run.Nodes[name].Status = WorkflowStatusSkipped
+	for _, down := range w.nodes[name].downstream {
+		w.skip(run, down)
+	}
+}
+
+func (w *Workflow) runNode(run *WorkflowRun, name string) {
+	w.mu.Lock()
+	node := w.nodes[name]
+	nodeRun := run.Nodes[name]
+	startedAt := time.Now()
+	nodeRun.Status = WorkflowStatusRunning
+	nodeRun.StartedAt = &startedAt
+	w.mu.Unlock()
+
+	err := runWorkflowJob(node.job)
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	finishedAt := time.Now()
+	nodeRun.FinishedAt = &finishedAt
+	if err != nil {
+		nodeRun.Status = WorkflowStatusFailed
+		nodeRun.Error = err.Error()
+	} else {
+		nodeRun.Status = WorkflowStatusSuccess
+	}
+}
+
+func runWorkflowJob(job WorkflowJob) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("panic: %v", r)
+		}
+	}()
+	return job.Run()
+}
+
+func (w *Workflow) appendRun(run *WorkflowRun) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.runs = append(w.runs, run)
+	if len(w.runs) > w.maxRuns {
+		w.runs = w.runs[len(w.runs)-w.maxRuns:]
+	}
+}
+
+// 获取运行记录(按开始时间倒序)
+func (w *Workflow) Runs() []WorkflowRun {
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+	runs := make([]WorkflowRun, 0, len(w.runs))
+	for i := len(w.runs) - 1; i >= 0; i-- {
+		runs = append(runs, w.runs[i].copy())
+	}
+	return runs
+}
+
+func (w *Workflow) GetRun(id string) (WorkflowRun, bool) {
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+	for _, run := range w.runs {
+		if run.ID == id {
+			return run.copy(), true
+		}
+	}
+	return WorkflowRun{}, false
+}
+
+func (w *Workflow) Graph() WorkflowGraph {
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+	graph := WorkflowGraph{
+		Name:  w.name,
+		Spec:  w.spec,
+		Nodes: make([]WorkflowGraphNode, 0, len(w.order)),
+		Edges: [][2]string{},
+	}
+	for _, name := range w.order {
+		node := w.nodes[name]
+		graph.Nodes = append(graph.Nodes, WorkflowGraphNode{
+			Name:       name,
+			DependsOn:  append([]string{}, node.upstream...),
+			Downstream: append([]string{}, node.downstream...),
+		})
+		for _, down := range node.downstream {
+			graph.Edges = append(graph.Edges, [2]string{name, down})
+		}
+	}
+	return graph
+}
+
+// 导出Graphviz DOT格式
+func (w *Workflow) DOT() string {
+	graph := w.Graph()
+	var b strings.Builder
+	b.WriteString(fmt.Sprintf("digraph %s {\n", strconv.Quote(graph.Name)))
+	for _, node := range graph.Nodes {
+		b.WriteString(fmt.Sprintf("  %s;\n", strconv.Quote(node.Name)))
+	}
+	for _, edge := range graph.Edges {
+		b.WriteString(fmt.Sprintf("  %s -> %s;\n", strconv.Quote(edge[0]), strconv.Quote(edge[1])))
+	}
+	b.WriteString("}\n")
+	return b.String()
+}
+
+var workflows = map[string]*Workflow{}
+var workflowsMu sync.RWMutex
+
+// 注册工作流，并按工作流的Spec加入定时任务
+func RegisterWorkflow(w *Workflow) (*Job, error) {
+	workflowsMu.Lock()
+	defer workflowsMu.Unlock()
+	if _, ok := workflows[w.name]; ok {
+		return nil, fmt.Errorf("%w: %s", ErrWorkflowExists, w.name)
+	}
+	job := &Job{}
+	job.Init(w.name, GetCron(), w)
+	workflows[w.name] = w
+	return job, nil
+}
+
+func GetWorkflow(name string) (*Workflow, bool) {
+	workflowsMu.RLock()
+	defer workflowsMu.RUnlock()
+	w, ok := workflows[name]
+	return w, ok
+}
+
+func Workflows() []*Workflow {
+	workflowsMu.RLock()
+	defer workflowsMu.RUnlock()
+	list := make([]*Workflow, 0, len(workflows))
+	for _, w := range workflows {
+		list = append(list, w)
+	}
+	sort.Slice(list, func(i, j int) bool {
+		return list[i].name < list[j].name
+	})
+	return list
+}
+
+// 停止时调用：将未完成的运行记录标记为中断，并将最近一次运行结果写入日志
+func FlushWorkflowRuns() {
+	for _, w := range Workflows() {
+		w.mu.Lock()
+		finishedAt := time.Now()
+		for _, run := range w.runs {
+			if run.Status != WorkflowStatusRunning {
+				continue
+			}
+			run.Status = WorkflowStatusInterrupted
+			run.FinishedAt = &finishedAt
+			for _, node := range run.Nodes {
+				if node.Status == WorkflowStatusRunning || node.Status == WorkflowStatusPending {
+					node.Status = WorkflowStatusInterrupted
+				}
+			}
+		}
+		var last *WorkflowRun
+		if len(w.runs) > 0 {
+			last = w.runs[len(w.runs)-1]
+		}
+		w.mu.Unlock()
+		if last != nil && cronLogger != nil {
+			cronLogger.Info("workflow last run", "workflow", last.Workflow, "run", last.ID, "status", last.Status)
+		}
+	}
+}
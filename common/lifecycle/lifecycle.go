@@ -0,0 +1,167 @@
+package lifecycle
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"os"
+	"os/signal"
+	"sync"
+	"syscall"
+	"time"
+)
+
+// 应用生命周期管理：按注册顺序启动组件，收到退出信号后按相反顺序停止组件
+
+type Component interface {
+	Name() string
+	// 启动组件，不应阻塞
+	Start() error
+	// 停止组件，需在ctx超时前返回
+	Stop(ctx context.Context) error
+}
+
+type Lifecycle struct {
+	components []Component
+	started    []Component
+	mu         sync.Mutex
+	errCh      chan error
+}
+
+func New() *Lifecycle {
+	return &Lifecycle{errCh: make(chan error, 1)}
+}
+
+func (l *Lifecycle) Append(components ...Component) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.components = append(l.components, components...)
+}
+
+func (l *Lifecycle) Start() error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	for _, component := range l.components {
+		if err := component.Start(); err != nil {
+			return &ComponentError{Component: component.Name(), Err: err}
+		}
+		l.started = append(l.started, component)
+	}
+	return nil
+}
+
+// 按启动顺序的相反顺序停止组件，所有组件共享同一个截止时间
+func (l *Lifecycle) Stop(ctx context.Context) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	var errs []error
+	for i := len(l.started) - 1; i >= 0; i-- {
+		component := l.started[i]
+		if err := component.Stop(ctx); err != nil {
+			errs = append(errs, &ComponentError{Component: component.Name(), Err: err})
+		}
+	}
+	l.started = nil
+	if len(errs) > 0 {
+		return errs[0]
+	}
+	return nil
+}
+
+// 组件运行过程中出现的致命错误，会触发应用退出
+func (l *Lifecycle) Fail(err error) {
+	select {
+	case l.errCh <- err:
+	default:
+	}
+}
+
+// 启动所有组件并阻塞至收到SIGINT/SIGTERM或组件异常，随后在timeout内停止所有组件
+func (l *Lifecycle) Run(timeout time.Duration) error {
+	if err := l.Start(); err != nil {
+		ctx, cancel := context.WithTimeout(context.Background(), timeout)
+		defer cancel()
+		_ = l.Stop(ctx)
+		return err
+	}
+	quit := make(chan os.Signal, 1)
+	signal.Notify(quit, syscall.SIGINT, syscall.SIGTERM)
+	defer signal.Stop(quit)
+
+	var runErr error
+	select {
+	case <-quit:
+	case runErr = <-l.errCh:
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+	if err := l.Stop(ctx); err != nil && runErr == nil {
+		runErr = err
+	}
+	return runErr
+}
+
+type ComponentError struct {
+	Component string
+	Err       error
+}
+
+func (e *ComponentError) Error() string {
+	return e.Component + ": " + e.Err.Error()
+}
+
+func (e *ComponentError) Unwrap() error {
+	return e.Err
+}
+
+type HTTPServer struct {
+	server    *http.Server
+	lifecycle *Lifecycle
+}
+
+func NewHTTPServer(server *http.Server, l *Lifecycle) *HTTPServer {
+	return &HTTPServer{server: server, lifecycle: l}
+}
+
+func (s *HTTPServer) Name() string {
+	return "http"
+}
+
+func (s *HTTPServer) Start() error {
+	go func() {
+		if err := s.server.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			s.lifecycle.Fail(&ComponentError{Component: s.Name(), Err: err})
+		}
+	}()
+	return nil
+}
+
+// 停止接收新请求，并等待处理中的请求完成
+func (s *HTTPServer) Stop(ctx context.Context) error {
+	return s.server.Shutdown(ctx)
+}
+
+type FuncComponent struct {
+	ComponentName string
+	StartFunc     func() error
+	StopFunc      func(ctx context.Context) error
+}
+
+func (c FuncComponent) Name() string {
+	return c.ComponentName
+}
+
+func (c FuncComponent) Start() error {
+	if c.StartFunc == nil {
+		return nil
+	}
+	return c.StartFunc()
+}
+
+func (c FuncComponent) Stop(ctx context.Context) error {
+	if c.StopFunc == nil {
+		return nil
+	}
+	return c.StopFunc(ctx)
+}
@@ -37,3 +37,7 @@ func (l *CronLogger) Info(msg string, keysAndValues ...interface{}) {
 func (l *CronLogger) Error(err error, msg string, keysAndValues ...interface{}) {
 	l.logger.Errorf(fmt.Sprintf("%s:%s", msg, err.Error()), keysAndValues)
 }
+
+func (l *CronLogger) Sync() error {
+	return l.logger.Sync()
+}
@@ -7,6 +7,7 @@ import (
 
 	"go.uber.org/zap/zapcore"
 
+	"com.github.gin-common/common/client_ip"
 	"com.github.gin-common/util"
 
 	"github.com/gin-gonic/gin"
@@ -84,7 +85,7 @@ func LoggerWithConfig(conf LoggerConfig) gin.HandlerFunc {
 			param.TimeStamp = time.Now()
 			param.Latency = param.TimeStamp.Sub(start)
 
-			param.ClientIP = c.ClientIP()
+			param.ClientIP = client_ip.Get(c)
 			param.Method = c.Request.Method
 			param.StatusCode = c.Writer.Status()
 			param.ErrorMessage = c.Errors.ByType(gin.ErrorTypePrivate).String()
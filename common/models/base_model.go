@@ -9,20 +9,19 @@ import (
 type BaseModel struct {
 	ID        uint      `gorm:"primaryKey;autoIncrement" json:"id"`
 	CreatedAt time.Time `json:"created_at"`
-	UpdatedAt time.Time `json:"updated_at"`
+	UpdatedAt time.Time `json:"updated_at" audit:"-"`
 }
 
+// 软删除，DeleteStatus与DeletedAt通过Repository同时修改
 type SoftDeleteModel struct {
 	DeleteStatus bool           `gorm:"not null;default:0" json:"delete_status"`
 	DeletedAt    gorm.DeletedAt `json:"deleted_at"`
+	// 未删除时为0，删除后为记录ID，与唯一字段组成联合唯一索引，使已删除记录不占用唯一值
+	DeleteMark uint `gorm:"not null;default:0" json:"-" audit:"-"`
 }
 
-func Delete(session *gorm.DB, id uint, m interface{}) (tx *gorm.DB) {
-	return session.Model(m).Where("id=?", id).Updates(map[string]interface{}{"delete_status": true, "deleted_at": time.Now()})
-}
-
-func Recover(session *gorm.DB, id uint, m interface{}) (tx *gorm.DB) {
-	return session.Model(m).Where("id=?", id).Updates(map[string]interface{}{"delete_status": false, "deleted_at": nil})
+func (m *SoftDeleteModel) IsDeleted() bool {
+	return m.DeleteStatus
 }
 
 type ActivateModel struct {
@@ -30,10 +29,21 @@ type ActivateModel struct {
 	ActivateAt     time.Time `json:"activate_at"`
 }
 
-func Activate(session *gorm.DB, id uint, m interface{}) (tx *gorm.DB) {
-	return session.Model(m).Where("id=?", id).Updates(map[string]interface{}{"activate_status": true, "activate_at": time.Now()})
+func (m *ActivateModel) IsActivated() bool {
+	return m.ActivateStatus
+}
+
+// 乐观锁版本号，通过Repository更新时校验并递增，版本不一致时返回Conflict
+type VersionModel struct {
+	Version uint `gorm:"not null;default:0" json:"version" audit:"-"`
+}
+
+func (m *VersionModel) GetVersion() uint {
+	return m.Version
 }
 
-func Deactivate(session *gorm.DB, id uint, m interface{}) (tx *gorm.DB) {
-	return session.Model(m).Where("id=?", id).Updates(map[string]interface{}{"activate_status": false, "activate_at": time.Now()})
+// 创建人及最后修改人，由OperatorPlugin从会话context中的当前用户(request_scope.Scope)自动填充，未认证或系统操作时不修改
+type OperatorModel struct {
+	CreatedBy *uint `gorm:"index" json:"created_by" audit:"-"`
+	UpdatedBy *uint `json:"updated_by" audit:"-"`
 }
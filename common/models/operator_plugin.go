@@ -0,0 +1,91 @@
+package models
+
+import (
+	"reflect"
+
+	"com.github.gin-common/common/request_scope"
+	"gorm.io/gorm"
+	"gorm.io/gorm/schema"
+)
+
+// 嵌入OperatorModel的模型在创建时填充created_by、updated_by，更新时填充updated_by(UpdateColumn除外)
+type OperatorPlugin struct{}
+
+func (OperatorPlugin) Name() string {
+	return "operator"
+}
+
+func (OperatorPlugin) Initialize(db *gorm.DB) error {
+	if err := db.Callback().Create().Before("gorm:create").Register("operator:before_create", setCreatedBy); err != nil {
+		return err
+	}
+	return db.Callback().Update().Before("gorm:update").Register("operator:before_update", setUpdatedBy)
+}
+
+func currentOperator(db *gorm.DB) (uint, bool) {
+	if db.Error != nil || db.Statement.Schema == nil {
+		return 0, false
+	}
+	scope := request_scope.FromContext(db.Statement.Context)
+	if !scope.Authenticated() {
+		return 0, false
+	}
+	return scope.UserID, true
+}
+
+// 只填充未指定的值
+func setOperatorIfZero(db *gorm.DB, field *schema.Field, value reflect.Value, id uint) {
+	if _, isZero := field.ValueOf(value); isZero {
+		db.AddError(field.Set(value, &id))
+	}
+}
+
+func setCreatedBy(db *gorm.DB) {
+	id, ok := currentOperator(db)
+	if !ok {
+		return
+	}
+	stmt := db.Statement
+	for _, name := range []string{"created_by", "updated_by"} {
+		field := stmt.Schema.LookUpField(name)
+		if field == nil {
+			continue
+		}
+		switch stmt.ReflectValue.Kind() {
+		case reflect.Slice, reflect.Array:
+			for i := 0; i < stmt.ReflectValue.Len(); i++ {
+				setOperatorIfZero(db, field, reflect.Indirect(stmt.ReflectValue.Index(i)), id)
+			}
+		case reflect.Struct:
+			setOperatorIfZero(db, field, stmt.ReflectValue, id)
+		}
+	}
+}
+
+func setUpdatedBy(db *gorm.DB) {
+	id, ok := currentOperator(db)
+	if !ok || db.Statement.UpdatingColumn {
+		return
+	}
+	stmt := db.Statement
+	field := stmt.Schema.LookUpField("updated_by")
+	if field == nil {
+		return
+	}
+	if _, ok := stmt.Dest.(map[string]interface{}); ok {
+		stmt.SetColumn(field.DBName, &id)
+		return
+	}
+	// 结构体只更新非零值字段，需要写入更新的值中
+	dest := reflect.Indirect(reflect.ValueOf(stmt.Dest))
+	if dest.Kind() != reflect.Struct || dest.Type() != stmt.Schema.ModelType {
+		return
+	}
+	if !dest.CanAddr() {
+		// 按值传入的结构体使用副本
+		copied := reflect.New(dest.Type())
+		copied.Elem().Set(dest)
+		stmt.Dest, dest = copied.Interface(), copied.Elem()
+	}
+	db.AddError(field.Set(dest, &id))
+}
@@ -0,0 +1,317 @@
+package models
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"com.github.gin-common/common/exceptions"
+	"com.github.gin-common/common/resp"
+	"gorm.io/gorm"
+)
+
+// 列表查询参数，例如:
+// ?filter[name][like]=jo&filter[activate_status]=true&sort=-created_at,id&page[number]=2&page[size]=20
+// filter未指定操作符时为eq，sort前缀-表示倒序，只允许使用QuerySchema中声明的字段
+
+type FieldType int
+
+const (
+	FieldString FieldType = iota
+	FieldInt
+	FieldBool
+	// RFC3339格式的时间
+	FieldTime
+)
+
+// 过滤操作符
+const (
+	OpEq   = "eq"
+	OpNe   = "ne"
+	OpGt   = "gt"
+	OpGte  = "gte"
+	OpLt   = "lt"
+	OpLte  = "lte"
+	OpLike = "like"
+	// 逗号分隔的多个值
+	OpIn = "in"
+	// true为IS NULL，false为IS NOT NULL
+	OpNull = "null"
+)
+
+var defaultOperators = map[FieldType][]string{
+	FieldString: {OpEq, OpNe, OpLike, OpIn, OpNull},
+	FieldInt:    {OpEq, OpNe, OpGt, OpGte, OpLt, OpLte, OpIn, OpNull},
+	FieldBool:   {OpEq, OpNull},
+	FieldTime:   {OpEq, OpNe, OpGt, OpGte, OpLt, OpLte, OpNull},
+}
+
+var operatorSQL = map[string]string{
+	OpEq:  "=",
+	OpNe:  "<>",
+	OpGt:  ">",
+	OpGte: ">=",
+	OpLt:  "<",
+	OpLte: "<=",
+}
+
+// 允许查询的字段
+type QueryField struct {
+	// 数据库列名，为空时与参数中的字段名相同
+	Column string
+	Type   FieldType
+	// 允许的操作符，为空时使用该类型的默认操作符
+	Operators []string
+	// 允许过滤，为false时只能用于排序
+	Filterable bool
+	Sortable   bool
+}
+
+func (field QueryField) allows(op string) bool {
+	operators := field.Operators
+	if len(operators) == 0 {
+		operators = defaultOperators[field.Type]
+	}
+	for _, o := range operators {
+		if o == op {
+			return true
+		}
+	}
+	return false
+}
+
+// 每个模型声明的可查询字段
+type QuerySchema struct {
+	Fields map[string]QueryField
+	// 未指定sort时的排序，如 -id
+	DefaultSort     string
+	DefaultPageSize int
+	MaxPageSize     int
+}
+
+type Filter struct {
+	Field    string
+	Column   string
+	Operator string
+	Value    interface{}
+}
+
+type Sort struct {
+	Column string
+	Desc   bool
+}
+
+type Query struct {
+	Filters  []Filter
+	Sorts    []Sort
+	Page     int
+	PageSize int
+}
+
+var filterKeyPattern = regexp.MustCompile(`^filter\[(\w+)\](?:\[(\w+)\])?$`)
+
+func queryError(format string, args ...interface{}) error {
+	return exceptions.NewError(exceptions.BadRequest, exceptions.WithError(fmt.Errorf(format, args...)))()
+}
+
+// 转义LIKE通配符
+func EscapeLike(s string) string {
+	return strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`).Replace(s)
+}
+
+func parseValue(fieldType FieldType, raw string) (interface{}, error) {
+	switch fieldType {
+	case FieldInt:
+		return strconv.ParseInt(raw, 10, 64)
+	case FieldBool:
+		return strconv.ParseBool(raw)
+	case FieldTime:
+		return time.Parse(time.RFC3339, raw)
+	}
+	return raw, nil
+}
+
+func (schema *QuerySchema) parseFilter(name string, op string, raw string) (*Filter, error) {
+	field, ok := schema.Fields[name]
+	if !ok || !field.Filterable {
+		return nil, queryError("不支持的过滤字段: %s", name)
+	}
+	if op == "" {
+		op = OpEq
+	}
+	if !field.allows(op) {
+		return nil, queryError("字段%s不支持操作符%s", name, op)
+	}
+	column := field.Column
+	if column == "" {
+		column = name
+	}
+	filter := &Filter{Field: name, Column: column, Operator: op}
+	var err error
+	switch op {
+	case OpNull:
+		filter.Value, err = strconv.ParseBool(raw)
+	case OpIn:
+		var values []interface{}
+		for _, item := range strings.Split(raw, ",") {
+			value, e := parseValue(field.Type, item)
+			if e != nil {
+				err = e
+				break
+			}
+			values = append(values, value)
+		}
+		filter.Value = values
+	default:
+		filter.Value, err = parseValue(field.Type, raw)
+	}
+	if err != nil {
+		return nil, queryError("字段%s的值无效: %s", name, raw)
+	}
+	return filter, nil
+}
+
+func (schema *QuerySchema) parseSort(raw string) ([]Sort, error) {
+	var sorts []Sort
+	for _, item := range strings.Split(raw, ",") {
+		item = strings.TrimSpace(item)
+		if item == "" {
+			continue
+		}
+		name := strings.TrimPrefix(item, "-")
+		field, ok := schema.Fields[name]
+		if !ok || !field.Sortable {
+			return nil, queryError("不支持的排序字段: %s", name)
+		}
+		column := field.Column
+		if column == "" {
+			column = name
+		}
+		sorts = append(sorts, Sort{Column: column, Desc: strings.HasPrefix(item, "-")})
+	}
+	return sorts, nil
+}
+
+func (schema *QuerySchema) parsePage(values url.Values) (page int, pageSize int, err error) {
+	pageSize, maxPageSize := schema.DefaultPageSize, schema.MaxPageSize
+	if pageSize <= 0 {
+		pageSize = 20
+	}
+	if maxPageSize <= 0 {
+		maxPageSize = 100
+	}
+	page = 1
+	if raw := values.Get("page[number]"); raw != "" {
+		if page, err = strconv.Atoi(raw); err != nil || page < 1 {
+			return 0, 0, queryError("page[number]必须为正整数")
+		}
+	}
+	if raw := values.Get("page[size]"); raw != "" {
+		if pageSize, err = strconv.Atoi(raw); err != nil || pageSize < 1 || pageSize > maxPageSize {
+			return 0, 0, queryError("page[size]取值范围为1-%d", maxPageSize)
+		}
+	}
+	return page, pageSize, nil
+}
+
+// 按声明的字段解析查询参数，未声明的字段、操作符及无效的值返回BadRequest
+func (schema *QuerySchema) Parse(values url.Values) (*Query, error) {
+	query := &Query{}
+	// 按参数名排序，保证生成的SQL稳定
+	keys := make([]string, 0, len(values))
+	for key := range values {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		items := values[key]
+		match := filterKeyPattern.FindStringSubmatch(key)
+		if match == nil {
+			if strings.HasPrefix(key, "filter[") {
+				return nil, queryError("无效的过滤参数: %s", key)
+			}
+			continue
+		}
+		for _, raw := range items {
+			filter, err := schema.parseFilter(match[1], match[2], raw)
+			if err != nil {
+				return nil, err
+			}
+			query.Filters = append(query.Filters, *filter)
+		}
+	}
+	sortParam := values.Get("sort")
+	if sortParam == "" {
+		sortParam = schema.DefaultSort
+	}
+	var err error
+	if query.Sorts, err = schema.parseSort(sortParam); err != nil {
+		return nil, err
+	}
+	if query.Page, query.PageSize, err = schema.parsePage(values); err != nil {
+		return nil, err
+	}
+	return query, nil
+}
+
+// 过滤条件
+func (query *Query) FilterScope() func(db *gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		for _, filter := range query.Filters {
+			switch filter.Operator {
+			case OpLike:
+				db = db.Where(fmt.Sprintf("%s LIKE ?", filter.Column), "%"+EscapeLike(fmt.Sprint(filter.Value))+"%")
+			case OpIn:
+				db = db.Where(fmt.Sprintf("%s IN ?", filter.Column), filter.Value)
+			case OpNull:
+				if filter.Value.(bool) {
+					db = db.Where(fmt.Sprintf("%s IS NULL", filter.Column))
+				} else {
+					db = db.Where(fmt.Sprintf("%s IS NOT NULL", filter.Column))
+				}
+			default:
+				db = db.Where(fmt.Sprintf("%s %s ?", filter.Column, operatorSQL[filter.Operator]), filter.Value)
+			}
+		}
+		return db
+	}
+}
+
+// 排序及分页
+func (query *Query) PageScope() func(db *gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		for _, s := range query.Sorts {
+			if s.Desc {
+				db = db.Order(fmt.Sprintf("%s DESC", s.Column))
+			} else {
+				db = db.Order(s.Column)
+			}
+		}
+		return db.Offset((query.Page - 1) * query.PageSize).Limit(query.PageSize)
+	}
+}
+
+func (query *Query) Pagination(total int64) resp.Pagination {
+	return resp.OffsetPagination(query.Page, query.PageSize, total, int64(query.Page*query.PageSize) < total)
+}
+
+// 查询总数及当前页数据，db需已指定Model
+func (query *Query) Find(db *gorm.DB, dest interface{}) (resp.Pagination, error) {
+	if db.Statement.Model == nil {
+		return resp.Pagination{}, errors.New("query: model not specified")
+	}
+	base := db.Session(&gorm.Session{WithConditions: true}).Scopes(query.FilterScope())
+	var total int64
+	if err := base.Session(&gorm.Session{WithConditions: true}).Count(&total).Error; err != nil {
+		return resp.Pagination{}, err
+	}
+	if err := base.Session(&gorm.Session{WithConditions: true}).Scopes(query.PageScope()).Find(dest).Error; err != nil {
+		return resp.Pagination{}, err
+	}
+	return query.Pagination(total), nil
+}
@@ -0,0 +1,153 @@
+package models_test
+
+import (
+	"net/http"
+	"net/url"
+	"reflect"
+	"testing"
+	"time"
+
+	"com.github.gin-common/common/exceptions"
+	"com.github.gin-common/common/models"
+)
+
+var testSchema = models.QuerySchema{
+	Fields: map[string]models.QueryField{
+		"id":         {Type: models.FieldInt, Filterable: true, Sortable: true},
+		"name":       {Type: models.FieldString, Filterable: true, Sortable: true},
+		"status":     {Type: models.FieldString, Filterable: true, Operators: []string{models.OpEq, models.OpIn}},
+		"active":     {Type: models.FieldBool, Filterable: true},
+		"created_at": {Type: models.FieldTime, Filterable: true, Sortable: true},
+		// 只能排序
+		"score": {Type: models.FieldInt, Sortable: true},
+	},
+	DefaultSort:     "-id",
+	DefaultPageSize: 10,
+	MaxPageSize:     50,
+}
+
+func parse(t *testing.T, raw string) (*models.Query, error) {
+	t.Helper()
+	values, err := url.ParseQuery(raw)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return testSchema.Parse(values)
+}
+
+func assertBadRequest(t *testing.T, raw string) {
+	t.Helper()
+	_, err := parse(t, raw)
+	apiErr, ok := err.(*exceptions.ApiError)
+	if !ok {
+		t.Fatalf("%s: expected ApiError, got %v", raw, err)
+	}
+	if apiErr.HttpCode != http.StatusBadRequest {
+		t.Fatalf("%s: expected 400, got %d", raw, apiErr.HttpCode)
+	}
+}
+
+func TestParseDefaults(t *testing.T) {
+	query, err := parse(t, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(query.Filters) != 0 {
+		t.Fatalf("unexpected filters: %v", query.Filters)
+	}
+	if !reflect.DeepEqual(query.Sorts, []models.Sort{{Column: "id", Desc: true}}) {
+		t.Fatalf("unexpected sorts: %v", query.Sorts)
+	}
+	if query.Page != 1 || query.PageSize != 10 {
+		t.Fatalf("unexpected page: %d/%d", query.Page, query.PageSize)
+	}
+}
+
+func TestParseFilters(t *testing.T) {
+	query, err := parse(t, "filter[name][like]=jo&filter[id][gte]=3&filter[status][in]=a,b&filter[active]=true&filter[created_at][lt]=2024-01-02T03:04:05Z")
+	if err != nil {
+		t.Fatal(err)
+	}
+	createdAt, _ := time.Parse(time.RFC3339, "2024-01-02T03:04:05Z")
+	// 按参数名排序
+	expected := []models.Filter{
+		{Field: "active", Column: "active", Operator: models.OpEq, Value: true},
+		{Field: "created_at", Column: "created_at", Operator: models.OpLt, Value: createdAt},
+		{Field: "id", Column: "id", Operator: models.OpGte, Value: int64(3)},
+		{Field: "name", Column: "name", Operator: models.OpLike, Value: "jo"},
+		{Field: "status", Column: "status", Operator: models.OpIn, Value: []interface{}{"a", "b"}},
+	}
+	if !reflect.DeepEqual(query.Filters, expected) {
+		t.Fatalf("unexpected filters:\n%v\n%v", query.Filters, expected)
+	}
+}
+
+func TestParseSort(t *testing.T) {
+	query, err := parse(t, "sort=-created_at,score")
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := []models.Sort{{Column: "created_at", Desc: true}, {Column: "score"}}
+	if !reflect.DeepEqual(query.Sorts, expected) {
+		t.Fatalf("unexpected sorts: %v", query.Sorts)
+	}
+}
+
+func TestParseUnknownFields(t *testing.T) {
+	for _, raw := range []string{
+		"filter[password]=x",
+		"filter[name%20or%201=1]=x",
+		"filter[]=x",
+		"filter[name][like][x]=jo",
+		// 只能排序的字段不能过滤
+		"filter[score]=1",
+		"sort=password",
+		// 只能过滤的字段不能排序
+		"sort=status",
+	} {
+		assertBadRequest(t, raw)
+	}
+}
+
+func TestParseOperatorNotAllowed(t *testing.T) {
+	for _, raw := range []string{
+		"filter[name][gt]=a",
+		"filter[status][like]=a",
+		"filter[active][in]=true",
+		"filter[id][regexp]=1",
+	} {
+		assertBadRequest(t, raw)
+	}
+}
+
+func TestParseBadValues(t *testing.T) {
+	for _, raw := range []string{
+		"filter[id]=abc",
+		"filter[id][in]=1,x",
+		"filter[active]=yes",
+		"filter[created_at][gte]=2024-01-02",
+		"filter[name][null]=maybe",
+	} {
+		assertBadRequest(t, raw)
+	}
+}
+
+func TestParsePageBounds(t *testing.T) {
+	query, err := parse(t, "page[number]=3&page[size]=50")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if query.Page != 3 || query.PageSize != 50 {
+		t.Fatalf("unexpected page: %d/%d", query.Page, query.PageSize)
+	}
+	for _, raw := range []string{
+		"page[size]=0",
+		"page[size]=51",
+		"page[size]=-1",
+		"page[size]=x",
+		"page[number]=0",
+		"page[number]=x",
+	} {
+		assertBadRequest(t, raw)
+	}
+}
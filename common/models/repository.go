@@ -0,0 +1,245 @@
+package models
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"time"
+
+	"com.github.gin-common/common/audit"
+	"com.github.gin-common/common/exceptions"
+	"com.github.gin-common/common/resp"
+	"github.com/go-sql-driver/mysql"
+	"gorm.io/gorm"
+)
+
+// 嵌入SoftDeleteModel的模型
+type softDeletable interface {
+	IsDeleted() bool
+}
+
+// 嵌入ActivateModel的模型
+type activatable interface {
+	IsActivated() bool
+}
+
+// 嵌入VersionModel的模型
+type versioned interface {
+	GetVersion() uint
+}
+
+var errVersionConflict = errors.New("数据已被修改，请刷新后重试")
+
+func IsDuplicateError(err error) bool {
+	if e, ok := err.(*mysql.MySQLError); ok {
+		return e.Number == uint16(1062)
+	}
+	return false
+}
+
+type RepositoryOption func(repository *Repository)
+
+// 记录不存在时返回的错误，默认为NotFound
+func WithNotFoundError(errDefFunc exceptions.ApiErrorDefFunc) RepositoryOption {
+	return func(repository *Repository) {
+		repository.notFound = errDefFunc
+	}
+}
+
+// 唯一索引冲突时返回的错误，默认为Conflict
+func WithDuplicateError(errDefFunc exceptions.ApiErrorDefFunc) RepositoryOption {
+	return func(repository *Repository) {
+		repository.duplicate = errDefFunc
+	}
+}
+
+// 嵌入BaseModel的模型的通用增删改查，记录不存在及唯一索引冲突时返回ApiError，其他数据库错误原样返回
+type Repository struct {
+	session   *gorm.DB
+	modelType reflect.Type
+	notFound  exceptions.ApiErrorDefFunc
+	duplicate exceptions.ApiErrorDefFunc
+}
+
+// model为模型指针，如 &model.User{}
+func NewRepository(session *gorm.DB, model interface{}, opts ...RepositoryOption) *Repository {
+	modelType := reflect.TypeOf(model)
+	if modelType.Kind() != reflect.Ptr || modelType.Elem().Kind() != reflect.Struct {
+		panic(fmt.Sprintf("repository: model must be a pointer to struct, got %s", modelType))
+	}
+	repository := &Repository{
+		session:   session,
+		modelType: modelType.Elem(),
+		notFound:  exceptions.NotFound,
+		duplicate: exceptions.Conflict,
+	}
+	for _, opt := range opts {
+		opt(repository)
+	}
+	return repository
+}
+
+// 使用指定的会话(如事务)
+func (repository *Repository) WithSession(session *gorm.DB) *Repository {
+	r := *repository
+	r.session = session
+	return &r
+}
+
+// 指定审计日志中记录的动作(见audit.ActionKey)
+func (repository *Repository) WithAction(action string) *Repository {
+	return repository.WithSession(repository.session.Set(audit.ActionKey, action).Session(&gorm.Session{WithConditions: true}))
+}
+
+func (repository *Repository) newModel() interface{} {
+	return reflect.New(repository.modelType).Interface()
+}
+
+func (repository *Repository) destOrNew(dest interface{}) interface{} {
+	if dest == nil {
+		return repository.newModel()
+	}
+	return dest
+}
+
+func (repository *Repository) mapError(err error) error {
+	if err == nil {
+		return nil
+	}
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return exceptions.GetDefinedErrors(repository.notFound)
+	}
+	if IsDuplicateError(err) {
+		return exceptions.GetDefinedErrors(repository.duplicate)
+	}
+	return err
+}
+
+func (repository *Repository) Create(value interface{}) error {
+	return repository.mapError(repository.session.Create(value).Error)
+}
+
+// 获取未删除的记录
+func (repository *Repository) Get(id uint, dest interface{}) error {
+	return repository.mapError(repository.session.Where("id = ?", id).Take(dest).Error)
+}
+
+// 将结构体(只取非零值字段)或map转换为更新的列
+func (repository *Repository) updatesMap(values interface{}) (map[string]interface{}, error) {
+	if m, ok := values.(map[string]interface{}); ok {
+		updates := make(map[string]interface{}, len(m)+1)
+		for k, v := range m {
+			updates[k] = v
+		}
+		return updates, nil
+	}
+	stmt := &gorm.Statement{DB: repository.session}
+	if err := stmt.Parse(values); err != nil {
+		return nil, err
+	}
+	value := reflect.Indirect(reflect.ValueOf(values))
+	updates := map[string]interface{}{}
+	for _, field := range stmt.Schema.Fields {
+		if field.DBName == "" || field.PrimaryKey || !field.Updatable {
+			continue
+		}
+		if v, isZero := field.ValueOf(value); !isZero {
+			updates[field.DBName] = v
+		}
+	}
+	return updates, nil
+}
+
+// 更新已获取的记录，values为结构体(只更新非零值字段)或map，更新后的值写回dest
+// 嵌入VersionModel时只在版本号与dest一致时更新并递增版本号，否则返回Conflict
+func (repository *Repository) Update(dest interface{}, values interface{}) error {
+	v, ok := dest.(versioned)
+	if !ok {
+		return repository.mapError(repository.session.Model(dest).Updates(values).Error)
+	}
+	updates, err := repository.updatesMap(values)
+	if err != nil {
+		return err
+	}
+	version := v.GetVersion()
+	updates["version"] = version + 1
+	result := repository.session.Model(dest).Where("version = ?", version).Updates(updates)
+	if result.Error != nil {
+		return repository.mapError(result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return exceptions.NewError(exceptions.Conflict, exceptions.WithError(errVersionConflict))()
+	}
+	return nil
+}
+
+// 获取记录并更新，dest为nil时不返回记录
+func (repository *Repository) updateByID(id uint, dest interface{}, action string, values map[string]interface{}, scopes ...func(*gorm.DB) *gorm.DB) error {
+	dest = repository.destOrNew(dest)
+	if err := repository.session.Scopes(scopes...).Where("id = ?", id).Take(dest).Error; err != nil {
+		return repository.mapError(err)
+	}
+	r := repository.WithAction(action)
+	return r.WithSession(r.session.Scopes(scopes...).Where("id = ?", id)).Update(dest, values)
+}
+
+// 只查询已软删除的记录
+func deletedOnly(db *gorm.DB) *gorm.DB {
+	return db.Unscoped().Where("delete_status = ?", true)
+}
+
+func (repository *Repository) mustSoftDelete() {
+	if _, ok := repository.newModel().(softDeletable); !ok {
+		panic(fmt.Sprintf("repository: %s does not embed SoftDeleteModel", repository.modelType))
+	}
+}
+
+func (repository *Repository) mustActivate() {
+	if _, ok := repository.newModel().(activatable); !ok {
+		panic(fmt.Sprintf("repository: %s does not embed ActivateModel", repository.modelType))
+	}
+}
+
+func (repository *Repository) SoftDelete(id uint, dest interface{}) error {
+	repository.mustSoftDelete()
+	return repository.updateByID(id, dest, "soft_delete", map[string]interface{}{"delete_status": true, "deleted_at": time.Now(), "delete_mark": id})
+}
+
+// 恢复已软删除的记录，记录不存在或未删除时返回notFound，唯一值已被占用时返回duplicate
+func (repository *Repository) Restore(id uint, dest interface{}) error {
+	repository.mustSoftDelete()
+	return repository.updateByID(id, dest, "restore", map[string]interface{}{"delete_status": false, "deleted_at": nil, "delete_mark": 0}, deletedOnly)
+}
+
+// 获取删除时间早于before的记录ID
+func (repository *Repository) DeletedBefore(before time.Time, limit int) ([]uint, error) {
+	repository.mustSoftDelete()
+	var ids []uint
+	err := repository.session.Model(repository.newModel()).Scopes(deletedOnly).
+		Where("deleted_at < ?", before).Order("id").Limit(limit).Pluck("id", &ids).Error
+	return ids, err
+}
+
+// 物理删除记录
+func (repository *Repository) Purge(ids []uint) (int64, error) {
+	if len(ids) == 0 {
+		return 0, nil
+	}
+	result := repository.WithAction("purge").session.Unscoped().Where("id IN ?", ids).Delete(repository.newModel())
+	return result.RowsAffected, result.Error
+}
+
+func (repository *Repository) Activate(id uint, dest interface{}) error {
+	repository.mustActivate()
+	return repository.updateByID(id, dest, "activate", map[string]interface{}{"activate_status": true, "activate_at": time.Now()})
+}
+
+func (repository *Repository) Deactivate(id uint, dest interface{}) error {
+	repository.mustActivate()
+	return repository.updateByID(id, dest, "deactivate", map[string]interface{}{"activate_status": false, "activate_at": time.Now()})
+}
+
+// 按查询参数分页获取未删除的记录，dest为模型切片指针
+func (repository *Repository) List(query *Query, dest interface{}) (resp.Pagination, error) {
+	return query.Find(repository.session.Model(repository.newModel()), dest)
+}
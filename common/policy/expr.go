@@ -0,0 +1,347 @@
+package policy
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// 策略表达式解析
+// expr       := or
+// or         := and ( ("or" | "||") and )*
+// and        := unary ( ("and" | "&&") unary )*
+// unary      := ("not" | "!") unary | primary
+// primary    := "(" expr ")" | "subject" "has" ("role" | "permission") name | operand [ ("==" | "!=") operand ]
+// operand    := subject.<attr> | resource.<attr> | number | string | true | false
+
+type tokenKind int
+
+const (
+	tokenEOF tokenKind = iota
+	tokenIdent
+	tokenNumber
+	tokenString
+	tokenOp
+)
+
+type token struct {
+	kind  tokenKind
+	value string
+}
+
+func tokenize(expr string) ([]token, error) {
+	var tokens []token
+	runes := []rune(expr)
+	for i := 0; i < len(runes); {
+		r := runes[i]
+		switch {
+		case unicode.IsSpace(r):
+			i++
+		case r == '(' || r == ')':
+			tokens = append(tokens, token{tokenOp, string(r)})
+			i++
+		case r == '=' || r == '!' || r == '&' || r == '|':
+			if i+1 < len(runes) && (runes[i+1] == '=' || (r != '=' && r != '!' && runes[i+1] == r)) {
+				tokens = append(tokens, token{tokenOp, string(runes[i : i+2])})
+				i += 2
+			} else if r == '!' {
+				tokens = append(tokens, token{tokenOp, "!"})
+				i++
+			} else {
+				return nil, fmt.Errorf("unexpected character %q at %d", r, i)
+			}
+		case r == '\'' || r == '"':
+			j := i + 1
+			for j < len(runes) && runes[j] != r {
+				j++
+			}
+			if j >= len(runes) {
+				return nil, fmt.Errorf("unterminated string at %d", i)
+			}
+			tokens = append(tokens, token{tokenString, string(runes[i+1 : j])})
+			i = j + 1
+		case unicode.IsDigit(r) || r == '-':
+			j := i + 1
+			for j < len(runes) && (unicode.IsDigit(runes[j]) || runes[j] == '.') {
+				j++
+			}
+			tokens = append(tokens, token{tokenNumber, string(runes[i:j])})
+			i = j
+		case unicode.IsLetter(r) || r == '_':
+			j := i + 1
+			for j < len(runes) && (unicode.IsLetter(runes[j]) || unicode.IsDigit(runes[j]) || runes[j] == '_' || runes[j] == '.' || runes[j] == ':' || runes[j] == '-') {
+				j++
+			}
+			tokens = append(tokens, token{tokenIdent, string(runes[i:j])})
+			i = j
+		default:
+			return nil, fmt.Errorf("unexpected character %q at %d", r, i)
+		}
+	}
+	return append(tokens, token{kind: tokenEOF}), nil
+}
+
+type parser struct {
+	tokens []token
+	pos    int
+}
+
+// 将表达式解析为规则
+func Parse(expr string) (Rule, error) {
+	tokens, err := tokenize(expr)
+	if err != nil {
+		return nil, err
+	}
+	p := &parser{tokens: tokens}
+	rule, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+	if p.peek().kind != tokenEOF {
+		return nil, fmt.Errorf("unexpected token %q", p.peek().value)
+	}
+	return rule, nil
+}
+
+func (p *parser) peek() token {
+	return p.tokens[p.pos]
+}
+
+func (p *parser) next() token {
+	t := p.tokens[p.pos]
+	if t.kind != tokenEOF {
+		p.pos++
+	}
+	return t
+}
+
+func (p *parser) isKeyword(values ...string) bool {
+	t := p.peek()
+	if t.kind != tokenIdent && t.kind != tokenOp {
+		return false
+	}
+	for _, v := range values {
+		if strings.EqualFold(t.value, v) {
+			return true
+		}
+	}
+	return false
+}
+
+func (p *parser) parseOr() (Rule, error) {
+	left, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+	rules := []Rule{left}
+	for p.isKeyword("or", "||") {
+		p.next()
+		right, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		rules = append(rules, right)
+	}
+	if len(rules) == 1 {
+		return left, nil
+	}
+	return Any(rules...), nil
+}
+
+func (p *parser) parseAnd() (Rule, error) {
+	left, err := p.parseUnary()
+	if err != nil {
+		return nil, err
+	}
+	rules := []Rule{left}
+	for p.isKeyword("and", "&&") {
+		p.next()
+		right, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		rules = append(rules, right)
+	}
+	if len(rules) == 1 {
+		return left, nil
+	}
+	return All(rules...), nil
+}
+
+func (p *parser) parseUnary() (Rule, error) {
+	if p.isKeyword("not", "!") {
+		p.next()
+		rule, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		return Not(rule), nil
+	}
+	return p.parsePrimary()
+}
+
+func (p *parser) parsePrimary() (Rule, error) {
+	if p.isKeyword("(") {
+		p.next()
+		rule, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		if !p.isKeyword(")") {
+			return nil, fmt.Errorf("expected ')'")
+		}
+		p.next()
+		return rule, nil
+	}
+	// subject has role xxx / subject has permission xxx
+	if p.isKeyword("subject") && p.tokens[p.pos+1].kind == tokenIdent && strings.EqualFold(p.tokens[p.pos+1].value, "has") {
+		p.next()
+		p.next()
+		kind := p.next()
+		name := p.next()
+		if name.kind != tokenIdent && name.kind != tokenString {
+			return nil, fmt.Errorf("expected name after 'has %s'", kind.value)
+		}
+		switch strings.ToLower(kind.value) {
+		case "role":
+			return HasRole(name.value), nil
+		case "permission":
+			return HasPermission(name.value), nil
+		default:
+			return nil, fmt.Errorf("unknown 'has' target %q", kind.value)
+		}
+	}
+
+	left, err := p.parseOperand()
+	if err != nil {
+		return nil, err
+	}
+	if !p.isKeyword("==", "!=") {
+		return RuleFunc(func(subject Subject, resource Resource) bool {
+			return truthy(left(subject, resource))
+		}), nil
+	}
+	op := p.next().value
+	right, err := p.parseOperand()
+	if err != nil {
+		return nil, err
+	}
+	return RuleFunc(func(subject Subject, resource Resource) bool {
+		equal := valueEqual(left(subject, resource), right(subject, resource))
+		if op == "==" {
+			return equal
+		}
+		return !equal
+	}), nil
+}
+
+type operand func(subject Subject, resource Resource) interface{}
+
+func (p *parser) parseOperand() (operand, error) {
+	t := p.next()
+	switch t.kind {
+	case tokenNumber:
+		f, err := strconv.ParseFloat(t.value, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid number %q", t.value)
+		}
+		return func(Subject, Resource) interface{} { return f }, nil
+	case tokenString:
+		v := t.value
+		return func(Subject, Resource) interface{} { return v }, nil
+	case tokenIdent:
+		switch strings.ToLower(t.value) {
+		case "true":
+			return func(Subject, Resource) interface{} { return true }, nil
+		case "false":
+			return func(Subject, Resource) interface{} { return false }, nil
+		}
+		parts := strings.SplitN(t.value, ".", 2)
+		if len(parts) != 2 {
+			return nil, fmt.Errorf("unknown identifier %q", t.value)
+		}
+		attr := parts[1]
+		switch parts[0] {
+		case "subject":
+			return func(subject Subject, resource Resource) interface{} { return subjectAttr(subject, attr) }, nil
+		case "resource":
+			return func(subject Subject, resource Resource) interface{} { return resourceAttr(resource, attr) }, nil
+		}
+		return nil, fmt.Errorf("unknown identifier %q", t.value)
+	}
+	return nil, fmt.Errorf("unexpected token %q", t.value)
+}
+
+func subjectAttr(subject Subject, attr string) interface{} {
+	if attr == "id" {
+		return subject.ID
+	}
+	return subject.Attrs[attr]
+}
+
+func resourceAttr(resource Resource, attr string) interface{} {
+	switch attr {
+	case "id":
+		return resource.ID
+	case "owner_id":
+		return resource.OwnerID
+	case "type":
+		return resource.Type
+	}
+	return resource.Attrs[attr]
+}
+
+func toFloat(v interface{}) (float64, bool) {
+	switch n := v.(type) {
+	case int:
+		return float64(n), true
+	case int8:
+		return float64(n), true
+	case int16:
+		return float64(n), true
+	case int32:
+		return float64(n), true
+	case int64:
+		return float64(n), true
+	case uint:
+		return float64(n), true
+	case uint8:
+		return float64(n), true
+	case uint16:
+		return float64(n), true
+	case uint32:
+		return float64(n), true
+	case uint64:
+		return float64(n), true
+	case float32:
+		return float64(n), true
+	case float64:
+		return n, true
+	}
+	return 0, false
+}
+
+func valueEqual(a interface{}, b interface{}) bool {
+	if fa, ok := toFloat(a); ok {
+		fb, ok := toFloat(b)
+		return ok && fa == fb
+	}
+	// 属性值可能为切片、map等不可比较类型，直接 == 会panic
+	return reflect.DeepEqual(a, b)
+}
+
+func truthy(v interface{}) bool {
+	switch b := v.(type) {
+	case nil:
+		return false
+	case bool:
+		return b
+	case string:
+		return b != ""
+	}
+	// 未知类型视为不满足
+	f, ok := toFloat(v)
+	return ok && f != 0
+}
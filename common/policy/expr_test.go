@@ -0,0 +1,130 @@
+package policy_test
+
+import (
+	"testing"
+
+	"com.github.gin-common/common/policy"
+	"com.github.gin-common/common/policy/policytest"
+)
+
+func newEngine(t *testing.T, exprs map[string]string) *policy.Engine {
+	t.Helper()
+	engine := policy.NewEngine()
+	for name, expr := range exprs {
+		if err := engine.RegisterExpr(name, expr); err != nil {
+			t.Fatalf("register %s: %v", name, err)
+		}
+	}
+	return engine
+}
+
+func TestParsePrecedence(t *testing.T) {
+	engine := newEngine(t, map[string]string{
+		// and优先于or: true or (false and false)
+		"and_before_or": "true or false and false",
+		"parens":        "(true or false) and false",
+		"symbols":       "true || false && false",
+	})
+	policytest.AssertAllowed(t, engine, "and_before_or", policy.Subject{}, policy.Resource{})
+	policytest.AssertDenied(t, engine, "parens", policy.Subject{}, policy.Resource{})
+	policytest.AssertAllowed(t, engine, "symbols", policy.Subject{}, policy.Resource{})
+}
+
+func TestParseNot(t *testing.T) {
+	engine := newEngine(t, map[string]string{
+		"not":        "not subject has role admin",
+		"bang":       "!(subject.id == resource.owner_id)",
+		"double_not": "not not true",
+		"not_and":    "not false and false",
+	})
+	admin := policy.Subject{ID: 1, Roles: []string{"admin"}}
+	user := policy.Subject{ID: 2}
+	policytest.AssertDenied(t, engine, "not", admin, policy.Resource{})
+	policytest.AssertAllowed(t, engine, "not", user, policy.Resource{})
+	policytest.AssertDenied(t, engine, "bang", user, policy.Resource{OwnerID: 2})
+	policytest.AssertAllowed(t, engine, "bang", user, policy.Resource{OwnerID: 3})
+	policytest.AssertAllowed(t, engine, "double_not", user, policy.Resource{})
+	// not只作用于紧随的表达式
+	policytest.AssertDenied(t, engine, "not_and", user, policy.Resource{})
+}
+
+func TestParseHas(t *testing.T) {
+	engine := newEngine(t, map[string]string{
+		"role":       "subject has role admin",
+		"permission": "subject has permission 'user:update'",
+	})
+	policytest.AssertCases(t, engine, "role", []policytest.Case{
+		{Name: "has role", Subject: policy.Subject{Roles: []string{"user", "admin"}}, Allowed: true},
+		{Name: "other role", Subject: policy.Subject{Roles: []string{"user"}}, Allowed: false},
+		{Name: "no role", Subject: policy.Subject{}, Allowed: false},
+	})
+	policytest.AssertCases(t, engine, "permission", []policytest.Case{
+		{Name: "has permission", Subject: policy.Subject{Permissions: []string{"user:update"}}, Allowed: true},
+		{Name: "wildcard", Subject: policy.Subject{Permissions: []string{"*"}}, Allowed: true},
+		{Name: "other permission", Subject: policy.Subject{Permissions: []string{"user:read"}}, Allowed: false},
+	})
+}
+
+func TestParseComparison(t *testing.T) {
+	engine := newEngine(t, map[string]string{
+		"owner":    "subject.id == resource.owner_id",
+		"type":     "resource.type != 'user'",
+		"number":   "resource.level == 3",
+		"attr":     "subject.active",
+		"slice_eq": "subject.tags == resource.tags",
+	})
+	policytest.AssertCases(t, engine, "owner", []policytest.Case{
+		{Name: "owner", Subject: policy.Subject{ID: 1}, Resource: policy.Resource{OwnerID: 1}, Allowed: true},
+		{Name: "not owner", Subject: policy.Subject{ID: 1}, Resource: policy.Resource{OwnerID: 2}, Allowed: false},
+	})
+	policytest.AssertCases(t, engine, "type", []policytest.Case{
+		{Name: "user", Resource: policy.Resource{Type: "user"}, Allowed: false},
+		{Name: "role", Resource: policy.Resource{Type: "role"}, Allowed: true},
+	})
+	policytest.AssertCases(t, engine, "number", []policytest.Case{
+		{Name: "int attr", Resource: policy.Resource{Attrs: map[string]interface{}{"level": 3}}, Allowed: true},
+		{Name: "string attr", Resource: policy.Resource{Attrs: map[string]interface{}{"level": "3"}}, Allowed: false},
+	})
+	policytest.AssertCases(t, engine, "attr", []policytest.Case{
+		{Name: "true", Subject: policy.Subject{Attrs: map[string]interface{}{"active": true}}, Allowed: true},
+		{Name: "false", Subject: policy.Subject{Attrs: map[string]interface{}{"active": false}}, Allowed: false},
+		{Name: "missing", Subject: policy.Subject{}, Allowed: false},
+		{Name: "unknown type", Subject: policy.Subject{Attrs: map[string]interface{}{"active": []string{"x"}}}, Allowed: false},
+	})
+	// 不可比较的类型不能panic
+	tags := map[string]interface{}{"tags": []string{"a"}}
+	policytest.AssertCases(t, engine, "slice_eq", []policytest.Case{
+		{Name: "equal slices", Subject: policy.Subject{Attrs: tags}, Resource: policy.Resource{Attrs: tags}, Allowed: true},
+		{Name: "different slices", Subject: policy.Subject{Attrs: tags}, Resource: policy.Resource{Attrs: map[string]interface{}{"tags": []string{"b"}}}, Allowed: false},
+		{Name: "map and slice", Subject: policy.Subject{Attrs: map[string]interface{}{"tags": map[string]int{"a": 1}}}, Resource: policy.Resource{Attrs: tags}, Allowed: false},
+	})
+}
+
+func TestParseMalformed(t *testing.T) {
+	for _, expr := range []string{
+		"",
+		"subject.id ==",
+		"(subject.id == 1",
+		"subject.id == 1)",
+		"subject has role",
+		"subject has group admin",
+		"owner_id == 1",
+		"subject.id = 1",
+		"'unterminated",
+		"true and",
+		"not",
+		"true false",
+		"subject.id == 1 # 2",
+	} {
+		if _, err := policy.Parse(expr); err == nil {
+			t.Errorf("expected error for %q", expr)
+		}
+	}
+}
+
+func TestEvaluateUnknownPolicy(t *testing.T) {
+	engine := policy.NewEngine()
+	if _, err := engine.Evaluate("missing", policy.Subject{}, policy.Resource{}); err == nil {
+		t.Fatal("expected error for unregistered policy")
+	}
+}
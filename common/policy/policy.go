@@ -0,0 +1,186 @@
+package policy
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"sync"
+
+	"com.github.gin-common/common/exceptions"
+)
+
+// 资源级授权策略，例如: "subject.id == resource.owner_id or subject has role admin"
+
+var ErrPolicyNotFound = errors.New("policy: policy not found")
+
+// 访问主体(一般为当前登录用户)
+type Subject struct {
+	ID          uint
+	Roles       []string
+	Permissions []string
+	Attrs       map[string]interface{}
+}
+
+func (s Subject) HasRole(role string) bool {
+	for _, r := range s.Roles {
+		if r == role {
+			return true
+		}
+	}
+	return false
+}
+
+func (s Subject) HasPermission(permission string) bool {
+	for _, p := range s.Permissions {
+		if p == permission || p == "*" {
+			return true
+		}
+	}
+	return false
+}
+
+// 被访问的资源
+type Resource struct {
+	Type    string
+	ID      uint
+	OwnerID uint
+	Attrs   map[string]interface{}
+}
+
+type Rule interface {
+	Evaluate(subject Subject, resource Resource) bool
+}
+
+type RuleFunc func(subject Subject, resource Resource) bool
+
+func (f RuleFunc) Evaluate(subject Subject, resource Resource) bool {
+	return f(subject, resource)
+}
+
+// 主体为资源的所有者
+func IsOwner() Rule {
+	return RuleFunc(func(subject Subject, resource Resource) bool {
+		return subject.ID != 0 && subject.ID == resource.OwnerID
+	})
+}
+
+func HasRole(role string) Rule {
+	return RuleFunc(func(subject Subject, resource Resource) bool {
+		return subject.HasRole(role)
+	})
+}
+
+func HasPermission(permission string) Rule {
+	return RuleFunc(func(subject Subject, resource Resource) bool {
+		return subject.HasPermission(permission)
+	})
+}
+
+// 任一规则满足
+func Any(rules ...Rule) Rule {
+	return RuleFunc(func(subject Subject, resource Resource) bool {
+		for _, rule := range rules {
+			if rule.Evaluate(subject, resource) {
+				return true
+			}
+		}
+		return false
+	})
+}
+
+// 所有规则均满足
+func All(rules ...Rule) Rule {
+	return RuleFunc(func(subject Subject, resource Resource) bool {
+		for _, rule := range rules {
+			if !rule.Evaluate(subject, resource) {
+				return false
+			}
+		}
+		return true
+	})
+}
+
+func Not(rule Rule) Rule {
+	return RuleFunc(func(subject Subject, resource Resource) bool {
+		return !rule.Evaluate(subject, resource)
+	})
+}
+
+type Engine struct {
+	policies map[string]Rule
+	mu       sync.RWMutex
+}
+
+func NewEngine() *Engine {
+	return &Engine{policies: map[string]Rule{}}
+}
+
+// 注册策略，同名策略会被覆盖
+func (e *Engine) Register(name string, rule Rule) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.policies[name] = rule
+}
+
+// 使用表达式注册策略
+func (e *Engine) RegisterExpr(name string, expr string) error {
+	rule, err := Parse(expr)
+	if err != nil {
+		return fmt.Errorf("policy %s: %w", name, err)
+	}
+	e.Register(name, rule)
+	return nil
+}
+
+// 从json文件加载策略，格式: {"policy name": "expression", ...}
+func (e *Engine) LoadFile(path string) error {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	var exprs map[string]string
+	if err = json.Unmarshal(data, &exprs); err != nil {
+		return err
+	}
+	rules := make(map[string]Rule, len(exprs))
+	for name, expr := range exprs {
+		rule, err := Parse(expr)
+		if err != nil {
+			return fmt.Errorf("policy %s: %w", name, err)
+		}
+		rules[name] = rule
+	}
+	for name, rule := range rules {
+		e.Register(name, rule)
+	}
+	return nil
+}
+
+func (e *Engine) Evaluate(name string, subject Subject, resource Resource) (bool, error) {
+	e.mu.RLock()
+	rule, ok := e.policies[name]
+	e.mu.RUnlock()
+	if !ok {
+		return false, fmt.Errorf("%w: %s", ErrPolicyNotFound, name)
+	}
+	return rule.Evaluate(subject, resource), nil
+}
+
+// 校验策略，不满足时返回Forbidden异常
+func (e *Engine) Authorize(name string, subject Subject, resource Resource) error {
+	allowed, err := e.Evaluate(name, subject, resource)
+	if err != nil {
+		return err
+	}
+	if !allowed {
+		return exceptions.NewError(exceptions.Forbidden, exceptions.WithError(fmt.Errorf("没有权限: %s", name)))()
+	}
+	return nil
+}
+
+var defaultEngine = NewEngine()
+
+func Default() *Engine {
+	return defaultEngine
+}
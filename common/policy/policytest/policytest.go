@@ -0,0 +1,51 @@
+package policytest
+
+import (
+	"testing"
+
+	"com.github.gin-common/common/policy"
+)
+
+// 策略测试辅助
+
+type Case struct {
+	Name     string
+	Subject  policy.Subject
+	Resource policy.Resource
+	Allowed  bool
+}
+
+func AssertAllowed(t testing.TB, engine *policy.Engine, name string, subject policy.Subject, resource policy.Resource) {
+	t.Helper()
+	assertOutcome(t, engine, name, subject, resource, true)
+}
+
+func AssertDenied(t testing.TB, engine *policy.Engine, name string, subject policy.Subject, resource policy.Resource) {
+	t.Helper()
+	assertOutcome(t, engine, name, subject, resource, false)
+}
+
+// 批量校验策略结果
+func AssertCases(t testing.TB, engine *policy.Engine, name string, cases []Case) {
+	t.Helper()
+	for _, c := range cases {
+		allowed, err := engine.Evaluate(name, c.Subject, c.Resource)
+		if err != nil {
+			t.Fatalf("policy %s case %s: %v", name, c.Name, err)
+		}
+		if allowed != c.Allowed {
+			t.Errorf("policy %s case %s: expected allowed=%v, got %v", name, c.Name, c.Allowed, allowed)
+		}
+	}
+}
+
+func assertOutcome(t testing.TB, engine *policy.Engine, name string, subject policy.Subject, resource policy.Resource, expected bool) {
+	t.Helper()
+	allowed, err := engine.Evaluate(name, subject, resource)
+	if err != nil {
+		t.Fatalf("policy %s: %v", name, err)
+	}
+	if allowed != expected {
+		t.Errorf("policy %s: expected allowed=%v, got %v (subject=%+v, resource=%+v)", name, expected, allowed, subject, resource)
+	}
+}
@@ -0,0 +1,75 @@
+package request_scope
+
+import (
+	"context"
+	"regexp"
+
+	"com.github.gin-common/common/client_ip"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+const HeaderRequestID = "X-Request-ID"
+
+// 客户端传入的请求ID只接受该格式，否则重新生成
+var requestIDPattern = regexp.MustCompile(`^[A-Za-z0-9._-]{1,64}$`)
+
+// 请求范围内的信息，由Middleware创建并保存在请求的context中，认证通过后由AuthMiddleware写入用户
+type Scope struct {
+	RequestID string
+	IP        string
+	UserAgent string
+	// 未认证时为0
+	UserID   uint
+	Username string
+}
+
+// 未加载Middleware时scope为nil，忽略
+func (scope *Scope) SetUser(id uint, username string) {
+	if scope == nil {
+		return
+	}
+	scope.UserID = id
+	scope.Username = username
+}
+
+func (scope *Scope) Authenticated() bool {
+	return scope != nil && scope.UserID != 0
+}
+
+type scopeKey struct{}
+
+func WithScope(ctx context.Context, scope *Scope) context.Context {
+	return context.WithValue(ctx, scopeKey{}, scope)
+}
+
+// 获取context中的Scope，不存在时(如定时任务)返回nil
+func FromContext(ctx context.Context) *Scope {
+	if ctx == nil {
+		return nil
+	}
+	scope, _ := ctx.Value(scopeKey{}).(*Scope)
+	return scope
+}
+
+func Get(c *gin.Context) *Scope {
+	return FromContext(c.Request.Context())
+}
+
+// 生成请求ID(或沿用合法的X-Request-ID)并创建Scope，需在其他中间件之前加载
+func Middleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		requestID := c.GetHeader(HeaderRequestID)
+		if !requestIDPattern.MatchString(requestID) {
+			requestID = uuid.New().String()
+		}
+		c.Header(HeaderRequestID, requestID)
+		scope := &Scope{
+			RequestID: requestID,
+			IP:        client_ip.Get(c),
+			UserAgent: c.Request.UserAgent(),
+		}
+		c.Request = c.Request.WithContext(WithScope(c.Request.Context(), scope))
+		c.Next()
+	}
+}
@@ -0,0 +1,35 @@
+package resp
+
+import "github.com/gin-gonic/gin"
+
+// 分页方式
+const (
+	PageModeOffset = "offset"
+	PageModeCursor = "cursor"
+)
+
+// 分页信息，offset分页返回page和total，cursor分页返回next_cursor
+type Pagination struct {
+	Mode       string `json:"mode"`
+	PageSize   int    `json:"page_size"`
+	Page       int    `json:"page,omitempty"`
+	Total      *int64 `json:"total,omitempty"`
+	HasMore    bool   `json:"has_more"`
+	NextCursor string `json:"next_cursor,omitempty"`
+}
+
+func OffsetPagination(page int, pageSize int, total int64, hasMore bool) Pagination {
+	return Pagination{Mode: PageModeOffset, Page: page, PageSize: pageSize, Total: &total, HasMore: hasMore}
+}
+
+func CursorPagination(pageSize int, nextCursor string, hasMore bool) Pagination {
+	return Pagination{Mode: PageModeCursor, PageSize: pageSize, NextCursor: nextCursor, HasMore: hasMore}
+}
+
+// 分页响应数据: {"items": [...], "pagination": {...}}
+func PageData(items interface{}, pagination Pagination) gin.H {
+	return gin.H{
+		"items":      items,
+		"pagination": pagination,
+	}
+}
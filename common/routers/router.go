@@ -1,9 +1,11 @@
 package routers
 
 import (
+	"path"
 	"reflect"
 	"runtime"
 	"strings"
+	"sync"
 
 	"com.github.gin-common/common/controllers"
 
@@ -14,6 +16,38 @@ type RouteDesc struct {
 	Method     string
 	MiddleWare []controllers.MiddlewareFunc
 	Controller []controllers.ControllerFunc
+	// 访问该路由所需的权限，为空时不校验
+	Permission string
+}
+
+// 路由权限表 key: METHOD + 空格 + 完整路径
+var routePermissions = map[string]string{}
+var routePermissionsMu sync.RWMutex
+
+func routeKey(method string, fullPath string) string {
+	return strings.ToUpper(method) + " " + fullPath
+}
+
+// 获取当前请求所匹配路由声明的权限
+func RoutePermission(context *gin.Context) string {
+	routePermissionsMu.RLock()
+	defer routePermissionsMu.RUnlock()
+	return routePermissions[routeKey(context.Request.Method, context.FullPath())]
+}
+
+// 获取所有路由声明的权限
+func DeclaredPermissions() []string {
+	routePermissionsMu.RLock()
+	defer routePermissionsMu.RUnlock()
+	var flag = make(map[string]bool)
+	var permissions []string
+	for _, permission := range routePermissions {
+		if !flag[permission] {
+			flag[permission] = true
+			permissions = append(permissions, permission)
+		}
+	}
+	return permissions
 }
 
 type GinRouterInterface interface {
@@ -91,10 +125,26 @@ func handleGroupConfigs(group *gin.RouterGroup, groupConfigs map[string][]RouteD
 
 			process := getFuncByMethod(method)
 			process(group, relativePath, controllerHandlers...)
+			if routeDesc.Permission != "" {
+				routePermissionsMu.Lock()
+				routePermissions[routeKey(method, joinPath(group.BasePath(), relativePath))] = routeDesc.Permission
+				routePermissionsMu.Unlock()
+			}
 		}
 	}
 }
 
+func joinPath(basePath string, relativePath string) string {
+	if relativePath == "" {
+		return basePath
+	}
+	finalPath := path.Join(basePath, relativePath)
+	if strings.HasSuffix(relativePath, "/") && !strings.HasSuffix(finalPath, "/") {
+		return finalPath + "/"
+	}
+	return finalPath
+}
+
 func CombineRouters(engine *gin.Engine, routers ...GinRouterInterface) {
 	for _, router := range routers {
 		handleRouter(engine, router)
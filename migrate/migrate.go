@@ -1,8 +1,13 @@
 package migrate
 
 import (
+	"fmt"
+
 	"com.github.gin-common/app/model"
+	"com.github.gin-common/common/audit"
 	"com.github.gin-common/tools/db_tool"
+	"com.github.gin-common/util"
+	"gorm.io/gorm"
 )
 
 func doMigrate(dst ...interface{}) {
@@ -12,6 +17,66 @@ func doMigrate(dst ...interface{}) {
 	}
 }
 
+// 唯一约束只作用于未删除的记录: 将单列唯一索引替换为(column, delete_mark)联合唯一索引
+func migrateSoftDeleteUnique(db *gorm.DB, m interface{}, column string) {
+	stmt := &gorm.Statement{DB: db}
+	if err := stmt.Parse(m); err != nil {
+		panic(err)
+	}
+	table := stmt.Schema.Table
+	migrator := db.Migrator()
+	// 旧版本unique标签创建的索引与列同名
+	if migrator.HasIndex(m, column) {
+		if err := migrator.DropIndex(m, column); err != nil {
+			panic(err)
+		}
+	}
+	name := fmt.Sprintf("idx_%s_%s_delete_mark", table, column)
+	if migrator.HasIndex(m, name) {
+		return
+	}
+	err := db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Table(table).Where("delete_status = ? AND delete_mark = ?", true, 0).Update("delete_mark", gorm.Expr("id")).Error; err != nil {
+			return err
+		}
+		return tx.Exec(fmt.Sprintf("CREATE UNIQUE INDEX `%s` ON `%s` (`%s`, `delete_mark`)", name, table, column)).Error
+	})
+	if err != nil {
+		panic(err)
+	}
+}
+
+func seedAdminRole(db *gorm.DB) {
+	// 初始化超级管理员角色, 若配置了ADMIN_USERNAME则将该用户设为超级管理员
+	err := db.Transaction(func(tx *gorm.DB) error {
+		permission := model.Permission{}
+		if err := tx.Where(model.Permission{Code: model.PermissionAll}).FirstOrCreate(&permission).Error; err != nil {
+			return err
+		}
+		role := model.Role{}
+		if err := tx.Where(model.Role{Name: model.RoleAdmin}).Attrs(model.Role{Description: "超级管理员"}).FirstOrCreate(&role).Error; err != nil {
+			return err
+		}
+		if err := tx.Model(&role).Association("Permissions").Append(&permission); err != nil {
+			return err
+		}
+		username := util.GetDefaultEnv("ADMIN_USERNAME", "")
+		if username == "" {
+			return nil
+		}
+		user := model.User{}
+		if err := tx.Where("username=?", username).Take(&user).Error; err != nil {
+			return err
+		}
+		return tx.Where(model.UserRole{UserID: user.ID, RoleID: role.ID}).FirstOrCreate(&model.UserRole{}).Error
+	})
+	if err != nil {
+		panic(err)
+	}
+}
+
 func Migrate() {
-	doMigrate(model.User{})
+	doMigrate(model.User{}, model.Permission{}, model.Role{}, model.UserRole{}, model.ApiKey{}, model.UserMFA{}, model.MFARecoveryCode{}, model.LoginLockout{}, model.UserIdentity{}, model.OAuthClient{}, model.OAuthConsent{}, model.PasswordHistory{}, audit.AuditLog{})
+	migrateSoftDeleteUnique(db_tool.GetDB(), &model.User{}, "username")
+	seedAdminRole(db_tool.GetDB())
 }
@@ -1,20 +1,31 @@
 package main
 
 import (
+	"net/http"
 	"os"
+	"strconv"
+	"time"
 
 	"github.com/gin-gonic/gin"
 
 	"com.github.gin-common/common/gin_recovery"
+	"com.github.gin-common/common/jobs"
+	"com.github.gin-common/common/lifecycle"
+	"com.github.gin-common/common/policy"
+	"com.github.gin-common/common/request_scope"
 
 	"go.uber.org/zap"
 
 	"com.github.gin-common/common/loggers/gin_logger"
 	"com.github.gin-common/common/routers"
 	"com.github.gin-common/common/validator_trans"
+	"com.github.gin-common/tools/jwt_tool"
+	"com.github.gin-common/wires"
 
 	"com.github.gin-common/util"
 
+	appJob "com.github.gin-common/app/job"
+	appPolicy "com.github.gin-common/app/policy"
 	"com.github.gin-common/app/router"
 
 	"com.github.gin-common/migrate"
@@ -25,6 +36,9 @@ import (
 var routerConfigs = []routers.GinRouterInterface{
 	router.UserRouter{},
 	router.AuthRouter{},
+	router.AdminRouter{},
+	router.WellKnownRouter{},
+	router.OAuthRouter{},
 }
 
 func setGinMode() {
@@ -51,13 +65,39 @@ func main() {
 	r.Use(gin_logger.LoggerWithWriter(gin.DefaultWriter, util.GetLogLevel(ginLogLevel), []zap.Option{}))
 	// 加载recover中间件
 	r.Use(gin_recovery.Recovery())
+	// 生成请求ID，保存请求范围内的操作人等信息
+	r.Use(request_scope.Middleware())
 
 	gin.DebugPrintRouteFunc = func(httpMethod, absolutePath, handlerName string, nuHandlers int) {
 		gin_logger.Log.Info("", zap.String("httpMethod", httpMethod), zap.String("absolutePath", absolutePath))
 	}
 	routers.CombineRouters(r, routerConfigs...)
 
-	if err := r.Run(":8080"); err != nil {
+	// 注册资源级授权策略，POLICY_FILE中的同名策略会覆盖代码中的声明
+	appPolicy.RegisterPolicies(policy.Default())
+	if policyFile := util.GetDefaultEnv("POLICY_FILE", ""); policyFile != "" {
+		if err := policy.Default().LoadFile(policyFile); err != nil {
+			gin_logger.Log.Fatal(err.Error())
+		}
+	}
+
+	if err := appJob.RegisterJobs(jobs.GetCron(), wires.UserService, *gin_logger.Log); err != nil {
+		gin_logger.Log.Fatal(err.Error())
+	}
+
+	shutdownTimeout, err := strconv.Atoi(util.GetDefaultEnv("SHUTDOWN_TIMEOUT", "30"))
+	if err != nil {
+		gin_logger.Log.Fatal(err.Error())
+	}
+	// 先启动定时任务及token注销列表订阅再启动http服务，退出时先停止http服务再停止定时任务
+	app := lifecycle.New()
+	app.Append(jobs.CronComponent{}, jwt_tool.DenylistComponent{}, lifecycle.NewHTTPServer(&http.Server{
+		Addr:    util.GetDefaultEnv("SERVER_ADDR", ":8080"),
+		Handler: r,
+	}, app))
+	err = app.Run(time.Duration(shutdownTimeout) * time.Second)
+	_ = gin_logger.Log.Sync()
+	if err != nil {
 		gin_logger.Log.Fatal(err.Error())
 	}
 }
@@ -12,7 +12,9 @@ import (
 
 	"go.uber.org/zap/zapcore"
 
+	"com.github.gin-common/common/audit"
 	"com.github.gin-common/common/loggers/gorm_logger"
+	"com.github.gin-common/common/models"
 	"go.uber.org/zap"
 
 	"gorm.io/driver/mysql"
@@ -75,6 +77,10 @@ func GetDB() *gorm.DB {
 		util.PanicError(err)
 		db, err = gorm.Open(mysql.Open(getMySqlDsn()), config)
 		util.PanicError(err)
+		// 增删改实现audit.Auditable的模型时记录审计日志
+		util.PanicError(db.Use(audit.Plugin{}))
+		// 自动填充嵌入models.OperatorModel的模型的创建人及修改人
+		util.PanicError(db.Use(models.OperatorPlugin{}))
 
 		// 获取通用数据库对象 sql.DB ，然后使用其提供的功能
 		sqlDB, err = db.DB()
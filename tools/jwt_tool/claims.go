@@ -0,0 +1,189 @@
+package jwt_tool
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"com.github.gin-common/util"
+	"github.com/dgrijalva/jwt-go"
+	"github.com/google/uuid"
+)
+
+// 标准声明(iss/aud/sub/iat/nbf/jti/exp)的签发与校验
+
+var (
+	ErrTokenMalformed        = errors.New("jwt: token malformed")
+	ErrTokenSignatureInvalid = errors.New("jwt: token signature invalid")
+	ErrTokenExpired          = errors.New("jwt: token expired")
+	ErrTokenNotYetValid      = errors.New("jwt: token not valid yet")
+	ErrTokenIssuerInvalid    = errors.New("jwt: token issuer invalid")
+	ErrTokenAudienceInvalid  = errors.New("jwt: token audience invalid")
+	ErrTokenClaimMissing     = errors.New("jwt: token claim missing")
+)
+
+type ClaimsPolicy struct {
+	// 签发者
+	Issuer string
+	// 签发时写入的受众
+	Audience string
+	// 校验时允许的受众
+	AllowedAudiences []string
+	// 允许的时钟偏差
+	ClockSkew time.Duration
+	// 必须存在的声明
+	RequiredClaims []string
+}
+
+// 写入标准声明，已存在的声明不会被覆盖(exp除外)
+func (p *ClaimsPolicy) Stamp(claims jwt.MapClaims, expire time.Duration) {
+	now := time.Now()
+	setDefault := func(name string, value interface{}) {
+		if _, ok := claims[name]; !ok {
+			claims[name] = value
+		}
+	}
+	setDefault("iss", p.Issuer)
+	setDefault("aud", p.Audience)
+	setDefault("iat", now.Unix())
+	setDefault("nbf", now.Unix())
+	setDefault("jti", uuid.New().String())
+	claims["exp"] = now.Add(expire).Unix()
+}
+
+func numericClaim(claims jwt.MapClaims, name string) (int64, bool, error) {
+	value, ok := claims[name]
+	if !ok {
+		return 0, false, nil
+	}
+	switch v := value.(type) {
+	case float64:
+		return int64(v), true, nil
+	case int64:
+		return v, true, nil
+	case int:
+		return int64(v), true, nil
+	case string:
+		n, err := strconv.ParseInt(v, 10, 64)
+		if err == nil {
+			return n, true, nil
+		}
+	}
+	return 0, true, fmt.Errorf("%w: %s is not numeric", ErrTokenMalformed, name)
+}
+
+func audiences(claims jwt.MapClaims) []string {
+	switch v := claims["aud"].(type) {
+	case string:
+		return []string{v}
+	case []string:
+		return v
+	case []interface{}:
+		result := make([]string, 0, len(v))
+		for _, item := range v {
+			if s, ok := item.(string); ok {
+				result = append(result, s)
+			}
+		}
+		return result
+	}
+	return nil
+}
+
+// 校验标准声明
+func (p *ClaimsPolicy) Validate(claims jwt.MapClaims, now time.Time) error {
+	for _, name := range p.RequiredClaims {
+		if _, ok := claims[name]; !ok {
+			return fmt.Errorf("%w: %s", ErrTokenClaimMissing, name)
+		}
+	}
+	skew := int64(p.ClockSkew / time.Second)
+	unix := now.Unix()
+
+	exp, ok, err := numericClaim(claims, "exp")
+	if err != nil {
+		return err
+	}
+	if ok && unix > exp+skew {
+		return ErrTokenExpired
+	}
+	nbf, ok, err := numericClaim(claims, "nbf")
+	if err != nil {
+		return err
+	}
+	if ok && unix < nbf-skew {
+		return ErrTokenNotYetValid
+	}
+	iat, ok, err := numericClaim(claims, "iat")
+	if err != nil {
+		return err
+	}
+	if ok && unix < iat-skew {
+		return fmt.Errorf("%w: issued in the future", ErrTokenNotYetValid)
+	}
+
+	if p.Issuer != "" {
+		if iss, _ := claims["iss"].(string); iss != p.Issuer {
+			return fmt.Errorf("%w: %s", ErrTokenIssuerInvalid, iss)
+		}
+	}
+	if len(p.AllowedAudiences) > 0 {
+		allowed := false
+		for _, aud := range audiences(claims) {
+			for _, a := range p.AllowedAudiences {
+				if aud == a {
+					allowed = true
+				}
+			}
+		}
+		if !allowed {
+			return ErrTokenAudienceInvalid
+		}
+	}
+	return nil
+}
+
+func splitList(value string) []string {
+	var result []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			result = append(result, item)
+		}
+	}
+	return result
+}
+
+func loadClaimsPolicyFromEnv() (*ClaimsPolicy, error) {
+	skew, err := strconv.Atoi(util.GetDefaultEnv("JWT_CLOCK_SKEW", "30"))
+	if err != nil {
+		return nil, err
+	}
+	p := &ClaimsPolicy{
+		Issuer:           util.GetDefaultEnv("JWT_ISSUER", "gin-common"),
+		Audience:         util.GetDefaultEnv("JWT_AUDIENCE", "gin-common"),
+		AllowedAudiences: splitList(util.GetDefaultEnv("JWT_ALLOWED_AUDIENCES", "")),
+		ClockSkew:        time.Duration(skew) * time.Second,
+		RequiredClaims:   []string{"exp", "iat", "jti", "sub"},
+	}
+	if len(p.AllowedAudiences) == 0 {
+		p.AllowedAudiences = []string{p.Audience}
+	}
+	return p, nil
+}
+
+var (
+	defaultClaimsPolicy     *ClaimsPolicy
+	defaultClaimsPolicyErr  error
+	defaultClaimsPolicyOnce sync.Once
+)
+
+// 获取根据环境变量加载的声明校验策略
+func DefaultClaimsPolicy() (*ClaimsPolicy, error) {
+	defaultClaimsPolicyOnce.Do(func() {
+		defaultClaimsPolicy, defaultClaimsPolicyErr = loadClaimsPolicyFromEnv()
+	})
+	return defaultClaimsPolicy, defaultClaimsPolicyErr
+}
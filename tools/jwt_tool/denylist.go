@@ -0,0 +1,218 @@
+package jwt_tool
+
+import (
+	"context"
+	"encoding/json"
+	"strconv"
+	"sync"
+	"time"
+
+	"com.github.gin-common/tools/redis_tool"
+	"com.github.gin-common/util"
+	"github.com/go-redis/redis/v8"
+)
+
+// 无状态模式下的token注销列表
+// 注销的jti保存在redis有序集合中(score为过期时间)，并通过pub/sub同步到各实例的本地缓存
+// 同一频道还用于通知用户信息变更，供各实例清除本地缓存的用户
+
+const (
+	denylistKey     = "revokedTokens"
+	denylistChannel = "revokedTokens"
+	// 定期与redis全量同步，弥补pub/sub断线期间丢失的消息
+	denylistResyncInterval = time.Minute
+)
+
+// 是否启用无状态模式：仅校验签名及标准声明，不再查询redis中的access token
+func StatelessMode() bool {
+	stateless, _ := strconv.ParseBool(util.GetDefaultEnv("JWT_STATELESS", "false"))
+	return stateless
+}
+
+// 频道消息：jti不为空表示注销token，uid不为空表示用户信息变更
+type denylistMessage struct {
+	JTI    string `json:"jti,omitempty"`
+	Exp    int64  `json:"exp,omitempty"`
+	UserID uint   `json:"uid,omitempty"`
+}
+
+type Denylist struct {
+	rdb          *redis.Client
+	local        map[string]int64
+	mu           sync.RWMutex
+	pubsub       *redis.PubSub
+	done         chan struct{}
+	userHandlers []func(userID uint)
+}
+
+func NewDenylist(rdb *redis.Client) *Denylist {
+	return &Denylist{rdb: rdb, local: map[string]int64{}}
+}
+
+func (d *Denylist) add(jti string, exp int64) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.local[jti] = exp
+}
+
+// 注销jti，expiresAt之后token本身已过期，无需继续保留
+func (d *Denylist) Revoke(ctx context.Context, jti string, expiresAt time.Time) error {
+	exp := expiresAt.Unix()
+	d.add(jti, exp)
+	msg, err := json.Marshal(denylistMessage{JTI: jti, Exp: exp})
+	if err != nil {
+		return err
+	}
+	_, err = d.rdb.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.ZAdd(ctx, denylistKey, &redis.Z{Score: float64(exp), Member: jti})
+		pipe.ZRemRangeByScore(ctx, denylistKey, "-inf", strconv.FormatInt(time.Now().Unix(), 10))
+		pipe.Publish(ctx, denylistChannel, msg)
+		return nil
+	})
+	return err
+}
+
+// 注册用户信息变更的处理函数，本实例及其他实例发出的通知都会触发
+func (d *Denylist) OnUserChanged(handler func(userID uint)) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.userHandlers = append(d.userHandlers, handler)
+}
+
+func (d *Denylist) userChanged(userID uint) {
+	d.mu.RLock()
+	handlers := d.userHandlers
+	d.mu.RUnlock()
+	for _, handler := range handlers {
+		handler(userID)
+	}
+}
+
+// 通知所有实例用户信息已变更
+func (d *Denylist) NotifyUserChanged(ctx context.Context, userID uint) error {
+	d.userChanged(userID)
+	msg, err := json.Marshal(denylistMessage{UserID: userID})
+	if err != nil {
+		return err
+	}
+	return d.rdb.Publish(ctx, denylistChannel, msg).Err()
+}
+
+func (d *Denylist) Contains(jti string) bool {
+	d.mu.RLock()
+	exp, ok := d.local[jti]
+	d.mu.RUnlock()
+	if ok && exp < time.Now().Unix() {
+		d.mu.Lock()
+		delete(d.local, jti)
+		d.mu.Unlock()
+		return false
+	}
+	return ok
+}
+
+// 从redis全量加载未过期的jti
+func (d *Denylist) Load(ctx context.Context) error {
+	now := strconv.FormatInt(time.Now().Unix(), 10)
+	items, err := d.rdb.ZRangeByScoreWithScores(ctx, denylistKey, &redis.ZRangeBy{Min: now, Max: "+inf"}).Result()
+	if err != nil {
+		return err
+	}
+	local := make(map[string]int64, len(items))
+	for _, item := range items {
+		if jti, ok := item.Member.(string); ok {
+			local[jti] = int64(item.Score)
+		}
+	}
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.local = local
+	return nil
+}
+
+// 订阅注销消息并加载已有的注销列表
+func (d *Denylist) Subscribe(ctx context.Context) error {
+	pubsub := d.rdb.Subscribe(ctx, denylistChannel)
+	if _, err := pubsub.Receive(ctx); err != nil {
+		_ = pubsub.Close()
+		return err
+	}
+	if err := d.Load(ctx); err != nil {
+		_ = pubsub.Close()
+		return err
+	}
+	d.pubsub = pubsub
+	d.done = make(chan struct{})
+	go d.listen(pubsub.Channel(), d.done)
+	return nil
+}
+
+func (d *Denylist) listen(ch <-chan *redis.Message, done chan struct{}) {
+	ticker := time.NewTicker(denylistResyncInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case msg, ok := <-ch:
+			if !ok {
+				return
+			}
+			var message denylistMessage
+			if json.Unmarshal([]byte(msg.Payload), &message) != nil {
+				continue
+			}
+			if message.JTI != "" {
+				d.add(message.JTI, message.Exp)
+			}
+			if message.UserID != 0 {
+				d.userChanged(message.UserID)
+			}
+		case <-ticker.C:
+			_ = d.Load(context.Background())
+		case <-done:
+			return
+		}
+	}
+}
+
+func (d *Denylist) Close() error {
+	if d.pubsub == nil {
+		return nil
+	}
+	close(d.done)
+	err := d.pubsub.Close()
+	d.pubsub = nil
+	return err
+}
+
+var (
+	defaultDenylist     *Denylist
+	defaultDenylistOnce sync.Once
+)
+
+func DefaultDenylist() *Denylist {
+	defaultDenylistOnce.Do(func() {
+		defaultDenylist = NewDenylist(redis_tool.GetGinServerRdb())
+	})
+	return defaultDenylist
+}
+
+// 无状态模式下随应用启动订阅注销列表
+type DenylistComponent struct{}
+
+func (DenylistComponent) Name() string {
+	return "jwt-denylist"
+}
+
+func (DenylistComponent) Start() error {
+	if !StatelessMode() {
+		return nil
+	}
+	return DefaultDenylist().Subscribe(context.Background())
+}
+
+func (DenylistComponent) Stop(ctx context.Context) error {
+	if !StatelessMode() {
+		return nil
+	}
+	return DefaultDenylist().Close()
+}
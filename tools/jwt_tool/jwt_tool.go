@@ -2,8 +2,6 @@ package jwt_tool
 
 import (
 	"errors"
-	"fmt"
-	"os"
 	"time"
 
 	"github.com/dgrijalva/jwt-go"
@@ -15,15 +13,17 @@ type TokenInfo struct {
 }
 
 func CreateToken(claims jwt.MapClaims, expireSeconds int) (*TokenInfo, error) {
-	// 创建token
-	secret := os.Getenv("SECRET_KEY")
-	if secret == "" {
-		return nil, errors.New("invalid token secret")
+	// 创建token，写入标准声明并使用活动密钥签名
+	m, err := DefaultKeyManager()
+	if err != nil {
+		return nil, err
+	}
+	p, err := DefaultClaimsPolicy()
+	if err != nil {
+		return nil, err
 	}
-	tokenObj := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
-	expireDuration := time.Duration(expireSeconds) * time.Second
-	claims["exp"] = time.Now().Add(expireDuration).Unix()
-	token, err := tokenObj.SignedString([]byte(secret))
+	p.Stamp(claims, time.Duration(expireSeconds)*time.Second)
+	token, err := m.Sign(claims)
 	if err != nil {
 		return nil, err
 	}
@@ -34,34 +34,44 @@ func CreateToken(claims jwt.MapClaims, expireSeconds int) (*TokenInfo, error) {
 }
 
 func verifyToken(token string) (*jwt.Token, error) {
-	secret := os.Getenv("SECRET_KEY")
-	if secret == "" {
-		return nil, errors.New("invalid token secret")
-	}
-
-	tokenObj, err := jwt.Parse(token, func(token *jwt.Token) (interface{}, error) {
-		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
-			return nil, fmt.Errorf("unexpected signing method: %v", token.Header["alg"])
-		}
-		return []byte(secret), nil
-	})
+	m, err := DefaultKeyManager()
 	if err != nil {
 		return nil, err
 	}
+	// 声明由ClaimsPolicy校验(支持时钟偏差)
+	parser := &jwt.Parser{SkipClaimsValidation: true}
+	tokenObj, err := parser.Parse(token, m.Keyfunc)
+	if err != nil {
+		var ve *jwt.ValidationError
+		if errors.As(err, &ve) && ve.Errors&jwt.ValidationErrorMalformed != 0 {
+			return nil, ErrTokenMalformed
+		}
+		return nil, ErrTokenSignatureInvalid
+	}
 	return tokenObj, nil
 }
 
 func TokenClaims(token string) (jwt.MapClaims, error) {
 	// 获取token claims
+	p, err := DefaultClaimsPolicy()
+	if err != nil {
+		return nil, err
+	}
+	return ParseToken(token, p)
+}
+
+// 校验签名后使用指定的策略校验声明
+func ParseToken(token string, policy *ClaimsPolicy) (jwt.MapClaims, error) {
 	tokenObj, err := verifyToken(token)
 	if err != nil {
 		return nil, err
 	}
-	var claims jwt.MapClaims
-	var ok bool
-	claims, ok = tokenObj.Claims.(jwt.MapClaims)
-	if ok && tokenObj.Valid {
-		return claims, nil
+	claims, ok := tokenObj.Claims.(jwt.MapClaims)
+	if !ok || !tokenObj.Valid {
+		return nil, errors.New("get token claims failed")
+	}
+	if err = policy.Validate(claims, time.Now()); err != nil {
+		return nil, err
 	}
-	return nil, errors.New("get token claims failed")
+	return claims, nil
 }
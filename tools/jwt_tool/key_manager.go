@@ -0,0 +1,302 @@
+package jwt_tool
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+
+	"com.github.gin-common/util"
+	"github.com/dgrijalva/jwt-go"
+)
+
+// 签名密钥管理
+// 设置JWT_KEYS_DIR后从目录中加载*.pem密钥(文件名即kid)，使用JWT_ACTIVE_KID对应的私钥签名，
+// JWT_RETIRED_KIDS中的密钥不再用于校验；未设置JWT_KEYS_DIR时沿用SECRET_KEY进行HS256签名
+
+var (
+	ErrNoActiveKey   = errors.New("jwt: no active signing key")
+	ErrKeyNotFound   = errors.New("jwt: signing key not found")
+	ErrKeyRetired    = errors.New("jwt: signing key retired")
+	ErrKeyNotPrivate = errors.New("jwt: signing key has no private part")
+)
+
+type SigningKey struct {
+	ID         string
+	Method     jwt.SigningMethod
+	PrivateKey crypto.PrivateKey
+	PublicKey  crypto.PublicKey
+	Retired    bool
+}
+
+// 对称密钥不对外公布
+func (key *SigningKey) IsSymmetric() bool {
+	_, ok := key.Method.(*jwt.SigningMethodHMAC)
+	return ok
+}
+
+type KeyManager struct {
+	keys     map[string]*SigningKey
+	order    []string
+	activeID string
+	mu       sync.RWMutex
+}
+
+func NewKeyManager() *KeyManager {
+	return &KeyManager{keys: map[string]*SigningKey{}}
+}
+
+// 添加密钥，第一个含私钥的密钥默认为活动密钥
+func (m *KeyManager) AddKey(key *SigningKey) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if _, ok := m.keys[key.ID]; !ok {
+		m.order = append(m.order, key.ID)
+	}
+	m.keys[key.ID] = key
+	if m.activeID == "" && key.PrivateKey != nil && !key.Retired {
+		m.activeID = key.ID
+	}
+}
+
+// 加载目录下所有pem文件
+func (m *KeyManager) LoadDir(dir string) error {
+	files, err := filepath.Glob(filepath.Join(dir, "*.pem"))
+	if err != nil {
+		return err
+	}
+	sort.Strings(files)
+	for _, file := range files {
+		data, err := ioutil.ReadFile(file)
+		if err != nil {
+			return err
+		}
+		kid := strings.TrimSuffix(filepath.Base(file), filepath.Ext(file))
+		key, err := ParsePEMKey(kid, data)
+		if err != nil {
+			return fmt.Errorf("%s: %w", file, err)
+		}
+		m.AddKey(key)
+	}
+	return nil
+}
+
+func (m *KeyManager) SetActive(kid string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	key, ok := m.keys[kid]
+	if !ok {
+		return fmt.Errorf("%w: %s", ErrKeyNotFound, kid)
+	}
+	if key.Retired {
+		return fmt.Errorf("%w: %s", ErrKeyRetired, kid)
+	}
+	if key.PrivateKey == nil {
+		return fmt.Errorf("%w: %s", ErrKeyNotPrivate, kid)
+	}
+	m.activeID = kid
+	return nil
+}
+
+// 退役密钥，退役后该密钥签发的token不再有效
+func (m *KeyManager) Retire(kid string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	key, ok := m.keys[kid]
+	if !ok {
+		return fmt.Errorf("%w: %s", ErrKeyNotFound, kid)
+	}
+	key.Retired = true
+	if m.activeID == kid {
+		m.activeID = ""
+	}
+	return nil
+}
+
+func (m *KeyManager) Active() (*SigningKey, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	key, ok := m.keys[m.activeID]
+	if !ok {
+		return nil, ErrNoActiveKey
+	}
+	return key, nil
+}
+
+func (m *KeyManager) Key(kid string) (*SigningKey, bool) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	key, ok := m.keys[kid]
+	return key, ok
+}
+
+// 所有未退役的密钥
+func (m *KeyManager) Keys() []*SigningKey {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	keys := make([]*SigningKey, 0, len(m.order))
+	for _, kid := range m.order {
+		if key := m.keys[kid]; !key.Retired {
+			keys = append(keys, key)
+		}
+	}
+	return keys
+}
+
+// 使用活动密钥签名，并在header中写入kid
+func (m *KeyManager) Sign(claims jwt.Claims) (string, error) {
+	key, err := m.Active()
+	if err != nil {
+		return "", err
+	}
+	tokenObj := jwt.NewWithClaims(key.Method, claims)
+	if key.ID != "" {
+		tokenObj.Header["kid"] = key.ID
+	}
+	return tokenObj.SignedString(key.PrivateKey)
+}
+
+// 校验时根据kid查找未退役的密钥
+func (m *KeyManager) Keyfunc(token *jwt.Token) (interface{}, error) {
+	kid, _ := token.Header["kid"].(string)
+	key, ok := m.Key(kid)
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrKeyNotFound, kid)
+	}
+	if key.Retired {
+		return nil, fmt.Errorf("%w: %s", ErrKeyRetired, kid)
+	}
+	if token.Method.Alg() != key.Method.Alg() {
+		return nil, fmt.Errorf("unexpected signing method: %v", token.Header["alg"])
+	}
+	return key.PublicKey, nil
+}
+
+// 解析pem格式的私钥或公钥，并根据密钥类型确定签名算法
+func ParsePEMKey(kid string, data []byte) (*SigningKey, error) {
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, errors.New("invalid pem data")
+	}
+	var raw interface{}
+	var err error
+	switch block.Type {
+	case "RSA PRIVATE KEY":
+		raw, err = x509.ParsePKCS1PrivateKey(block.Bytes)
+	case "EC PRIVATE KEY":
+		raw, err = x509.ParseECPrivateKey(block.Bytes)
+	case "PRIVATE KEY":
+		raw, err = x509.ParsePKCS8PrivateKey(block.Bytes)
+	case "RSA PUBLIC KEY":
+		raw, err = x509.ParsePKCS1PublicKey(block.Bytes)
+	case "PUBLIC KEY":
+		raw, err = x509.ParsePKIXPublicKey(block.Bytes)
+	default:
+		return nil, fmt.Errorf("unsupported pem type: %s", block.Type)
+	}
+	if err != nil {
+		return nil, err
+	}
+	key := &SigningKey{ID: kid}
+	switch k := raw.(type) {
+	case *rsa.PrivateKey:
+		key.PrivateKey, key.PublicKey, key.Method = k, &k.PublicKey, jwt.SigningMethodRS256
+	case *rsa.PublicKey:
+		key.PublicKey, key.Method = k, jwt.SigningMethodRS256
+	case *ecdsa.PrivateKey:
+		key.PrivateKey, key.PublicKey = k, &k.PublicKey
+		key.Method, err = ecdsaMethod(k.Curve)
+	case *ecdsa.PublicKey:
+		key.PublicKey = k
+		key.Method, err = ecdsaMethod(k.Curve)
+	case ed25519.PrivateKey:
+		key.PrivateKey, key.PublicKey, key.Method = k, k.Public(), SigningMethodEdDSA
+	case ed25519.PublicKey:
+		key.PublicKey, key.Method = k, SigningMethodEdDSA
+	default:
+		return nil, fmt.Errorf("unsupported key type: %T", raw)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return key, nil
+}
+
+func ecdsaMethod(curve elliptic.Curve) (jwt.SigningMethod, error) {
+	switch curve {
+	case elliptic.P256():
+		return jwt.SigningMethodES256, nil
+	case elliptic.P384():
+		return jwt.SigningMethodES384, nil
+	case elliptic.P521():
+		return jwt.SigningMethodES512, nil
+	}
+	return nil, fmt.Errorf("unsupported curve: %s", curve.Params().Name)
+}
+
+// 使用SECRET_KEY的HS256密钥，不写入kid
+func NewHMACKey(secret string) *SigningKey {
+	return &SigningKey{
+		Method:     jwt.SigningMethodHS256,
+		PrivateKey: []byte(secret),
+		PublicKey:  []byte(secret),
+	}
+}
+
+func loadKeyManagerFromEnv() (*KeyManager, error) {
+	m := NewKeyManager()
+	dir := util.GetDefaultEnv("JWT_KEYS_DIR", "")
+	if dir == "" {
+		secret := os.Getenv("SECRET_KEY")
+		if secret == "" {
+			return nil, errors.New("invalid token secret")
+		}
+		m.AddKey(NewHMACKey(secret))
+		return m, nil
+	}
+	if err := m.LoadDir(dir); err != nil {
+		return nil, err
+	}
+	for _, kid := range strings.Split(util.GetDefaultEnv("JWT_RETIRED_KIDS", ""), ",") {
+		if kid = strings.TrimSpace(kid); kid == "" {
+			continue
+		}
+		if err := m.Retire(kid); err != nil {
+			return nil, err
+		}
+	}
+	if kid := util.GetDefaultEnv("JWT_ACTIVE_KID", ""); kid != "" {
+		if err := m.SetActive(kid); err != nil {
+			return nil, err
+		}
+	}
+	if _, err := m.Active(); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+var (
+	defaultKeyManager *KeyManager
+	defaultKeyErr     error
+	defaultKeyOnce    sync.Once
+)
+
+// 获取根据环境变量加载的密钥管理器
+func DefaultKeyManager() (*KeyManager, error) {
+	defaultKeyOnce.Do(func() {
+		defaultKeyManager, defaultKeyErr = loadKeyManagerFromEnv()
+	})
+	return defaultKeyManager, defaultKeyErr
+}
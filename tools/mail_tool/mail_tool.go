@@ -0,0 +1,180 @@
+package mail_tool
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"fmt"
+	"io/ioutil"
+	"mime"
+	"net"
+	"net/smtp"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"com.github.gin-common/util"
+	"github.com/google/uuid"
+)
+
+// 邮件发送，MAIL_DRIVER可选smtp、file(写入MAIL_FILE_DIR目录，开发环境使用)、memory(测试使用)
+
+type Message struct {
+	From    string
+	To      []string
+	Subject string
+	// 纯文本正文
+	Body string
+}
+
+type Mailer interface {
+	Send(ctx context.Context, message Message) error
+}
+
+// 生成RFC 5322格式的邮件内容
+func (message Message) Bytes() []byte {
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "From: %s\r\n", message.From)
+	fmt.Fprintf(&buf, "To: %s\r\n", strings.Join(message.To, ", "))
+	fmt.Fprintf(&buf, "Subject: %s\r\n", mime.BEncoding.Encode("UTF-8", message.Subject))
+	fmt.Fprintf(&buf, "Date: %s\r\n", time.Now().Format(time.RFC1123Z))
+	buf.WriteString("MIME-Version: 1.0\r\n")
+	buf.WriteString("Content-Type: text/plain; charset=UTF-8\r\n")
+	buf.WriteString("Content-Transfer-Encoding: base64\r\n\r\n")
+	body := base64.StdEncoding.EncodeToString([]byte(message.Body))
+	for len(body) > 76 {
+		buf.WriteString(body[:76] + "\r\n")
+		body = body[76:]
+	}
+	buf.WriteString(body + "\r\n")
+	return buf.Bytes()
+}
+
+type SMTPMailer struct {
+	Host     string
+	Port     string
+	Username string
+	Password string
+	From     string
+}
+
+func (mailer *SMTPMailer) Send(ctx context.Context, message Message) error {
+	if message.From == "" {
+		message.From = mailer.From
+	}
+	var auth smtp.Auth
+	if mailer.Username != "" {
+		auth = smtp.PlainAuth("", mailer.Username, mailer.Password, mailer.Host)
+	}
+	done := make(chan error, 1)
+	go func() {
+		done <- smtp.SendMail(net.JoinHostPort(mailer.Host, mailer.Port), auth, message.From, message.To, message.Bytes())
+	}()
+	select {
+	case err := <-done:
+		return err
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// 将邮件保存为.eml文件
+type FileMailer struct {
+	Dir  string
+	From string
+}
+
+func (mailer *FileMailer) Send(ctx context.Context, message Message) error {
+	if message.From == "" {
+		message.From = mailer.From
+	}
+	if err := os.MkdirAll(mailer.Dir, 0755); err != nil {
+		return err
+	}
+	name := fmt.Sprintf("%s-%s.eml", time.Now().Format("20060102150405"), uuid.New().String())
+	return ioutil.WriteFile(filepath.Join(mailer.Dir, name), message.Bytes(), 0644)
+}
+
+// 将邮件保存在内存中，供测试读取
+type MemoryMailer struct {
+	From     string
+	messages []Message
+	mu       sync.Mutex
+}
+
+func (mailer *MemoryMailer) Send(ctx context.Context, message Message) error {
+	if message.From == "" {
+		message.From = mailer.From
+	}
+	mailer.mu.Lock()
+	defer mailer.mu.Unlock()
+	mailer.messages = append(mailer.messages, message)
+	return nil
+}
+
+func (mailer *MemoryMailer) Messages() []Message {
+	mailer.mu.Lock()
+	defer mailer.mu.Unlock()
+	return append([]Message(nil), mailer.messages...)
+}
+
+// 最后一封发送给to的邮件
+func (mailer *MemoryMailer) Last(to string) (Message, bool) {
+	mailer.mu.Lock()
+	defer mailer.mu.Unlock()
+	for i := len(mailer.messages) - 1; i >= 0; i-- {
+		for _, addr := range mailer.messages[i].To {
+			if addr == to {
+				return mailer.messages[i], true
+			}
+		}
+	}
+	return Message{}, false
+}
+
+func (mailer *MemoryMailer) Reset() {
+	mailer.mu.Lock()
+	defer mailer.mu.Unlock()
+	mailer.messages = nil
+}
+
+var (
+	mailer     Mailer
+	mailerOnce sync.Once
+)
+
+func newMailerFromEnv() Mailer {
+	from := util.GetDefaultEnv("MAIL_FROM", "no-reply@localhost")
+	switch driver := util.GetDefaultEnv("MAIL_DRIVER", "file"); driver {
+	case "smtp":
+		return &SMTPMailer{
+			Host:     util.GetDefaultEnv("MAIL_SMTP_HOST", "127.0.0.1"),
+			Port:     util.GetDefaultEnv("MAIL_SMTP_PORT", "25"),
+			Username: util.GetDefaultEnv("MAIL_SMTP_USERNAME", ""),
+			Password: util.GetDefaultEnv("MAIL_SMTP_PASSWORD", ""),
+			From:     from,
+		}
+	case "memory":
+		return &MemoryMailer{From: from}
+	case "file":
+		return &FileMailer{Dir: util.GetDefaultEnv("MAIL_FILE_DIR", "mails"), From: from}
+	default:
+		panic(fmt.Sprintf("unknown MAIL_DRIVER: %s", driver))
+	}
+}
+
+// 获取根据环境变量创建的Mailer
+func GetMailer() Mailer {
+	mailerOnce.Do(func() {
+		mailer = newMailerFromEnv()
+	})
+	return mailer
+}
+
+// 替换默认Mailer(测试时使用MemoryMailer)
+func SetMailer(m Mailer) {
+	mailerOnce.Do(func() {})
+	mailer = m
+}
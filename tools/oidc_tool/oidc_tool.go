@@ -0,0 +1,347 @@
+package oidc_tool
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"com.github.gin-common/tools/jwt_tool"
+	"github.com/dgrijalva/jwt-go"
+)
+
+// OpenID Connect客户端: discovery、授权码+PKCE、ID token校验
+
+var (
+	ErrIssuerMismatch  = errors.New("oidc: issuer mismatch")
+	ErrIDTokenInvalid  = errors.New("oidc: id token invalid")
+	ErrNonceMismatch   = errors.New("oidc: nonce mismatch")
+	ErrTokenExchange   = errors.New("oidc: token exchange failed")
+	ErrMissingIDToken  = errors.New("oidc: token response has no id_token")
+	ErrUnknownProvider = errors.New("oidc: unknown provider")
+)
+
+// ID token允许的时钟偏差
+const clockSkew = time.Minute
+
+type Config struct {
+	// 提供方名称，用于路由及关联外部身份
+	Name         string
+	Issuer       string
+	ClientID     string
+	ClientSecret string
+	RedirectURL  string
+	Scopes       []string
+}
+
+// discovery文档 (/.well-known/openid-configuration)
+type Metadata struct {
+	Issuer                string `json:"issuer"`
+	AuthorizationEndpoint string `json:"authorization_endpoint"`
+	TokenEndpoint         string `json:"token_endpoint"`
+	UserinfoEndpoint      string `json:"userinfo_endpoint"`
+	JWKSURI               string `json:"jwks_uri"`
+}
+
+type TokenResponse struct {
+	AccessToken  string `json:"access_token"`
+	TokenType    string `json:"token_type"`
+	RefreshToken string `json:"refresh_token"`
+	ExpiresIn    int    `json:"expires_in"`
+	IDToken      string `json:"id_token"`
+}
+
+// ID token中的常用声明
+type IDTokenClaims struct {
+	Subject           string
+	Email             string
+	EmailVerified     bool
+	Name              string
+	PreferredUsername string
+	Nonce             string
+	Raw               jwt.MapClaims
+}
+
+type Client struct {
+	config     Config
+	httpClient *http.Client
+	metadata   *Metadata
+	keys       map[string]*jwt_tool.SigningKey
+	mu         sync.RWMutex
+}
+
+func NewClient(config Config, httpClient *http.Client) *Client {
+	if httpClient == nil {
+		httpClient = &http.Client{Timeout: 10 * time.Second}
+	}
+	if len(config.Scopes) == 0 {
+		config.Scopes = []string{"openid", "profile", "email"}
+	}
+	return &Client{config: config, httpClient: httpClient}
+}
+
+func (c *Client) Config() Config {
+	return c.config
+}
+
+func (c *Client) getJSON(ctx context.Context, endpoint string, v interface{}) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Accept", "application/json")
+	res, err := c.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return fmt.Errorf("oidc: GET %s: %s", endpoint, res.Status)
+	}
+	return json.NewDecoder(res.Body).Decode(v)
+}
+
+// 获取discovery文档，结果会被缓存
+func (c *Client) Discover(ctx context.Context) (*Metadata, error) {
+	c.mu.RLock()
+	metadata := c.metadata
+	c.mu.RUnlock()
+	if metadata != nil {
+		return metadata, nil
+	}
+	metadata = &Metadata{}
+	endpoint := strings.TrimSuffix(c.config.Issuer, "/") + "/.well-known/openid-configuration"
+	if err := c.getJSON(ctx, endpoint, metadata); err != nil {
+		return nil, err
+	}
+	if strings.TrimSuffix(metadata.Issuer, "/") != strings.TrimSuffix(c.config.Issuer, "/") {
+		return nil, fmt.Errorf("%w: %s", ErrIssuerMismatch, metadata.Issuer)
+	}
+	c.mu.Lock()
+	c.metadata = metadata
+	c.mu.Unlock()
+	return metadata, nil
+}
+
+func randomString(n int) (string, error) {
+	b := make([]byte, n)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+// 生成state/nonce等随机值
+func RandomState() (string, error) {
+	return randomString(24)
+}
+
+// 生成PKCE code verifier
+func NewCodeVerifier() (string, error) {
+	return randomString(32)
+}
+
+// PKCE S256 code challenge
+func CodeChallengeS256(verifier string) string {
+	sum := sha256.Sum256([]byte(verifier))
+	return base64.RawURLEncoding.EncodeToString(sum[:])
+}
+
+// 生成授权地址
+func (c *Client) AuthCodeURL(ctx context.Context, state string, nonce string, codeVerifier string) (string, error) {
+	metadata, err := c.Discover(ctx)
+	if err != nil {
+		return "", err
+	}
+	params := url.Values{}
+	params.Set("response_type", "code")
+	params.Set("client_id", c.config.ClientID)
+	params.Set("redirect_uri", c.config.RedirectURL)
+	params.Set("scope", strings.Join(c.config.Scopes, " "))
+	params.Set("state", state)
+	params.Set("nonce", nonce)
+	params.Set("code_challenge", CodeChallengeS256(codeVerifier))
+	params.Set("code_challenge_method", "S256")
+	sep := "?"
+	if strings.Contains(metadata.AuthorizationEndpoint, "?") {
+		sep = "&"
+	}
+	return metadata.AuthorizationEndpoint + sep + params.Encode(), nil
+}
+
+// 使用授权码换取token
+func (c *Client) Exchange(ctx context.Context, code string, codeVerifier string) (*TokenResponse, error) {
+	metadata, err := c.Discover(ctx)
+	if err != nil {
+		return nil, err
+	}
+	form := url.Values{}
+	form.Set("grant_type", "authorization_code")
+	form.Set("code", code)
+	form.Set("redirect_uri", c.config.RedirectURL)
+	form.Set("client_id", c.config.ClientID)
+	form.Set("code_verifier", codeVerifier)
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, metadata.TokenEndpoint, strings.NewReader(form.Encode()))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Accept", "application/json")
+	if c.config.ClientSecret != "" {
+		req.SetBasicAuth(url.QueryEscape(c.config.ClientID), url.QueryEscape(c.config.ClientSecret))
+	}
+	res, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+	body, err := ioutil.ReadAll(res.Body)
+	if err != nil {
+		return nil, err
+	}
+	if res.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("%w: %s %s", ErrTokenExchange, res.Status, string(body))
+	}
+	tokens := &TokenResponse{}
+	if err = json.Unmarshal(body, tokens); err != nil {
+		return nil, err
+	}
+	if tokens.IDToken == "" {
+		return nil, ErrMissingIDToken
+	}
+	return tokens, nil
+}
+
+// 拉取提供方的JWKS
+func (c *Client) refreshKeys(ctx context.Context) error {
+	metadata, err := c.Discover(ctx)
+	if err != nil {
+		return err
+	}
+	set := jwt_tool.JWKSet{}
+	if err = c.getJSON(ctx, metadata.JWKSURI, &set); err != nil {
+		return err
+	}
+	keys := make(map[string]*jwt_tool.SigningKey, len(set.Keys))
+	for _, jwk := range set.Keys {
+		if jwk.Use != "" && jwk.Use != "sig" {
+			continue
+		}
+		key, err := jwk.SigningKey()
+		if err != nil {
+			continue
+		}
+		keys[key.ID] = key
+	}
+	c.mu.Lock()
+	c.keys = keys
+	c.mu.Unlock()
+	return nil
+}
+
+// 根据kid查找密钥，未找到时重新拉取JWKS(提供方可能已轮换密钥)
+func (c *Client) key(ctx context.Context, kid string) (*jwt_tool.SigningKey, error) {
+	c.mu.RLock()
+	key, ok := c.keys[kid]
+	c.mu.RUnlock()
+	if ok {
+		return key, nil
+	}
+	if err := c.refreshKeys(ctx); err != nil {
+		return nil, err
+	}
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	if key, ok = c.keys[kid]; ok {
+		return key, nil
+	}
+	// 只有一个密钥且token未指定kid时使用该密钥
+	if kid == "" && len(c.keys) == 1 {
+		for _, key = range c.keys {
+			return key, nil
+		}
+	}
+	return nil, fmt.Errorf("%w: unknown kid %q", ErrIDTokenInvalid, kid)
+}
+
+func audienceContains(claims jwt.MapClaims, clientID string) (bool, int) {
+	switch aud := claims["aud"].(type) {
+	case string:
+		return aud == clientID, 1
+	case []interface{}:
+		for _, item := range aud {
+			if item == clientID {
+				return true, len(aud)
+			}
+		}
+		return false, len(aud)
+	}
+	return false, 0
+}
+
+// 校验ID token的签名及iss/aud/exp/iat/nonce
+func (c *Client) VerifyIDToken(ctx context.Context, rawIDToken string, nonce string) (*IDTokenClaims, error) {
+	metadata, err := c.Discover(ctx)
+	if err != nil {
+		return nil, err
+	}
+	parser := &jwt.Parser{SkipClaimsValidation: true}
+	token, err := parser.Parse(rawIDToken, func(token *jwt.Token) (interface{}, error) {
+		kid, _ := token.Header["kid"].(string)
+		key, err := c.key(ctx, kid)
+		if err != nil {
+			return nil, err
+		}
+		if token.Method.Alg() != key.Method.Alg() {
+			return nil, fmt.Errorf("unexpected signing method: %v", token.Header["alg"])
+		}
+		return key.PublicKey, nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrIDTokenInvalid, err)
+	}
+	claims, ok := token.Claims.(jwt.MapClaims)
+	if !ok {
+		return nil, ErrIDTokenInvalid
+	}
+	if iss, _ := claims["iss"].(string); iss != metadata.Issuer {
+		return nil, fmt.Errorf("%w: %s", ErrIssuerMismatch, iss)
+	}
+	contains, count := audienceContains(claims, c.config.ClientID)
+	if !contains {
+		return nil, fmt.Errorf("%w: audience mismatch", ErrIDTokenInvalid)
+	}
+	if azp, ok := claims["azp"].(string); (count > 1 || ok) && azp != c.config.ClientID {
+		return nil, fmt.Errorf("%w: authorized party mismatch", ErrIDTokenInvalid)
+	}
+	now := time.Now()
+	if !claims.VerifyExpiresAt(now.Add(-clockSkew).Unix(), true) {
+		return nil, fmt.Errorf("%w: expired", ErrIDTokenInvalid)
+	}
+	if !claims.VerifyIssuedAt(now.Add(clockSkew).Unix(), true) {
+		return nil, fmt.Errorf("%w: issued in the future", ErrIDTokenInvalid)
+	}
+	result := &IDTokenClaims{Raw: claims}
+	result.Subject, _ = claims["sub"].(string)
+	result.Email, _ = claims["email"].(string)
+	result.EmailVerified, _ = claims["email_verified"].(bool)
+	result.Name, _ = claims["name"].(string)
+	result.PreferredUsername, _ = claims["preferred_username"].(string)
+	result.Nonce, _ = claims["nonce"].(string)
+	if result.Subject == "" {
+		return nil, fmt.Errorf("%w: missing sub", ErrIDTokenInvalid)
+	}
+	if nonce != "" && result.Nonce != nonce {
+		return nil, ErrNonceMismatch
+	}
+	return result, nil
+}
@@ -0,0 +1,145 @@
+package oidc_tool_test
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"strings"
+	"testing"
+
+	"com.github.gin-common/tools/oidc_tool"
+	"com.github.gin-common/tools/oidc_tool/oidctest"
+)
+
+const redirectURL = "http://localhost/oidc/test/callback"
+
+func newServer(t *testing.T) *oidctest.Server {
+	t.Helper()
+	server, err := oidctest.NewServer("client", "secret")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(server.Close)
+	return server
+}
+
+// 完成授权流程，返回ID token
+func login(t *testing.T, server *oidctest.Server, client *oidc_tool.Client, nonce string) string {
+	t.Helper()
+	ctx := context.Background()
+	verifier, err := oidc_tool.NewCodeVerifier()
+	if err != nil {
+		t.Fatal(err)
+	}
+	authURL, err := client.AuthCodeURL(ctx, "state", nonce, verifier)
+	if err != nil {
+		t.Fatal(err)
+	}
+	code, state, err := server.Authorize(authURL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if state != "state" {
+		t.Fatalf("expected state to round trip, got %q", state)
+	}
+	tokens, err := client.Exchange(ctx, code, verifier)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return tokens.IDToken
+}
+
+func TestDiscover(t *testing.T) {
+	server := newServer(t)
+	client := oidc_tool.NewClient(server.Config("test", redirectURL), nil)
+	metadata, err := client.Discover(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if metadata.Issuer != server.Issuer() || metadata.TokenEndpoint != server.URL+"/token" || metadata.JWKSURI != server.URL+"/jwks" {
+		t.Errorf("unexpected metadata: %+v", metadata)
+	}
+
+	// 配置的issuer与discovery文档不一致
+	config := server.Config("test", redirectURL)
+	config.Issuer = strings.Replace(server.URL, "127.0.0.1", "localhost", 1)
+	_, err = oidc_tool.NewClient(config, nil).Discover(context.Background())
+	if !errors.Is(err, oidc_tool.ErrIssuerMismatch) {
+		t.Errorf("expected ErrIssuerMismatch, got %v", err)
+	}
+}
+
+func TestExchangeWithPKCE(t *testing.T) {
+	server := newServer(t)
+	client := oidc_tool.NewClient(server.Config("test", redirectURL), nil)
+	ctx := context.Background()
+
+	idToken := login(t, server, client, "nonce")
+	claims, err := client.VerifyIDToken(ctx, idToken, "nonce")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if claims.Subject != "oidctest-user" || claims.Email != "oidctest@example.com" || !claims.EmailVerified || claims.PreferredUsername != "oidctest" {
+		t.Errorf("unexpected claims: %+v", claims)
+	}
+
+	// 错误的code_verifier
+	verifier, _ := oidc_tool.NewCodeVerifier()
+	authURL, err := client.AuthCodeURL(ctx, "state", "nonce", verifier)
+	if err != nil {
+		t.Fatal(err)
+	}
+	code, _, err := server.Authorize(authURL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	otherVerifier, _ := oidc_tool.NewCodeVerifier()
+	if _, err = client.Exchange(ctx, code, otherVerifier); !errors.Is(err, oidc_tool.ErrTokenExchange) {
+		t.Errorf("expected ErrTokenExchange for wrong verifier, got %v", err)
+	}
+	// 授权码只能使用一次
+	if _, err = client.Exchange(ctx, code, verifier); !errors.Is(err, oidc_tool.ErrTokenExchange) {
+		t.Errorf("expected ErrTokenExchange for reused code, got %v", err)
+	}
+}
+
+func TestVerifyIDTokenRejects(t *testing.T) {
+	server := newServer(t)
+	client := oidc_tool.NewClient(server.Config("test", redirectURL), nil)
+	ctx := context.Background()
+	idToken := login(t, server, client, "nonce")
+
+	t.Run("signature", func(t *testing.T) {
+		// 修改payload后签名不再匹配
+		parts := strings.Split(idToken, ".")
+		payload, err := base64.RawURLEncoding.DecodeString(parts[1])
+		if err != nil {
+			t.Fatal(err)
+		}
+		payload = []byte(strings.Replace(string(payload), "oidctest-user", "attacker-user", 1))
+		parts[1] = base64.RawURLEncoding.EncodeToString(payload)
+		_, err = client.VerifyIDToken(ctx, strings.Join(parts, "."), "nonce")
+		if !errors.Is(err, oidc_tool.ErrIDTokenInvalid) {
+			t.Errorf("expected ErrIDTokenInvalid, got %v", err)
+		}
+	})
+	t.Run("nonce", func(t *testing.T) {
+		if _, err := client.VerifyIDToken(ctx, idToken, "other"); !errors.Is(err, oidc_tool.ErrNonceMismatch) {
+			t.Errorf("expected ErrNonceMismatch, got %v", err)
+		}
+	})
+	t.Run("audience", func(t *testing.T) {
+		// 签发给其他客户端的ID token
+		config := server.Config("test", redirectURL)
+		config.ClientID = "other-client"
+		other := oidc_tool.NewClient(config, nil)
+		if _, err := other.VerifyIDToken(ctx, idToken, "nonce"); !errors.Is(err, oidc_tool.ErrIDTokenInvalid) {
+			t.Errorf("expected ErrIDTokenInvalid, got %v", err)
+		}
+	})
+	t.Run("malformed", func(t *testing.T) {
+		if _, err := client.VerifyIDToken(ctx, "not-a-token", ""); !errors.Is(err, oidc_tool.ErrIDTokenInvalid) {
+			t.Errorf("expected ErrIDTokenInvalid, got %v", err)
+		}
+	})
+}
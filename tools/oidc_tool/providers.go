@@ -0,0 +1,71 @@
+package oidc_tool
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+
+	"com.github.gin-common/util"
+)
+
+// 外部身份提供方注册表
+// OIDC_PROVIDERS=corp 时读取 OIDC_CORP_ISSUER、OIDC_CORP_CLIENT_ID、OIDC_CORP_CLIENT_SECRET、
+// OIDC_CORP_REDIRECT_URL 及 OIDC_CORP_SCOPES(空格分隔)
+
+var (
+	providers     = map[string]*Client{}
+	providersMu   sync.RWMutex
+	providersOnce sync.Once
+)
+
+func envName(provider string, key string) string {
+	return fmt.Sprintf("OIDC_%s_%s", strings.ToUpper(strings.ReplaceAll(provider, "-", "_")), key)
+}
+
+func loadProvidersFromEnv() {
+	for _, name := range strings.Split(util.GetDefaultEnv("OIDC_PROVIDERS", ""), ",") {
+		if name = strings.TrimSpace(name); name == "" {
+			continue
+		}
+		RegisterProvider(NewClient(Config{
+			Name:         name,
+			Issuer:       util.GetDefaultEnv(envName(name, "ISSUER"), ""),
+			ClientID:     util.GetDefaultEnv(envName(name, "CLIENT_ID"), ""),
+			ClientSecret: util.GetDefaultEnv(envName(name, "CLIENT_SECRET"), ""),
+			RedirectURL:  util.GetDefaultEnv(envName(name, "REDIRECT_URL"), ""),
+			Scopes:       strings.Fields(util.GetDefaultEnv(envName(name, "SCOPES"), "")),
+		}, nil))
+	}
+}
+
+// 注册提供方，同名会被覆盖
+func RegisterProvider(client *Client) {
+	providersMu.Lock()
+	defer providersMu.Unlock()
+	providers[client.config.Name] = client
+}
+
+func GetProvider(name string) (*Client, error) {
+	providersOnce.Do(loadProvidersFromEnv)
+	providersMu.RLock()
+	defer providersMu.RUnlock()
+	client, ok := providers[name]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrUnknownProvider, name)
+	}
+	return client, nil
+}
+
+// 已配置的提供方名称
+func Providers() []string {
+	providersOnce.Do(loadProvidersFromEnv)
+	providersMu.RLock()
+	defer providersMu.RUnlock()
+	names := make([]string, 0, len(providers))
+	for name := range providers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
@@ -0,0 +1,319 @@
+package password_tool
+
+import (
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+
+	"com.github.gin-common/util"
+	"golang.org/x/crypto/argon2"
+	"golang.org/x/crypto/bcrypt"
+	"golang.org/x/crypto/scrypt"
+)
+
+// 密码哈希算法，通过哈希值前缀识别:
+// bcrypt: $2a$/$2b$/$2y$
+// argon2id: $argon2id$v=19$m=65536,t=3,p=2$<salt>$<hash>
+// scrypt: $scrypt$ln=15,r=8,p=1$<salt>$<hash>
+
+var ErrUnknownHash = errors.New("password: unknown hash format")
+
+type PasswordHasher interface {
+	// 算法名称
+	Name() string
+	// 是否为该算法生成的哈希值
+	Match(hash string) bool
+	Hash(password []byte) (string, error)
+	Verify(hash string, password []byte) (bool, error)
+	// 哈希参数与当前配置不一致时需要重新哈希
+	NeedsRehash(hash string) bool
+}
+
+const saltLength = 16
+
+// 解析出的盐及哈希值的最小长度，过短(如为空)时任意密码都可能校验通过
+const minKeyLength = 16
+
+var b64 = base64.RawStdEncoding
+
+func randomSalt() ([]byte, error) {
+	salt := make([]byte, saltLength)
+	if _, err := rand.Read(salt); err != nil {
+		return nil, err
+	}
+	return salt, nil
+}
+
+// 解析 $<name>$<params>$<salt>$<hash> 格式，params为k=v,k=v
+func parsePHC(hash string, name string) (params map[string]int, salt []byte, key []byte, err error) {
+	parts := strings.Split(hash, "$")
+	// argon2id含有版本段
+	if len(parts) == 6 && parts[2] == fmt.Sprintf("v=%d", argon2.Version) {
+		parts = append(parts[:2], parts[3:]...)
+	}
+	if len(parts) != 5 || parts[0] != "" || parts[1] != name {
+		return nil, nil, nil, ErrUnknownHash
+	}
+	params = map[string]int{}
+	for _, kv := range strings.Split(parts[2], ",") {
+		pair := strings.SplitN(kv, "=", 2)
+		if len(pair) != 2 {
+			return nil, nil, nil, ErrUnknownHash
+		}
+		if params[pair[0]], err = strconv.Atoi(pair[1]); err != nil {
+			return nil, nil, nil, ErrUnknownHash
+		}
+	}
+	if salt, err = b64.DecodeString(parts[3]); err != nil {
+		return nil, nil, nil, ErrUnknownHash
+	}
+	if key, err = b64.DecodeString(parts[4]); err != nil {
+		return nil, nil, nil, ErrUnknownHash
+	}
+	if len(salt) < minKeyLength || len(key) < minKeyLength {
+		return nil, nil, nil, ErrUnknownHash
+	}
+	return params, salt, key, nil
+}
+
+type BcryptHasher struct {
+	Cost int
+}
+
+func (h *BcryptHasher) Name() string {
+	return "bcrypt"
+}
+
+func (h *BcryptHasher) Match(hash string) bool {
+	return strings.HasPrefix(hash, "$2a$") || strings.HasPrefix(hash, "$2b$") || strings.HasPrefix(hash, "$2y$")
+}
+
+func (h *BcryptHasher) Hash(password []byte) (string, error) {
+	hash, err := bcrypt.GenerateFromPassword(password, h.Cost)
+	if err != nil {
+		return "", err
+	}
+	return string(hash), nil
+}
+
+func (h *BcryptHasher) Verify(hash string, password []byte) (bool, error) {
+	err := bcrypt.CompareHashAndPassword([]byte(hash), password)
+	if err == bcrypt.ErrMismatchedHashAndPassword {
+		return false, nil
+	}
+	return err == nil, err
+}
+
+func (h *BcryptHasher) NeedsRehash(hash string) bool {
+	cost, err := bcrypt.Cost([]byte(hash))
+	return err != nil || cost != h.Cost
+}
+
+type Argon2idHasher struct {
+	// 内存(KiB)
+	Memory  uint32
+	Time    uint32
+	Threads uint8
+	KeyLen  uint32
+}
+
+func (h *Argon2idHasher) Name() string {
+	return "argon2id"
+}
+
+func (h *Argon2idHasher) Match(hash string) bool {
+	return strings.HasPrefix(hash, "$argon2id$")
+}
+
+func (h *Argon2idHasher) Hash(password []byte) (string, error) {
+	salt, err := randomSalt()
+	if err != nil {
+		return "", err
+	}
+	key := argon2.IDKey(password, salt, h.Time, h.Memory, h.Threads, h.KeyLen)
+	return fmt.Sprintf("$argon2id$v=%d$m=%d,t=%d,p=%d$%s$%s", argon2.Version, h.Memory, h.Time, h.Threads,
+		b64.EncodeToString(salt), b64.EncodeToString(key)), nil
+}
+
+func (h *Argon2idHasher) Verify(hash string, password []byte) (bool, error) {
+	params, salt, key, err := parsePHC(hash, h.Name())
+	if err != nil {
+		return false, err
+	}
+	if params["m"] <= 0 || params["t"] <= 0 || params["p"] <= 0 || params["p"] > 255 {
+		return false, ErrUnknownHash
+	}
+	actual := argon2.IDKey(password, salt, uint32(params["t"]), uint32(params["m"]), uint8(params["p"]), uint32(len(key)))
+	return subtle.ConstantTimeCompare(actual, key) == 1, nil
+}
+
+func (h *Argon2idHasher) NeedsRehash(hash string) bool {
+	params, _, key, err := parsePHC(hash, h.Name())
+	return err != nil || params["m"] != int(h.Memory) || params["t"] != int(h.Time) ||
+		params["p"] != int(h.Threads) || len(key) != int(h.KeyLen)
+}
+
+type ScryptHasher struct {
+	// N = 2^LogN
+	LogN   int
+	R      int
+	P      int
+	KeyLen int
+}
+
+func (h *ScryptHasher) Name() string {
+	return "scrypt"
+}
+
+func (h *ScryptHasher) Match(hash string) bool {
+	return strings.HasPrefix(hash, "$scrypt$")
+}
+
+func (h *ScryptHasher) Hash(password []byte) (string, error) {
+	salt, err := randomSalt()
+	if err != nil {
+		return "", err
+	}
+	key, err := scrypt.Key(password, salt, 1<<uint(h.LogN), h.R, h.P, h.KeyLen)
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("$scrypt$ln=%d,r=%d,p=%d$%s$%s", h.LogN, h.R, h.P,
+		b64.EncodeToString(salt), b64.EncodeToString(key)), nil
+}
+
+func (h *ScryptHasher) Verify(hash string, password []byte) (bool, error) {
+	params, salt, key, err := parsePHC(hash, h.Name())
+	if err != nil {
+		return false, err
+	}
+	if params["ln"] <= 0 || params["ln"] >= 32 {
+		return false, ErrUnknownHash
+	}
+	actual, err := scrypt.Key(password, salt, 1<<uint(params["ln"]), params["r"], params["p"], len(key))
+	if err != nil {
+		return false, err
+	}
+	return subtle.ConstantTimeCompare(actual, key) == 1, nil
+}
+
+func (h *ScryptHasher) NeedsRehash(hash string) bool {
+	params, _, key, err := parsePHC(hash, h.Name())
+	return err != nil || params["ln"] != h.LogN || params["r"] != h.R || params["p"] != h.P || len(key) != h.KeyLen
+}
+
+// 按配置的首选算法哈希，按哈希值前缀选择算法校验
+type HasherRegistry struct {
+	preferred PasswordHasher
+	hashers   []PasswordHasher
+}
+
+func NewHasherRegistry(preferred PasswordHasher, others ...PasswordHasher) *HasherRegistry {
+	return &HasherRegistry{preferred: preferred, hashers: append([]PasswordHasher{preferred}, others...)}
+}
+
+func (r *HasherRegistry) Preferred() PasswordHasher {
+	return r.preferred
+}
+
+func (r *HasherRegistry) Identify(hash string) (PasswordHasher, error) {
+	for _, h := range r.hashers {
+		if h.Match(hash) {
+			return h, nil
+		}
+	}
+	return nil, ErrUnknownHash
+}
+
+func (r *HasherRegistry) Hash(password string) (string, error) {
+	return r.preferred.Hash([]byte(password))
+}
+
+func (r *HasherRegistry) Verify(hash string, password string) bool {
+	h, err := r.Identify(hash)
+	if err != nil {
+		return false
+	}
+	ok, err := h.Verify(hash, []byte(password))
+	return err == nil && ok
+}
+
+// 哈希算法不是首选算法或参数已变化
+func (r *HasherRegistry) NeedsRehash(hash string) bool {
+	h, err := r.Identify(hash)
+	if err != nil {
+		return true
+	}
+	return h.Name() != r.preferred.Name() || h.NeedsRehash(hash)
+}
+
+func envInt(key string, defaultValue int) (int, error) {
+	return strconv.Atoi(util.GetDefaultEnv(key, strconv.Itoa(defaultValue)))
+}
+
+func loadHashersFromEnv() (*HasherRegistry, error) {
+	bcryptCost, err := envInt("BCRYPT_COST", bcrypt.DefaultCost)
+	if err != nil {
+		return nil, err
+	}
+	argonMemory, err := envInt("ARGON2_MEMORY", 64*1024)
+	if err != nil {
+		return nil, err
+	}
+	argonTime, err := envInt("ARGON2_TIME", 3)
+	if err != nil {
+		return nil, err
+	}
+	argonThreads, err := envInt("ARGON2_THREADS", 2)
+	if err != nil {
+		return nil, err
+	}
+	scryptLogN, err := envInt("SCRYPT_LOG_N", 15)
+	if err != nil {
+		return nil, err
+	}
+	scryptR, err := envInt("SCRYPT_R", 8)
+	if err != nil {
+		return nil, err
+	}
+	scryptP, err := envInt("SCRYPT_P", 1)
+	if err != nil {
+		return nil, err
+	}
+	hashers := map[string]PasswordHasher{
+		"bcrypt":   &BcryptHasher{Cost: bcryptCost},
+		"argon2id": &Argon2idHasher{Memory: uint32(argonMemory), Time: uint32(argonTime), Threads: uint8(argonThreads), KeyLen: 32},
+		"scrypt":   &ScryptHasher{LogN: scryptLogN, R: scryptR, P: scryptP, KeyLen: 32},
+	}
+	name := util.GetDefaultEnv("PASSWORD_HASHER", "bcrypt")
+	preferred, ok := hashers[name]
+	if !ok {
+		return nil, fmt.Errorf("unknown PASSWORD_HASHER: %s", name)
+	}
+	var others []PasswordHasher
+	for _, n := range []string{"bcrypt", "argon2id", "scrypt"} {
+		if n != name {
+			others = append(others, hashers[n])
+		}
+	}
+	return NewHasherRegistry(preferred, others...), nil
+}
+
+var (
+	defaultHashers     *HasherRegistry
+	defaultHashersErr  error
+	defaultHashersOnce sync.Once
+)
+
+// 获取根据环境变量配置的哈希算法
+func DefaultHashers() (*HasherRegistry, error) {
+	defaultHashersOnce.Do(func() {
+		defaultHashers, defaultHashersErr = loadHashersFromEnv()
+	})
+	return defaultHashers, defaultHashersErr
+}
@@ -0,0 +1,212 @@
+package password_tool
+
+import (
+	"bufio"
+	"crypto/rand"
+	"fmt"
+	"math/big"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+	"unicode"
+	"unicode/utf8"
+
+	"com.github.gin-common/util"
+)
+
+// 密码策略：最小长度、字符种类、常见密码黑名单、与用户名相似度、历史密码数量及有效期
+
+// 内置的常见弱密码，PASSWORD_BLOCKLIST_FILE中的密码会追加到其中
+var commonPasswords = []string{
+	"password", "12345678", "123456789", "1234567890", "qwerty123", "11111111",
+	"iloveyou", "admin123", "password1", "abc12345", "qwertyuiop", "1q2w3e4r",
+	"88888888", "00000000", "a1234567", "woaini1314",
+}
+
+type Policy struct {
+	MinLength int
+	MaxLength int
+	// 至少包含的字符种类数(小写字母、大写字母、数字、符号)
+	MinClasses int
+	// 不能包含用户名(或其倒序)
+	DisallowUsername bool
+	// 不允许与最近N个密码相同
+	HistorySize int
+	// 密码有效期，为0时不过期
+	MaxAge    time.Duration
+	blocklist map[string]struct{}
+}
+
+func NewPolicy() *Policy {
+	p := &Policy{MinLength: 8, MaxLength: 128, MinClasses: 2, DisallowUsername: true, blocklist: map[string]struct{}{}}
+	p.Block(commonPasswords...)
+	return p
+}
+
+// 添加黑名单密码(不区分大小写)
+func (p *Policy) Block(passwords ...string) {
+	for _, password := range passwords {
+		if password = strings.TrimSpace(password); password != "" {
+			p.blocklist[strings.ToLower(password)] = struct{}{}
+		}
+	}
+}
+
+// 从文件加载黑名单，每行一个密码，#开头为注释
+func (p *Policy) LoadBlocklist(path string) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		if line := scanner.Text(); !strings.HasPrefix(line, "#") {
+			p.Block(line)
+		}
+	}
+	return scanner.Err()
+}
+
+func charClasses(password string) int {
+	var lower, upper, digit, symbol int
+	for _, r := range password {
+		switch {
+		case unicode.IsLower(r):
+			lower = 1
+		case unicode.IsUpper(r):
+			upper = 1
+		case unicode.IsDigit(r):
+			digit = 1
+		default:
+			symbol = 1
+		}
+	}
+	return lower + upper + digit + symbol
+}
+
+func reverse(s string) string {
+	runes := []rune(s)
+	for i, j := 0, len(runes)-1; i < j; i, j = i+1, j-1 {
+		runes[i], runes[j] = runes[j], runes[i]
+	}
+	return string(runes)
+}
+
+// 校验密码，返回不满足的规则说明
+func (p *Policy) Validate(password string, username string) []string {
+	var violations []string
+	length := utf8.RuneCountInString(password)
+	if length < p.MinLength {
+		violations = append(violations, fmt.Sprintf("密码长度不能少于%d位", p.MinLength))
+	}
+	if p.MaxLength > 0 && length > p.MaxLength {
+		violations = append(violations, fmt.Sprintf("密码长度不能超过%d位", p.MaxLength))
+	}
+	if charClasses(password) < p.MinClasses {
+		violations = append(violations, fmt.Sprintf("密码需包含小写字母、大写字母、数字、符号中的至少%d种", p.MinClasses))
+	}
+	lower := strings.ToLower(password)
+	if _, ok := p.blocklist[lower]; ok {
+		violations = append(violations, "密码过于常见")
+	}
+	if p.DisallowUsername && utf8.RuneCountInString(username) >= 3 {
+		name := strings.ToLower(username)
+		if strings.Contains(lower, name) || strings.Contains(lower, reverse(name)) || strings.Contains(name, lower) {
+			violations = append(violations, "密码不能与用户名相似")
+		}
+	}
+	return violations
+}
+
+// 上次修改时间超过有效期时密码过期
+func (p *Policy) Expired(changedAt time.Time, now time.Time) bool {
+	return p.MaxAge > 0 && now.Sub(changedAt) > p.MaxAge
+}
+
+const (
+	lowerChars  = "abcdefghijkmnopqrstuvwxyz"
+	upperChars  = "ABCDEFGHJKLMNPQRSTUVWXYZ"
+	digitChars  = "23456789"
+	symbolChars = "!@#$%^&*-_=+"
+)
+
+func randomChar(chars string) (byte, error) {
+	n, err := rand.Int(rand.Reader, big.NewInt(int64(len(chars))))
+	if err != nil {
+		return 0, err
+	}
+	return chars[n.Int64()], nil
+}
+
+// 生成包含全部字符种类的随机密码
+func Generate(length int) (string, error) {
+	classes := []string{lowerChars, upperChars, digitChars, symbolChars}
+	if length < len(classes) {
+		length = len(classes)
+	}
+	all := strings.Join(classes, "")
+	password := make([]byte, length)
+	for i := range password {
+		chars := all
+		if i < len(classes) {
+			chars = classes[i]
+		}
+		c, err := randomChar(chars)
+		if err != nil {
+			return "", err
+		}
+		password[i] = c
+	}
+	// 打乱顺序
+	for i := len(password) - 1; i > 0; i-- {
+		n, err := rand.Int(rand.Reader, big.NewInt(int64(i+1)))
+		if err != nil {
+			return "", err
+		}
+		j := n.Int64()
+		password[i], password[j] = password[j], password[i]
+	}
+	return string(password), nil
+}
+
+func loadPolicyFromEnv() (*Policy, error) {
+	p := NewPolicy()
+	var err error
+	if p.MinLength, err = strconv.Atoi(util.GetDefaultEnv("PASSWORD_MIN_LENGTH", "8")); err != nil {
+		return nil, err
+	}
+	if p.MinClasses, err = strconv.Atoi(util.GetDefaultEnv("PASSWORD_MIN_CLASSES", "2")); err != nil {
+		return nil, err
+	}
+	if p.HistorySize, err = strconv.Atoi(util.GetDefaultEnv("PASSWORD_HISTORY", "5")); err != nil {
+		return nil, err
+	}
+	maxAgeDays, err := strconv.Atoi(util.GetDefaultEnv("PASSWORD_MAX_AGE_DAYS", "0"))
+	if err != nil {
+		return nil, err
+	}
+	p.MaxAge = time.Duration(maxAgeDays) * 24 * time.Hour
+	if path := util.GetDefaultEnv("PASSWORD_BLOCKLIST_FILE", ""); path != "" {
+		if err = p.LoadBlocklist(path); err != nil {
+			return nil, err
+		}
+	}
+	return p, nil
+}
+
+var (
+	defaultPolicy     *Policy
+	defaultPolicyErr  error
+	defaultPolicyOnce sync.Once
+)
+
+// 获取根据环境变量加载的密码策略
+func DefaultPolicy() (*Policy, error) {
+	defaultPolicyOnce.Do(func() {
+		defaultPolicy, defaultPolicyErr = loadPolicyFromEnv()
+	})
+	return defaultPolicy, defaultPolicyErr
+}
@@ -1,6 +1,10 @@
 package util
 
 import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
 	"fmt"
 	"io"
 	"os"
@@ -40,6 +44,21 @@ func CompareHash(hashedStr string, plainStr []byte) bool {
 	return true
 }
 
+// 生成n字节的随机字符串(URL安全的base64编码)
+func RandomToken(n int) (string, error) {
+	b := make([]byte, n)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+// 计算sha256摘要(十六进制)
+func SHA256Hex(s string) string {
+	sum := sha256.Sum256([]byte(s))
+	return hex.EncodeToString(sum[:])
+}
+
 func PanicError(err error) {
 	if err != nil {
 		panic(err)
@@ -6,14 +6,20 @@ import (
 	"time"
 
 	"com.github.gin-common/common/controllers"
+	"com.github.gin-common/common/loggers/gin_logger"
+	"go.uber.org/zap"
 
+	"com.github.gin-common/tools/mail_tool"
 	"com.github.gin-common/tools/redis_tool"
 
+	"github.com/gin-gonic/gin"
 	"github.com/go-redis/redis/v8"
 
 	"com.github.gin-common/app/middleware"
 
+	adminController "com.github.gin-common/app/controller/admin"
 	authController "com.github.gin-common/app/controller/auth"
+	oauthController "com.github.gin-common/app/controller/oauth"
 	userController "com.github.gin-common/app/controller/user"
 
 	"com.github.gin-common/app/service/impl"
@@ -31,13 +37,27 @@ import (
 type gormSessionTimeout time.Duration
 type timeoutGormContext context.Context
 
+// gorm会话的父context，请求中为携带request_scope.Scope的请求context
+type requestContext context.Context
+
 func provideTimeoutGormSession(context timeoutGormContext) *gorm.DB {
 	session := db_tool.GetDB().Session(&gorm.Session{Context: context, WithConditions: false})
 	return session
 }
 
-func provideTimeoutGormContext(timeout gormSessionTimeout) timeoutGormContext {
-	timeoutContext, _ := context.WithTimeout(context.Background(), time.Duration(timeout))
+func provideRequestContext(ginContext *gin.Context) requestContext {
+	return ginContext.Request.Context()
+}
+
+// 定时任务等请求之外的组件使用
+func provideBackgroundContext() requestContext {
+	return context.Background()
+}
+
+func provideTimeoutGormContext(parent requestContext, timeout gormSessionTimeout) timeoutGormContext {
+	timeoutContext, cancel := context.WithTimeout(parent, time.Duration(timeout))
+	// 超时后释放context资源
+	time.AfterFunc(time.Duration(timeout), cancel)
 	return timeoutContext
 }
 
@@ -60,9 +80,17 @@ func provideCreateUserForm() *form.CreateUserForm {
 	return &form.CreateUserForm{}
 }
 
-func provideUserService(session *gorm.DB) *impl.UserServiceImpl {
+func provideLogger() zap.Logger {
+	return *gin_logger.Log
+}
+
+func provideMailer() mail_tool.Mailer {
+	return mail_tool.GetMailer()
+}
+
+func provideUserService(session *gorm.DB, rdb *redis.Client, ctx context.Context, logger zap.Logger, mailer mail_tool.Mailer) *impl.UserServiceImpl {
 	serviceImpl := &impl.UserServiceImpl{}
-	serviceImpl.Init(session)
+	serviceImpl.Init(session, rdb, ctx, logger, mailer)
 	return serviceImpl
 }
 
@@ -70,9 +98,9 @@ func provideUpdateUserForm() *form.UpdateUserForm {
 	return &form.UpdateUserForm{}
 }
 
-func provideEditUserController(updateUserForm *form.UpdateUserForm, userService service.UserService) controllers.Controller {
+func provideEditUserController(updateUserForm *form.UpdateUserForm, userService service.UserService, roleService service.RoleService) controllers.Controller {
 	controller := &userController.EditUserController{}
-	controller.Init(updateUserForm, userService)
+	controller.Init(updateUserForm, userService, roleService)
 	return controller
 }
 
@@ -100,9 +128,31 @@ func provideGetUserInfoController(userService service.UserService) controllers.C
 	return controller
 }
 
-func provideChangePasswordController(changePassForm *form.ChangePassForm, userService service.UserService) controllers.Controller {
+func provideUserListForm() *form.UserListForm {
+	return &form.UserListForm{}
+}
+
+func provideUserListController(userListForm *form.UserListForm, userService service.UserService) controllers.Controller {
+	controller := &userController.UserListController{}
+	controller.Init(userListForm, userService)
+	return controller
+}
+
+func provideRestoreUserController(userService service.UserService) controllers.Controller {
+	controller := &userController.RestoreUserController{}
+	controller.Init(userService)
+	return controller
+}
+
+func provideUserTrashController(userListForm *form.UserListForm, userService service.UserService) controllers.Controller {
+	controller := &userController.UserTrashController{}
+	controller.Init(userListForm, userService)
+	return controller
+}
+
+func provideChangePasswordController(changePassForm *form.ChangePassForm, userService service.UserService, roleService service.RoleService) controllers.Controller {
 	controller := &userController.ChangePasswordController{}
-	controller.Init(userService, changePassForm)
+	controller.Init(userService, changePassForm, roleService)
 	return controller
 }
 
@@ -118,9 +168,9 @@ func provideRedisRdb() *redis.Client {
 	return redis_tool.GetGinServerRdb()
 }
 
-func provideAuthMiddleware(ctx context.Context, rdb *redis.Client, userService service.UserService) controllers.MiddleWare {
+func provideAuthMiddleware(ctx context.Context, rdb *redis.Client, userService service.UserService, apiKeyService service.ApiKeyService) controllers.MiddleWare {
 	mid := &middleware.AuthMiddleware{}
-	mid.Init(rdb, userService, ctx)
+	mid.Init(rdb, userService, apiKeyService, ctx)
 	return mid
 }
 
@@ -128,13 +178,17 @@ func provideDeactivatedAbortMiddleware() controllers.MiddleWare {
 	return &middleware.DeactivatedAbortMiddleware{}
 }
 
+func provideApiKeyDeniedMiddleware() controllers.MiddleWare {
+	return &middleware.ApiKeyDeniedMiddleware{}
+}
+
 func provideLoginForm() *form.LoginForm {
 	return &form.LoginForm{}
 }
 
-func provideAuthService(ctx context.Context, rdb *redis.Client, userService service.UserService) *impl.AuthServiceImpl {
+func provideAuthService(ctx context.Context, rdb *redis.Client, userService service.UserService, mfaService service.MFAService, loginGuardService service.LoginGuardService, auditService service.AuditService) *impl.AuthServiceImpl {
 	serviceImpl := &impl.AuthServiceImpl{}
-	serviceImpl.Init(ctx, rdb, userService)
+	serviceImpl.Init(ctx, rdb, userService, mfaService, loginGuardService, auditService)
 	return serviceImpl
 }
 
@@ -144,12 +198,390 @@ func provideLoginController(loginForm *form.LoginForm, authService service.AuthS
 	return controller
 }
 
+func provideRefreshTokenForm() *form.RefreshTokenForm {
+	return &form.RefreshTokenForm{}
+}
+
+func provideRefreshTokenController(refreshTokenForm *form.RefreshTokenForm, authService service.AuthService) controllers.Controller {
+	controller := &authController.RefreshTokenController{}
+	controller.Init(refreshTokenForm, authService)
+	return controller
+}
+
 func provideLogoutController(authService service.AuthService) controllers.Controller {
 	controller := &authController.LogoutController{}
 	controller.Init(authService)
 	return controller
 }
 
+func provideSessionListController(authService service.AuthService) controllers.Controller {
+	controller := &authController.SessionListController{}
+	controller.Init(authService)
+	return controller
+}
+
+func provideRevokeSessionController(authService service.AuthService) controllers.Controller {
+	controller := &authController.RevokeSessionController{}
+	controller.Init(authService)
+	return controller
+}
+
+func provideLogoutAllController(authService service.AuthService) controllers.Controller {
+	controller := &authController.LogoutAllController{}
+	controller.Init(authService)
+	return controller
+}
+
 func provideCurrentUserController() controllers.Controller {
 	return &authController.CurrentUserController{}
 }
+
+func provideWorkflowListController() controllers.Controller {
+	return &adminController.WorkflowListController{}
+}
+
+func provideWorkflowGraphController() controllers.Controller {
+	return &adminController.WorkflowGraphController{}
+}
+
+func provideWorkflowRunsController() controllers.Controller {
+	return &adminController.WorkflowRunsController{}
+}
+
+func provideRoleService(session *gorm.DB, rdb *redis.Client, ctx context.Context, logger zap.Logger) *impl.RoleServiceImpl {
+	serviceImpl := &impl.RoleServiceImpl{}
+	serviceImpl.Init(session, rdb, ctx, logger)
+	return serviceImpl
+}
+
+func providePermissionMiddleware(roleService service.RoleService) controllers.MiddleWare {
+	mid := &middleware.PermissionMiddleware{}
+	mid.Init(roleService)
+	return mid
+}
+
+func provideCreateRoleForm() *form.CreateRoleForm {
+	return &form.CreateRoleForm{}
+}
+
+func provideUpdateRoleForm() *form.UpdateRoleForm {
+	return &form.UpdateRoleForm{}
+}
+
+func provideAssignRolesForm() *form.AssignRolesForm {
+	return &form.AssignRolesForm{}
+}
+
+func provideRoleListController(roleService service.RoleService) controllers.Controller {
+	controller := &adminController.RoleListController{}
+	controller.Init(roleService)
+	return controller
+}
+
+func provideCreateRoleController(createRoleForm *form.CreateRoleForm, roleService service.RoleService) controllers.Controller {
+	controller := &adminController.CreateRoleController{}
+	controller.Init(createRoleForm, roleService)
+	return controller
+}
+
+func provideUpdateRoleController(updateRoleForm *form.UpdateRoleForm, roleService service.RoleService) controllers.Controller {
+	controller := &adminController.UpdateRoleController{}
+	controller.Init(updateRoleForm, roleService)
+	return controller
+}
+
+func provideDeleteRoleController(roleService service.RoleService) controllers.Controller {
+	controller := &adminController.DeleteRoleController{}
+	controller.Init(roleService)
+	return controller
+}
+
+func providePermissionListController(roleService service.RoleService) controllers.Controller {
+	controller := &adminController.PermissionListController{}
+	controller.Init(roleService)
+	return controller
+}
+
+func provideUserRolesController(roleService service.RoleService) controllers.Controller {
+	controller := &adminController.UserRolesController{}
+	controller.Init(roleService)
+	return controller
+}
+
+func provideAssignUserRolesController(assignRolesForm *form.AssignRolesForm, roleService service.RoleService, userService service.UserService) controllers.Controller {
+	controller := &adminController.AssignUserRolesController{}
+	controller.Init(assignRolesForm, roleService, userService)
+	return controller
+}
+
+func provideUnassignUserRoleController(roleService service.RoleService) controllers.Controller {
+	controller := &adminController.UnassignUserRoleController{}
+	controller.Init(roleService)
+	return controller
+}
+
+func provideJWKSController() controllers.Controller {
+	return &authController.JWKSController{}
+}
+
+func provideApiKeyService(session *gorm.DB, logger zap.Logger) *impl.ApiKeyServiceImpl {
+	serviceImpl := &impl.ApiKeyServiceImpl{}
+	serviceImpl.Init(session, logger)
+	return serviceImpl
+}
+
+func provideCreateApiKeyForm() *form.CreateApiKeyForm {
+	return &form.CreateApiKeyForm{}
+}
+
+func provideCreateApiKeyController(createApiKeyForm *form.CreateApiKeyForm, apiKeyService service.ApiKeyService) controllers.Controller {
+	controller := &authController.CreateApiKeyController{}
+	controller.Init(createApiKeyForm, apiKeyService)
+	return controller
+}
+
+func provideApiKeyListController(apiKeyService service.ApiKeyService) controllers.Controller {
+	controller := &authController.ApiKeyListController{}
+	controller.Init(apiKeyService)
+	return controller
+}
+
+func provideRevokeApiKeyController(apiKeyService service.ApiKeyService) controllers.Controller {
+	controller := &authController.RevokeApiKeyController{}
+	controller.Init(apiKeyService)
+	return controller
+}
+
+func provideMFAService(session *gorm.DB, logger zap.Logger) *impl.MFAServiceImpl {
+	serviceImpl := &impl.MFAServiceImpl{}
+	serviceImpl.Init(session, logger)
+	return serviceImpl
+}
+
+func provideLoginMFAForm() *form.LoginMFAForm {
+	return &form.LoginMFAForm{}
+}
+
+func provideMFACodeForm() *form.MFACodeForm {
+	return &form.MFACodeForm{}
+}
+
+func provideLoginMFAController(loginMFAForm *form.LoginMFAForm, authService service.AuthService) controllers.Controller {
+	controller := &authController.LoginMFAController{}
+	controller.Init(loginMFAForm, authService)
+	return controller
+}
+
+func provideMFAStatusController(mfaService service.MFAService) controllers.Controller {
+	controller := &authController.MFAStatusController{}
+	controller.Init(mfaService)
+	return controller
+}
+
+func provideEnrollMFAController(mfaService service.MFAService) controllers.Controller {
+	controller := &authController.EnrollMFAController{}
+	controller.Init(mfaService)
+	return controller
+}
+
+func provideConfirmMFAController(mfaCodeForm *form.MFACodeForm, mfaService service.MFAService) controllers.Controller {
+	controller := &authController.ConfirmMFAController{}
+	controller.Init(mfaCodeForm, mfaService)
+	return controller
+}
+
+func provideDisableMFAController(mfaCodeForm *form.MFACodeForm, mfaService service.MFAService) controllers.Controller {
+	controller := &authController.DisableMFAController{}
+	controller.Init(mfaCodeForm, mfaService)
+	return controller
+}
+
+func provideLoginGuardService(session *gorm.DB, rdb *redis.Client, ctx context.Context, logger zap.Logger) *impl.LoginGuardServiceImpl {
+	serviceImpl := &impl.LoginGuardServiceImpl{}
+	serviceImpl.Init(session, rdb, ctx, logger)
+	return serviceImpl
+}
+
+func provideUnlockLoginForm() *form.UnlockLoginForm {
+	return &form.UnlockLoginForm{}
+}
+
+func provideLockoutListController(loginGuardService service.LoginGuardService) controllers.Controller {
+	controller := &adminController.LockoutListController{}
+	controller.Init(loginGuardService)
+	return controller
+}
+
+func provideUnlockLoginController(unlockLoginForm *form.UnlockLoginForm, loginGuardService service.LoginGuardService) controllers.Controller {
+	controller := &adminController.UnlockLoginController{}
+	controller.Init(unlockLoginForm, loginGuardService)
+	return controller
+}
+
+func provideOIDCService(session *gorm.DB, rdb *redis.Client, ctx context.Context, logger zap.Logger, userService service.UserService, authService service.AuthService) *impl.OIDCServiceImpl {
+	serviceImpl := &impl.OIDCServiceImpl{}
+	serviceImpl.Init(session, rdb, ctx, logger, userService, authService)
+	return serviceImpl
+}
+
+func provideOIDCAuthorizeController(oidcService service.OIDCService) controllers.Controller {
+	controller := &authController.OIDCAuthorizeController{}
+	controller.Init(oidcService)
+	return controller
+}
+
+func provideOIDCCallbackController(oidcService service.OIDCService) controllers.Controller {
+	controller := &authController.OIDCCallbackController{}
+	controller.Init(oidcService)
+	return controller
+}
+
+func provideOIDCLinkController(oidcService service.OIDCService) controllers.Controller {
+	controller := &authController.OIDCLinkController{}
+	controller.Init(oidcService)
+	return controller
+}
+
+func provideIdentityListController(oidcService service.OIDCService) controllers.Controller {
+	controller := &authController.IdentityListController{}
+	controller.Init(oidcService)
+	return controller
+}
+
+func provideUnlinkIdentityController(oidcService service.OIDCService) controllers.Controller {
+	controller := &authController.UnlinkIdentityController{}
+	controller.Init(oidcService)
+	return controller
+}
+
+func provideOAuthService(session *gorm.DB, rdb *redis.Client, ctx context.Context, logger zap.Logger, userService service.UserService) *impl.OAuthServiceImpl {
+	serviceImpl := &impl.OAuthServiceImpl{}
+	serviceImpl.Init(session, rdb, ctx, logger, userService)
+	return serviceImpl
+}
+
+func provideOAuthAuthorizeForm() *form.OAuthAuthorizeForm {
+	return &form.OAuthAuthorizeForm{}
+}
+
+func provideOAuthConsentForm() *form.OAuthConsentForm {
+	return &form.OAuthConsentForm{}
+}
+
+func provideOAuthTokenForm() *form.OAuthTokenForm {
+	return &form.OAuthTokenForm{}
+}
+
+func provideOAuthTokenOperationForm() *form.OAuthTokenOperationForm {
+	return &form.OAuthTokenOperationForm{}
+}
+
+func provideCreateOAuthClientForm() *form.CreateOAuthClientForm {
+	return &form.CreateOAuthClientForm{}
+}
+
+func provideOAuthAuthorizeInfoController(authorizeForm *form.OAuthAuthorizeForm, oauthService service.OAuthService) controllers.Controller {
+	controller := &oauthController.AuthorizeInfoController{}
+	controller.Init(authorizeForm, oauthService)
+	return controller
+}
+
+func provideOAuthAuthorizeController(consentForm *form.OAuthConsentForm, oauthService service.OAuthService) controllers.Controller {
+	controller := &oauthController.AuthorizeController{}
+	controller.Init(consentForm, oauthService)
+	return controller
+}
+
+func provideOAuthTokenController(tokenForm *form.OAuthTokenForm, oauthService service.OAuthService) controllers.Controller {
+	controller := &oauthController.TokenController{}
+	controller.Init(tokenForm, oauthService)
+	return controller
+}
+
+func provideOAuthIntrospectController(tokenOperationForm *form.OAuthTokenOperationForm, oauthService service.OAuthService) controllers.Controller {
+	controller := &oauthController.IntrospectController{}
+	controller.Init(tokenOperationForm, oauthService)
+	return controller
+}
+
+func provideOAuthRevokeController(tokenOperationForm *form.OAuthTokenOperationForm, oauthService service.OAuthService) controllers.Controller {
+	controller := &oauthController.RevokeController{}
+	controller.Init(tokenOperationForm, oauthService)
+	return controller
+}
+
+func provideOAuthClientListController(oauthService service.OAuthService) controllers.Controller {
+	controller := &adminController.OAuthClientListController{}
+	controller.Init(oauthService)
+	return controller
+}
+
+func provideCreateOAuthClientController(createOAuthClientForm *form.CreateOAuthClientForm, oauthService service.OAuthService) controllers.Controller {
+	controller := &adminController.CreateOAuthClientController{}
+	controller.Init(createOAuthClientForm, oauthService)
+	return controller
+}
+
+func provideDeleteOAuthClientController(oauthService service.OAuthService) controllers.Controller {
+	controller := &adminController.DeleteOAuthClientController{}
+	controller.Init(oauthService)
+	return controller
+}
+
+func provideForgotPasswordForm() *form.ForgotPasswordForm {
+	return &form.ForgotPasswordForm{}
+}
+
+func provideForgotPasswordController(forgotPasswordForm *form.ForgotPasswordForm, userService service.UserService, loginGuardService service.LoginGuardService) controllers.Controller {
+	controller := &userController.ForgotPasswordController{}
+	controller.Init(userService, loginGuardService, forgotPasswordForm)
+	return controller
+}
+
+func provideResetPasswordForm() *form.ResetPasswordForm {
+	return &form.ResetPasswordForm{}
+}
+
+func provideResetPasswordController(resetPasswordForm *form.ResetPasswordForm, userService service.UserService) controllers.Controller {
+	controller := &userController.ResetPasswordController{}
+	controller.Init(userService, resetPasswordForm)
+	return controller
+}
+
+func provideSendEmailVerificationController(userService service.UserService) controllers.Controller {
+	controller := &userController.SendEmailVerificationController{}
+	controller.Init(userService)
+	return controller
+}
+
+func provideVerifyEmailForm() *form.VerifyEmailForm {
+	return &form.VerifyEmailForm{}
+}
+
+func provideVerifyEmailController(verifyEmailForm *form.VerifyEmailForm, userService service.UserService) controllers.Controller {
+	controller := &userController.VerifyEmailController{}
+	controller.Init(userService, verifyEmailForm)
+	return controller
+}
+
+func provideLoginChangePasswordForm() *form.LoginChangePasswordForm {
+	return &form.LoginChangePasswordForm{}
+}
+
+func provideLoginChangePasswordController(loginChangePasswordForm *form.LoginChangePasswordForm, authService service.AuthService) controllers.Controller {
+	controller := &authController.LoginChangePasswordController{}
+	controller.Init(loginChangePasswordForm, authService)
+	return controller
+}
+
+func provideAuditService(session *gorm.DB, logger zap.Logger) *impl.AuditServiceImpl {
+	serviceImpl := &impl.AuditServiceImpl{}
+	serviceImpl.Init(session, logger)
+	return serviceImpl
+}
+
+func provideAuditLogListController(auditService service.AuditService) controllers.Controller {
+	controller := &adminController.AuditLogListController{}
+	controller.Init(auditService)
+	return controller
+}
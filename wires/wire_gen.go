@@ -9,130 +9,903 @@ import (
 	"com.github.gin-common/app/service"
 	"com.github.gin-common/app/service/impl"
 	"com.github.gin-common/common/controllers"
+	"github.com/gin-gonic/gin"
 	"github.com/google/wire"
 )
 
 // Injectors from injector.go.bak:
 
-func CreateUserController() controllers.Controller {
+func CreateUserController(ginContext *gin.Context) controllers.Controller {
 	createUserForm := provideCreateUserForm()
+	wiresRequestContext := provideRequestContext(ginContext)
 	wiresGormSessionTimeout := provideGormSessionTimeout()
-	wiresTimeoutGormContext := provideTimeoutGormContext(wiresGormSessionTimeout)
+	wiresTimeoutGormContext := provideTimeoutGormContext(wiresRequestContext, wiresGormSessionTimeout)
 	db := provideTimeoutGormSession(wiresTimeoutGormContext)
-	userServiceImpl := provideUserService(db)
+	client := provideRedisRdb()
+	context := provideRedisContext()
+	logger := provideLogger()
+	mailer := provideMailer()
+	userServiceImpl := provideUserService(db, client, context, logger, mailer)
 	controller := provideCreateUserController(createUserForm, userServiceImpl)
 	return controller
 }
 
-func UpdateUserController() controllers.Controller {
+func UpdateUserController(ginContext *gin.Context) controllers.Controller {
 	updateUserForm := provideUpdateUserForm()
+	wiresRequestContext := provideRequestContext(ginContext)
 	wiresGormSessionTimeout := provideGormSessionTimeout()
-	wiresTimeoutGormContext := provideTimeoutGormContext(wiresGormSessionTimeout)
+	wiresTimeoutGormContext := provideTimeoutGormContext(wiresRequestContext, wiresGormSessionTimeout)
 	db := provideTimeoutGormSession(wiresTimeoutGormContext)
-	userServiceImpl := provideUserService(db)
-	controller := provideEditUserController(updateUserForm, userServiceImpl)
+	client := provideRedisRdb()
+	context := provideRedisContext()
+	logger := provideLogger()
+	mailer := provideMailer()
+	userServiceImpl := provideUserService(db, client, context, logger, mailer)
+	roleServiceImpl := provideRoleService(db, client, context, logger)
+	controller := provideEditUserController(updateUserForm, userServiceImpl, roleServiceImpl)
 	return controller
 }
 
-func DeleteUserController() controllers.Controller {
+func DeleteUserController(ginContext *gin.Context) controllers.Controller {
+	wiresRequestContext := provideRequestContext(ginContext)
 	wiresGormSessionTimeout := provideGormSessionTimeout()
-	wiresTimeoutGormContext := provideTimeoutGormContext(wiresGormSessionTimeout)
+	wiresTimeoutGormContext := provideTimeoutGormContext(wiresRequestContext, wiresGormSessionTimeout)
 	db := provideTimeoutGormSession(wiresTimeoutGormContext)
-	userServiceImpl := provideUserService(db)
+	client := provideRedisRdb()
+	context := provideRedisContext()
+	logger := provideLogger()
+	mailer := provideMailer()
+	userServiceImpl := provideUserService(db, client, context, logger, mailer)
 	controller := provideDeleteUserController(userServiceImpl)
 	return controller
 }
 
-func ActivateUserController() controllers.Controller {
+func ActivateUserController(ginContext *gin.Context) controllers.Controller {
+	wiresRequestContext := provideRequestContext(ginContext)
 	wiresGormSessionTimeout := provideGormSessionTimeout()
-	wiresTimeoutGormContext := provideTimeoutGormContext(wiresGormSessionTimeout)
+	wiresTimeoutGormContext := provideTimeoutGormContext(wiresRequestContext, wiresGormSessionTimeout)
 	db := provideTimeoutGormSession(wiresTimeoutGormContext)
-	userServiceImpl := provideUserService(db)
+	client := provideRedisRdb()
+	context := provideRedisContext()
+	logger := provideLogger()
+	mailer := provideMailer()
+	userServiceImpl := provideUserService(db, client, context, logger, mailer)
 	controller := provideActivateUserController(userServiceImpl)
 	return controller
 }
 
-func DeActivateUserController() controllers.Controller {
+func DeActivateUserController(ginContext *gin.Context) controllers.Controller {
+	wiresRequestContext := provideRequestContext(ginContext)
 	wiresGormSessionTimeout := provideGormSessionTimeout()
-	wiresTimeoutGormContext := provideTimeoutGormContext(wiresGormSessionTimeout)
+	wiresTimeoutGormContext := provideTimeoutGormContext(wiresRequestContext, wiresGormSessionTimeout)
 	db := provideTimeoutGormSession(wiresTimeoutGormContext)
-	userServiceImpl := provideUserService(db)
+	client := provideRedisRdb()
+	context := provideRedisContext()
+	logger := provideLogger()
+	mailer := provideMailer()
+	userServiceImpl := provideUserService(db, client, context, logger, mailer)
 	controller := provideDeActivateUserController(userServiceImpl)
 	return controller
 }
 
-func GetUserInfoController() controllers.Controller {
+func GetUserInfoController(ginContext *gin.Context) controllers.Controller {
+	wiresRequestContext := provideRequestContext(ginContext)
 	wiresGormSessionTimeout := provideGormSessionTimeout()
-	wiresTimeoutGormContext := provideTimeoutGormContext(wiresGormSessionTimeout)
+	wiresTimeoutGormContext := provideTimeoutGormContext(wiresRequestContext, wiresGormSessionTimeout)
 	db := provideTimeoutGormSession(wiresTimeoutGormContext)
-	userServiceImpl := provideUserService(db)
+	client := provideRedisRdb()
+	context := provideRedisContext()
+	logger := provideLogger()
+	mailer := provideMailer()
+	userServiceImpl := provideUserService(db, client, context, logger, mailer)
 	controller := provideGetUserInfoController(userServiceImpl)
 	return controller
 }
 
-func ChangePasswordController() controllers.Controller {
+func UserListController(ginContext *gin.Context) controllers.Controller {
+	userListForm := provideUserListForm()
+	wiresRequestContext := provideRequestContext(ginContext)
+	wiresGormSessionTimeout := provideGormSessionTimeout()
+	wiresTimeoutGormContext := provideTimeoutGormContext(wiresRequestContext, wiresGormSessionTimeout)
+	db := provideTimeoutGormSession(wiresTimeoutGormContext)
+	client := provideRedisRdb()
+	context := provideRedisContext()
+	logger := provideLogger()
+	mailer := provideMailer()
+	userServiceImpl := provideUserService(db, client, context, logger, mailer)
+	controller := provideUserListController(userListForm, userServiceImpl)
+	return controller
+}
+
+func RestoreUserController(ginContext *gin.Context) controllers.Controller {
+	wiresRequestContext := provideRequestContext(ginContext)
+	wiresGormSessionTimeout := provideGormSessionTimeout()
+	wiresTimeoutGormContext := provideTimeoutGormContext(wiresRequestContext, wiresGormSessionTimeout)
+	db := provideTimeoutGormSession(wiresTimeoutGormContext)
+	client := provideRedisRdb()
+	context := provideRedisContext()
+	logger := provideLogger()
+	mailer := provideMailer()
+	userServiceImpl := provideUserService(db, client, context, logger, mailer)
+	controller := provideRestoreUserController(userServiceImpl)
+	return controller
+}
+
+func UserTrashController(ginContext *gin.Context) controllers.Controller {
+	userListForm := provideUserListForm()
+	wiresRequestContext := provideRequestContext(ginContext)
+	wiresGormSessionTimeout := provideGormSessionTimeout()
+	wiresTimeoutGormContext := provideTimeoutGormContext(wiresRequestContext, wiresGormSessionTimeout)
+	db := provideTimeoutGormSession(wiresTimeoutGormContext)
+	client := provideRedisRdb()
+	context := provideRedisContext()
+	logger := provideLogger()
+	mailer := provideMailer()
+	userServiceImpl := provideUserService(db, client, context, logger, mailer)
+	controller := provideUserTrashController(userListForm, userServiceImpl)
+	return controller
+}
+
+// 定时任务等长期运行的组件每次使用时获取新的UserService
+func UserService() service.UserService {
+	wiresRequestContext := provideBackgroundContext()
+	wiresGormSessionTimeout := provideGormSessionTimeout()
+	wiresTimeoutGormContext := provideTimeoutGormContext(wiresRequestContext, wiresGormSessionTimeout)
+	db := provideTimeoutGormSession(wiresTimeoutGormContext)
+	client := provideRedisRdb()
+	context := provideRedisContext()
+	logger := provideLogger()
+	mailer := provideMailer()
+	userServiceImpl := provideUserService(db, client, context, logger, mailer)
+	return userServiceImpl
+}
+
+func ChangePasswordController(ginContext *gin.Context) controllers.Controller {
 	changePassForm := provideChangePassForm()
+	wiresRequestContext := provideRequestContext(ginContext)
 	wiresGormSessionTimeout := provideGormSessionTimeout()
-	wiresTimeoutGormContext := provideTimeoutGormContext(wiresGormSessionTimeout)
+	wiresTimeoutGormContext := provideTimeoutGormContext(wiresRequestContext, wiresGormSessionTimeout)
 	db := provideTimeoutGormSession(wiresTimeoutGormContext)
-	userServiceImpl := provideUserService(db)
-	controller := provideChangePasswordController(changePassForm, userServiceImpl)
+	client := provideRedisRdb()
+	context := provideRedisContext()
+	logger := provideLogger()
+	mailer := provideMailer()
+	userServiceImpl := provideUserService(db, client, context, logger, mailer)
+	roleServiceImpl := provideRoleService(db, client, context, logger)
+	controller := provideChangePasswordController(changePassForm, userServiceImpl, roleServiceImpl)
 	return controller
 }
 
-func AuthMiddleware() controllers.MiddleWare {
+func AuthMiddleware(ginContext *gin.Context) controllers.MiddleWare {
 	context := provideRedisContext()
 	client := provideRedisRdb()
+	wiresRequestContext := provideRequestContext(ginContext)
 	wiresGormSessionTimeout := provideGormSessionTimeout()
-	wiresTimeoutGormContext := provideTimeoutGormContext(wiresGormSessionTimeout)
+	wiresTimeoutGormContext := provideTimeoutGormContext(wiresRequestContext, wiresGormSessionTimeout)
 	db := provideTimeoutGormSession(wiresTimeoutGormContext)
-	userServiceImpl := provideUserService(db)
-	middleWare := provideAuthMiddleware(context, client, userServiceImpl)
+	logger := provideLogger()
+	mailer := provideMailer()
+	userServiceImpl := provideUserService(db, client, context, logger, mailer)
+	apiKeyServiceImpl := provideApiKeyService(db, logger)
+	middleWare := provideAuthMiddleware(context, client, userServiceImpl, apiKeyServiceImpl)
 	return middleWare
 }
 
-func DeactivatedAbortMiddleware() controllers.MiddleWare {
+func DeactivatedAbortMiddleware(ginContext *gin.Context) controllers.MiddleWare {
 	middleWare := provideDeactivatedAbortMiddleware()
 	return middleWare
 }
 
-func LoginController() controllers.Controller {
+func ApiKeyDeniedMiddleware(ginContext *gin.Context) controllers.MiddleWare {
+	middleWare := provideApiKeyDeniedMiddleware()
+	return middleWare
+}
+
+func LoginController(ginContext *gin.Context) controllers.Controller {
 	loginForm := provideLoginForm()
 	context := provideRedisContext()
 	client := provideRedisRdb()
+	wiresRequestContext := provideRequestContext(ginContext)
 	wiresGormSessionTimeout := provideGormSessionTimeout()
-	wiresTimeoutGormContext := provideTimeoutGormContext(wiresGormSessionTimeout)
+	wiresTimeoutGormContext := provideTimeoutGormContext(wiresRequestContext, wiresGormSessionTimeout)
 	db := provideTimeoutGormSession(wiresTimeoutGormContext)
-	userServiceImpl := provideUserService(db)
-	authServiceImpl := provideAuthService(context, client, userServiceImpl)
+	logger := provideLogger()
+	mailer := provideMailer()
+	userServiceImpl := provideUserService(db, client, context, logger, mailer)
+	mfaServiceImpl := provideMFAService(db, logger)
+	loginGuardServiceImpl := provideLoginGuardService(db, client, context, logger)
+	auditServiceImpl := provideAuditService(db, logger)
+	authServiceImpl := provideAuthService(context, client, userServiceImpl, mfaServiceImpl, loginGuardServiceImpl, auditServiceImpl)
 	controller := provideLoginController(loginForm, authServiceImpl)
 	return controller
 }
 
-func LogoutController() controllers.Controller {
+func RefreshTokenController(ginContext *gin.Context) controllers.Controller {
+	refreshTokenForm := provideRefreshTokenForm()
 	context := provideRedisContext()
 	client := provideRedisRdb()
+	wiresRequestContext := provideRequestContext(ginContext)
 	wiresGormSessionTimeout := provideGormSessionTimeout()
-	wiresTimeoutGormContext := provideTimeoutGormContext(wiresGormSessionTimeout)
+	wiresTimeoutGormContext := provideTimeoutGormContext(wiresRequestContext, wiresGormSessionTimeout)
 	db := provideTimeoutGormSession(wiresTimeoutGormContext)
-	userServiceImpl := provideUserService(db)
-	authServiceImpl := provideAuthService(context, client, userServiceImpl)
+	logger := provideLogger()
+	mailer := provideMailer()
+	userServiceImpl := provideUserService(db, client, context, logger, mailer)
+	mfaServiceImpl := provideMFAService(db, logger)
+	loginGuardServiceImpl := provideLoginGuardService(db, client, context, logger)
+	auditServiceImpl := provideAuditService(db, logger)
+	authServiceImpl := provideAuthService(context, client, userServiceImpl, mfaServiceImpl, loginGuardServiceImpl, auditServiceImpl)
+	controller := provideRefreshTokenController(refreshTokenForm, authServiceImpl)
+	return controller
+}
+
+func LogoutController(ginContext *gin.Context) controllers.Controller {
+	context := provideRedisContext()
+	client := provideRedisRdb()
+	wiresRequestContext := provideRequestContext(ginContext)
+	wiresGormSessionTimeout := provideGormSessionTimeout()
+	wiresTimeoutGormContext := provideTimeoutGormContext(wiresRequestContext, wiresGormSessionTimeout)
+	db := provideTimeoutGormSession(wiresTimeoutGormContext)
+	logger := provideLogger()
+	mailer := provideMailer()
+	userServiceImpl := provideUserService(db, client, context, logger, mailer)
+	mfaServiceImpl := provideMFAService(db, logger)
+	loginGuardServiceImpl := provideLoginGuardService(db, client, context, logger)
+	auditServiceImpl := provideAuditService(db, logger)
+	authServiceImpl := provideAuthService(context, client, userServiceImpl, mfaServiceImpl, loginGuardServiceImpl, auditServiceImpl)
 	controller := provideLogoutController(authServiceImpl)
 	return controller
 }
 
-func CurrentUserController() controllers.Controller {
+func SessionListController(ginContext *gin.Context) controllers.Controller {
+	context := provideRedisContext()
+	client := provideRedisRdb()
+	wiresRequestContext := provideRequestContext(ginContext)
+	wiresGormSessionTimeout := provideGormSessionTimeout()
+	wiresTimeoutGormContext := provideTimeoutGormContext(wiresRequestContext, wiresGormSessionTimeout)
+	db := provideTimeoutGormSession(wiresTimeoutGormContext)
+	logger := provideLogger()
+	mailer := provideMailer()
+	userServiceImpl := provideUserService(db, client, context, logger, mailer)
+	mfaServiceImpl := provideMFAService(db, logger)
+	loginGuardServiceImpl := provideLoginGuardService(db, client, context, logger)
+	auditServiceImpl := provideAuditService(db, logger)
+	authServiceImpl := provideAuthService(context, client, userServiceImpl, mfaServiceImpl, loginGuardServiceImpl, auditServiceImpl)
+	controller := provideSessionListController(authServiceImpl)
+	return controller
+}
+
+func RevokeSessionController(ginContext *gin.Context) controllers.Controller {
+	context := provideRedisContext()
+	client := provideRedisRdb()
+	wiresRequestContext := provideRequestContext(ginContext)
+	wiresGormSessionTimeout := provideGormSessionTimeout()
+	wiresTimeoutGormContext := provideTimeoutGormContext(wiresRequestContext, wiresGormSessionTimeout)
+	db := provideTimeoutGormSession(wiresTimeoutGormContext)
+	logger := provideLogger()
+	mailer := provideMailer()
+	userServiceImpl := provideUserService(db, client, context, logger, mailer)
+	mfaServiceImpl := provideMFAService(db, logger)
+	loginGuardServiceImpl := provideLoginGuardService(db, client, context, logger)
+	auditServiceImpl := provideAuditService(db, logger)
+	authServiceImpl := provideAuthService(context, client, userServiceImpl, mfaServiceImpl, loginGuardServiceImpl, auditServiceImpl)
+	controller := provideRevokeSessionController(authServiceImpl)
+	return controller
+}
+
+func LogoutAllController(ginContext *gin.Context) controllers.Controller {
+	context := provideRedisContext()
+	client := provideRedisRdb()
+	wiresRequestContext := provideRequestContext(ginContext)
+	wiresGormSessionTimeout := provideGormSessionTimeout()
+	wiresTimeoutGormContext := provideTimeoutGormContext(wiresRequestContext, wiresGormSessionTimeout)
+	db := provideTimeoutGormSession(wiresTimeoutGormContext)
+	logger := provideLogger()
+	mailer := provideMailer()
+	userServiceImpl := provideUserService(db, client, context, logger, mailer)
+	mfaServiceImpl := provideMFAService(db, logger)
+	loginGuardServiceImpl := provideLoginGuardService(db, client, context, logger)
+	auditServiceImpl := provideAuditService(db, logger)
+	authServiceImpl := provideAuthService(context, client, userServiceImpl, mfaServiceImpl, loginGuardServiceImpl, auditServiceImpl)
+	controller := provideLogoutAllController(authServiceImpl)
+	return controller
+}
+
+func CurrentUserController(ginContext *gin.Context) controllers.Controller {
 	controller := provideCurrentUserController()
 	return controller
 }
 
+func WorkflowListController(ginContext *gin.Context) controllers.Controller {
+	controller := provideWorkflowListController()
+	return controller
+}
+
+func WorkflowGraphController(ginContext *gin.Context) controllers.Controller {
+	controller := provideWorkflowGraphController()
+	return controller
+}
+
+func WorkflowRunsController(ginContext *gin.Context) controllers.Controller {
+	controller := provideWorkflowRunsController()
+	return controller
+}
+
+func PermissionMiddleware(ginContext *gin.Context) controllers.MiddleWare {
+	wiresRequestContext := provideRequestContext(ginContext)
+	wiresGormSessionTimeout := provideGormSessionTimeout()
+	wiresTimeoutGormContext := provideTimeoutGormContext(wiresRequestContext, wiresGormSessionTimeout)
+	db := provideTimeoutGormSession(wiresTimeoutGormContext)
+	client := provideRedisRdb()
+	context := provideRedisContext()
+	logger := provideLogger()
+	roleServiceImpl := provideRoleService(db, client, context, logger)
+	middleWare := providePermissionMiddleware(roleServiceImpl)
+	return middleWare
+}
+
+func RoleListController(ginContext *gin.Context) controllers.Controller {
+	wiresRequestContext := provideRequestContext(ginContext)
+	wiresGormSessionTimeout := provideGormSessionTimeout()
+	wiresTimeoutGormContext := provideTimeoutGormContext(wiresRequestContext, wiresGormSessionTimeout)
+	db := provideTimeoutGormSession(wiresTimeoutGormContext)
+	client := provideRedisRdb()
+	context := provideRedisContext()
+	logger := provideLogger()
+	roleServiceImpl := provideRoleService(db, client, context, logger)
+	controller := provideRoleListController(roleServiceImpl)
+	return controller
+}
+
+func CreateRoleController(ginContext *gin.Context) controllers.Controller {
+	createRoleForm := provideCreateRoleForm()
+	wiresRequestContext := provideRequestContext(ginContext)
+	wiresGormSessionTimeout := provideGormSessionTimeout()
+	wiresTimeoutGormContext := provideTimeoutGormContext(wiresRequestContext, wiresGormSessionTimeout)
+	db := provideTimeoutGormSession(wiresTimeoutGormContext)
+	client := provideRedisRdb()
+	context := provideRedisContext()
+	logger := provideLogger()
+	roleServiceImpl := provideRoleService(db, client, context, logger)
+	controller := provideCreateRoleController(createRoleForm, roleServiceImpl)
+	return controller
+}
+
+func UpdateRoleController(ginContext *gin.Context) controllers.Controller {
+	updateRoleForm := provideUpdateRoleForm()
+	wiresRequestContext := provideRequestContext(ginContext)
+	wiresGormSessionTimeout := provideGormSessionTimeout()
+	wiresTimeoutGormContext := provideTimeoutGormContext(wiresRequestContext, wiresGormSessionTimeout)
+	db := provideTimeoutGormSession(wiresTimeoutGormContext)
+	client := provideRedisRdb()
+	context := provideRedisContext()
+	logger := provideLogger()
+	roleServiceImpl := provideRoleService(db, client, context, logger)
+	controller := provideUpdateRoleController(updateRoleForm, roleServiceImpl)
+	return controller
+}
+
+func DeleteRoleController(ginContext *gin.Context) controllers.Controller {
+	wiresRequestContext := provideRequestContext(ginContext)
+	wiresGormSessionTimeout := provideGormSessionTimeout()
+	wiresTimeoutGormContext := provideTimeoutGormContext(wiresRequestContext, wiresGormSessionTimeout)
+	db := provideTimeoutGormSession(wiresTimeoutGormContext)
+	client := provideRedisRdb()
+	context := provideRedisContext()
+	logger := provideLogger()
+	roleServiceImpl := provideRoleService(db, client, context, logger)
+	controller := provideDeleteRoleController(roleServiceImpl)
+	return controller
+}
+
+func PermissionListController(ginContext *gin.Context) controllers.Controller {
+	wiresRequestContext := provideRequestContext(ginContext)
+	wiresGormSessionTimeout := provideGormSessionTimeout()
+	wiresTimeoutGormContext := provideTimeoutGormContext(wiresRequestContext, wiresGormSessionTimeout)
+	db := provideTimeoutGormSession(wiresTimeoutGormContext)
+	client := provideRedisRdb()
+	context := provideRedisContext()
+	logger := provideLogger()
+	roleServiceImpl := provideRoleService(db, client, context, logger)
+	controller := providePermissionListController(roleServiceImpl)
+	return controller
+}
+
+func UserRolesController(ginContext *gin.Context) controllers.Controller {
+	wiresRequestContext := provideRequestContext(ginContext)
+	wiresGormSessionTimeout := provideGormSessionTimeout()
+	wiresTimeoutGormContext := provideTimeoutGormContext(wiresRequestContext, wiresGormSessionTimeout)
+	db := provideTimeoutGormSession(wiresTimeoutGormContext)
+	client := provideRedisRdb()
+	context := provideRedisContext()
+	logger := provideLogger()
+	roleServiceImpl := provideRoleService(db, client, context, logger)
+	controller := provideUserRolesController(roleServiceImpl)
+	return controller
+}
+
+func AssignUserRolesController(ginContext *gin.Context) controllers.Controller {
+	assignRolesForm := provideAssignRolesForm()
+	wiresRequestContext := provideRequestContext(ginContext)
+	wiresGormSessionTimeout := provideGormSessionTimeout()
+	wiresTimeoutGormContext := provideTimeoutGormContext(wiresRequestContext, wiresGormSessionTimeout)
+	db := provideTimeoutGormSession(wiresTimeoutGormContext)
+	client := provideRedisRdb()
+	context := provideRedisContext()
+	logger := provideLogger()
+	roleServiceImpl := provideRoleService(db, client, context, logger)
+	mailer := provideMailer()
+	userServiceImpl := provideUserService(db, client, context, logger, mailer)
+	controller := provideAssignUserRolesController(assignRolesForm, roleServiceImpl, userServiceImpl)
+	return controller
+}
+
+func UnassignUserRoleController(ginContext *gin.Context) controllers.Controller {
+	wiresRequestContext := provideRequestContext(ginContext)
+	wiresGormSessionTimeout := provideGormSessionTimeout()
+	wiresTimeoutGormContext := provideTimeoutGormContext(wiresRequestContext, wiresGormSessionTimeout)
+	db := provideTimeoutGormSession(wiresTimeoutGormContext)
+	client := provideRedisRdb()
+	context := provideRedisContext()
+	logger := provideLogger()
+	roleServiceImpl := provideRoleService(db, client, context, logger)
+	controller := provideUnassignUserRoleController(roleServiceImpl)
+	return controller
+}
+
+func JWKSController(ginContext *gin.Context) controllers.Controller {
+	controller := provideJWKSController()
+	return controller
+}
+
+func CreateApiKeyController(ginContext *gin.Context) controllers.Controller {
+	createApiKeyForm := provideCreateApiKeyForm()
+	wiresRequestContext := provideRequestContext(ginContext)
+	wiresGormSessionTimeout := provideGormSessionTimeout()
+	wiresTimeoutGormContext := provideTimeoutGormContext(wiresRequestContext, wiresGormSessionTimeout)
+	db := provideTimeoutGormSession(wiresTimeoutGormContext)
+	logger := provideLogger()
+	apiKeyServiceImpl := provideApiKeyService(db, logger)
+	controller := provideCreateApiKeyController(createApiKeyForm, apiKeyServiceImpl)
+	return controller
+}
+
+func ApiKeyListController(ginContext *gin.Context) controllers.Controller {
+	wiresRequestContext := provideRequestContext(ginContext)
+	wiresGormSessionTimeout := provideGormSessionTimeout()
+	wiresTimeoutGormContext := provideTimeoutGormContext(wiresRequestContext, wiresGormSessionTimeout)
+	db := provideTimeoutGormSession(wiresTimeoutGormContext)
+	logger := provideLogger()
+	apiKeyServiceImpl := provideApiKeyService(db, logger)
+	controller := provideApiKeyListController(apiKeyServiceImpl)
+	return controller
+}
+
+func RevokeApiKeyController(ginContext *gin.Context) controllers.Controller {
+	wiresRequestContext := provideRequestContext(ginContext)
+	wiresGormSessionTimeout := provideGormSessionTimeout()
+	wiresTimeoutGormContext := provideTimeoutGormContext(wiresRequestContext, wiresGormSessionTimeout)
+	db := provideTimeoutGormSession(wiresTimeoutGormContext)
+	logger := provideLogger()
+	apiKeyServiceImpl := provideApiKeyService(db, logger)
+	controller := provideRevokeApiKeyController(apiKeyServiceImpl)
+	return controller
+}
+
+func LoginMFAController(ginContext *gin.Context) controllers.Controller {
+	loginMFAForm := provideLoginMFAForm()
+	context := provideRedisContext()
+	client := provideRedisRdb()
+	wiresRequestContext := provideRequestContext(ginContext)
+	wiresGormSessionTimeout := provideGormSessionTimeout()
+	wiresTimeoutGormContext := provideTimeoutGormContext(wiresRequestContext, wiresGormSessionTimeout)
+	db := provideTimeoutGormSession(wiresTimeoutGormContext)
+	logger := provideLogger()
+	mailer := provideMailer()
+	userServiceImpl := provideUserService(db, client, context, logger, mailer)
+	mfaServiceImpl := provideMFAService(db, logger)
+	loginGuardServiceImpl := provideLoginGuardService(db, client, context, logger)
+	auditServiceImpl := provideAuditService(db, logger)
+	authServiceImpl := provideAuthService(context, client, userServiceImpl, mfaServiceImpl, loginGuardServiceImpl, auditServiceImpl)
+	controller := provideLoginMFAController(loginMFAForm, authServiceImpl)
+	return controller
+}
+
+func MFAStatusController(ginContext *gin.Context) controllers.Controller {
+	wiresRequestContext := provideRequestContext(ginContext)
+	wiresGormSessionTimeout := provideGormSessionTimeout()
+	wiresTimeoutGormContext := provideTimeoutGormContext(wiresRequestContext, wiresGormSessionTimeout)
+	db := provideTimeoutGormSession(wiresTimeoutGormContext)
+	logger := provideLogger()
+	mfaServiceImpl := provideMFAService(db, logger)
+	controller := provideMFAStatusController(mfaServiceImpl)
+	return controller
+}
+
+func EnrollMFAController(ginContext *gin.Context) controllers.Controller {
+	wiresRequestContext := provideRequestContext(ginContext)
+	wiresGormSessionTimeout := provideGormSessionTimeout()
+	wiresTimeoutGormContext := provideTimeoutGormContext(wiresRequestContext, wiresGormSessionTimeout)
+	db := provideTimeoutGormSession(wiresTimeoutGormContext)
+	logger := provideLogger()
+	mfaServiceImpl := provideMFAService(db, logger)
+	controller := provideEnrollMFAController(mfaServiceImpl)
+	return controller
+}
+
+func ConfirmMFAController(ginContext *gin.Context) controllers.Controller {
+	mfaCodeForm := provideMFACodeForm()
+	wiresRequestContext := provideRequestContext(ginContext)
+	wiresGormSessionTimeout := provideGormSessionTimeout()
+	wiresTimeoutGormContext := provideTimeoutGormContext(wiresRequestContext, wiresGormSessionTimeout)
+	db := provideTimeoutGormSession(wiresTimeoutGormContext)
+	logger := provideLogger()
+	mfaServiceImpl := provideMFAService(db, logger)
+	controller := provideConfirmMFAController(mfaCodeForm, mfaServiceImpl)
+	return controller
+}
+
+func DisableMFAController(ginContext *gin.Context) controllers.Controller {
+	mfaCodeForm := provideMFACodeForm()
+	wiresRequestContext := provideRequestContext(ginContext)
+	wiresGormSessionTimeout := provideGormSessionTimeout()
+	wiresTimeoutGormContext := provideTimeoutGormContext(wiresRequestContext, wiresGormSessionTimeout)
+	db := provideTimeoutGormSession(wiresTimeoutGormContext)
+	logger := provideLogger()
+	mfaServiceImpl := provideMFAService(db, logger)
+	controller := provideDisableMFAController(mfaCodeForm, mfaServiceImpl)
+	return controller
+}
+
+func LockoutListController(ginContext *gin.Context) controllers.Controller {
+	wiresRequestContext := provideRequestContext(ginContext)
+	wiresGormSessionTimeout := provideGormSessionTimeout()
+	wiresTimeoutGormContext := provideTimeoutGormContext(wiresRequestContext, wiresGormSessionTimeout)
+	db := provideTimeoutGormSession(wiresTimeoutGormContext)
+	client := provideRedisRdb()
+	context := provideRedisContext()
+	logger := provideLogger()
+	loginGuardServiceImpl := provideLoginGuardService(db, client, context, logger)
+	controller := provideLockoutListController(loginGuardServiceImpl)
+	return controller
+}
+
+func UnlockLoginController(ginContext *gin.Context) controllers.Controller {
+	unlockLoginForm := provideUnlockLoginForm()
+	wiresRequestContext := provideRequestContext(ginContext)
+	wiresGormSessionTimeout := provideGormSessionTimeout()
+	wiresTimeoutGormContext := provideTimeoutGormContext(wiresRequestContext, wiresGormSessionTimeout)
+	db := provideTimeoutGormSession(wiresTimeoutGormContext)
+	client := provideRedisRdb()
+	context := provideRedisContext()
+	logger := provideLogger()
+	loginGuardServiceImpl := provideLoginGuardService(db, client, context, logger)
+	controller := provideUnlockLoginController(unlockLoginForm, loginGuardServiceImpl)
+	return controller
+}
+
+func OIDCAuthorizeController(ginContext *gin.Context) controllers.Controller {
+	wiresRequestContext := provideRequestContext(ginContext)
+	wiresGormSessionTimeout := provideGormSessionTimeout()
+	wiresTimeoutGormContext := provideTimeoutGormContext(wiresRequestContext, wiresGormSessionTimeout)
+	db := provideTimeoutGormSession(wiresTimeoutGormContext)
+	client := provideRedisRdb()
+	context := provideRedisContext()
+	logger := provideLogger()
+	mailer := provideMailer()
+	userServiceImpl := provideUserService(db, client, context, logger, mailer)
+	mfaServiceImpl := provideMFAService(db, logger)
+	loginGuardServiceImpl := provideLoginGuardService(db, client, context, logger)
+	auditServiceImpl := provideAuditService(db, logger)
+	authServiceImpl := provideAuthService(context, client, userServiceImpl, mfaServiceImpl, loginGuardServiceImpl, auditServiceImpl)
+	oidcServiceImpl := provideOIDCService(db, client, context, logger, userServiceImpl, authServiceImpl)
+	controller := provideOIDCAuthorizeController(oidcServiceImpl)
+	return controller
+}
+
+func OIDCCallbackController(ginContext *gin.Context) controllers.Controller {
+	wiresRequestContext := provideRequestContext(ginContext)
+	wiresGormSessionTimeout := provideGormSessionTimeout()
+	wiresTimeoutGormContext := provideTimeoutGormContext(wiresRequestContext, wiresGormSessionTimeout)
+	db := provideTimeoutGormSession(wiresTimeoutGormContext)
+	client := provideRedisRdb()
+	context := provideRedisContext()
+	logger := provideLogger()
+	mailer := provideMailer()
+	userServiceImpl := provideUserService(db, client, context, logger, mailer)
+	mfaServiceImpl := provideMFAService(db, logger)
+	loginGuardServiceImpl := provideLoginGuardService(db, client, context, logger)
+	auditServiceImpl := provideAuditService(db, logger)
+	authServiceImpl := provideAuthService(context, client, userServiceImpl, mfaServiceImpl, loginGuardServiceImpl, auditServiceImpl)
+	oidcServiceImpl := provideOIDCService(db, client, context, logger, userServiceImpl, authServiceImpl)
+	controller := provideOIDCCallbackController(oidcServiceImpl)
+	return controller
+}
+
+func OIDCLinkController(ginContext *gin.Context) controllers.Controller {
+	wiresRequestContext := provideRequestContext(ginContext)
+	wiresGormSessionTimeout := provideGormSessionTimeout()
+	wiresTimeoutGormContext := provideTimeoutGormContext(wiresRequestContext, wiresGormSessionTimeout)
+	db := provideTimeoutGormSession(wiresTimeoutGormContext)
+	client := provideRedisRdb()
+	context := provideRedisContext()
+	logger := provideLogger()
+	mailer := provideMailer()
+	userServiceImpl := provideUserService(db, client, context, logger, mailer)
+	mfaServiceImpl := provideMFAService(db, logger)
+	loginGuardServiceImpl := provideLoginGuardService(db, client, context, logger)
+	auditServiceImpl := provideAuditService(db, logger)
+	authServiceImpl := provideAuthService(context, client, userServiceImpl, mfaServiceImpl, loginGuardServiceImpl, auditServiceImpl)
+	oidcServiceImpl := provideOIDCService(db, client, context, logger, userServiceImpl, authServiceImpl)
+	controller := provideOIDCLinkController(oidcServiceImpl)
+	return controller
+}
+
+func IdentityListController(ginContext *gin.Context) controllers.Controller {
+	wiresRequestContext := provideRequestContext(ginContext)
+	wiresGormSessionTimeout := provideGormSessionTimeout()
+	wiresTimeoutGormContext := provideTimeoutGormContext(wiresRequestContext, wiresGormSessionTimeout)
+	db := provideTimeoutGormSession(wiresTimeoutGormContext)
+	client := provideRedisRdb()
+	context := provideRedisContext()
+	logger := provideLogger()
+	mailer := provideMailer()
+	userServiceImpl := provideUserService(db, client, context, logger, mailer)
+	mfaServiceImpl := provideMFAService(db, logger)
+	loginGuardServiceImpl := provideLoginGuardService(db, client, context, logger)
+	auditServiceImpl := provideAuditService(db, logger)
+	authServiceImpl := provideAuthService(context, client, userServiceImpl, mfaServiceImpl, loginGuardServiceImpl, auditServiceImpl)
+	oidcServiceImpl := provideOIDCService(db, client, context, logger, userServiceImpl, authServiceImpl)
+	controller := provideIdentityListController(oidcServiceImpl)
+	return controller
+}
+
+func UnlinkIdentityController(ginContext *gin.Context) controllers.Controller {
+	wiresRequestContext := provideRequestContext(ginContext)
+	wiresGormSessionTimeout := provideGormSessionTimeout()
+	wiresTimeoutGormContext := provideTimeoutGormContext(wiresRequestContext, wiresGormSessionTimeout)
+	db := provideTimeoutGormSession(wiresTimeoutGormContext)
+	client := provideRedisRdb()
+	context := provideRedisContext()
+	logger := provideLogger()
+	mailer := provideMailer()
+	userServiceImpl := provideUserService(db, client, context, logger, mailer)
+	mfaServiceImpl := provideMFAService(db, logger)
+	loginGuardServiceImpl := provideLoginGuardService(db, client, context, logger)
+	auditServiceImpl := provideAuditService(db, logger)
+	authServiceImpl := provideAuthService(context, client, userServiceImpl, mfaServiceImpl, loginGuardServiceImpl, auditServiceImpl)
+	oidcServiceImpl := provideOIDCService(db, client, context, logger, userServiceImpl, authServiceImpl)
+	controller := provideUnlinkIdentityController(oidcServiceImpl)
+	return controller
+}
+
+func OAuthAuthorizeInfoController(ginContext *gin.Context) controllers.Controller {
+	oAuthAuthorizeForm := provideOAuthAuthorizeForm()
+	wiresRequestContext := provideRequestContext(ginContext)
+	wiresGormSessionTimeout := provideGormSessionTimeout()
+	wiresTimeoutGormContext := provideTimeoutGormContext(wiresRequestContext, wiresGormSessionTimeout)
+	db := provideTimeoutGormSession(wiresTimeoutGormContext)
+	client := provideRedisRdb()
+	context := provideRedisContext()
+	logger := provideLogger()
+	mailer := provideMailer()
+	userServiceImpl := provideUserService(db, client, context, logger, mailer)
+	oauthServiceImpl := provideOAuthService(db, client, context, logger, userServiceImpl)
+	controller := provideOAuthAuthorizeInfoController(oAuthAuthorizeForm, oauthServiceImpl)
+	return controller
+}
+
+func OAuthAuthorizeController(ginContext *gin.Context) controllers.Controller {
+	oAuthConsentForm := provideOAuthConsentForm()
+	wiresRequestContext := provideRequestContext(ginContext)
+	wiresGormSessionTimeout := provideGormSessionTimeout()
+	wiresTimeoutGormContext := provideTimeoutGormContext(wiresRequestContext, wiresGormSessionTimeout)
+	db := provideTimeoutGormSession(wiresTimeoutGormContext)
+	client := provideRedisRdb()
+	context := provideRedisContext()
+	logger := provideLogger()
+	mailer := provideMailer()
+	userServiceImpl := provideUserService(db, client, context, logger, mailer)
+	oauthServiceImpl := provideOAuthService(db, client, context, logger, userServiceImpl)
+	controller := provideOAuthAuthorizeController(oAuthConsentForm, oauthServiceImpl)
+	return controller
+}
+
+func OAuthTokenController(ginContext *gin.Context) controllers.Controller {
+	oAuthTokenForm := provideOAuthTokenForm()
+	wiresRequestContext := provideRequestContext(ginContext)
+	wiresGormSessionTimeout := provideGormSessionTimeout()
+	wiresTimeoutGormContext := provideTimeoutGormContext(wiresRequestContext, wiresGormSessionTimeout)
+	db := provideTimeoutGormSession(wiresTimeoutGormContext)
+	client := provideRedisRdb()
+	context := provideRedisContext()
+	logger := provideLogger()
+	mailer := provideMailer()
+	userServiceImpl := provideUserService(db, client, context, logger, mailer)
+	oauthServiceImpl := provideOAuthService(db, client, context, logger, userServiceImpl)
+	controller := provideOAuthTokenController(oAuthTokenForm, oauthServiceImpl)
+	return controller
+}
+
+func OAuthIntrospectController(ginContext *gin.Context) controllers.Controller {
+	oAuthTokenOperationForm := provideOAuthTokenOperationForm()
+	wiresRequestContext := provideRequestContext(ginContext)
+	wiresGormSessionTimeout := provideGormSessionTimeout()
+	wiresTimeoutGormContext := provideTimeoutGormContext(wiresRequestContext, wiresGormSessionTimeout)
+	db := provideTimeoutGormSession(wiresTimeoutGormContext)
+	client := provideRedisRdb()
+	context := provideRedisContext()
+	logger := provideLogger()
+	mailer := provideMailer()
+	userServiceImpl := provideUserService(db, client, context, logger, mailer)
+	oauthServiceImpl := provideOAuthService(db, client, context, logger, userServiceImpl)
+	controller := provideOAuthIntrospectController(oAuthTokenOperationForm, oauthServiceImpl)
+	return controller
+}
+
+func OAuthRevokeController(ginContext *gin.Context) controllers.Controller {
+	oAuthTokenOperationForm := provideOAuthTokenOperationForm()
+	wiresRequestContext := provideRequestContext(ginContext)
+	wiresGormSessionTimeout := provideGormSessionTimeout()
+	wiresTimeoutGormContext := provideTimeoutGormContext(wiresRequestContext, wiresGormSessionTimeout)
+	db := provideTimeoutGormSession(wiresTimeoutGormContext)
+	client := provideRedisRdb()
+	context := provideRedisContext()
+	logger := provideLogger()
+	mailer := provideMailer()
+	userServiceImpl := provideUserService(db, client, context, logger, mailer)
+	oauthServiceImpl := provideOAuthService(db, client, context, logger, userServiceImpl)
+	controller := provideOAuthRevokeController(oAuthTokenOperationForm, oauthServiceImpl)
+	return controller
+}
+
+func OAuthClientListController(ginContext *gin.Context) controllers.Controller {
+	wiresRequestContext := provideRequestContext(ginContext)
+	wiresGormSessionTimeout := provideGormSessionTimeout()
+	wiresTimeoutGormContext := provideTimeoutGormContext(wiresRequestContext, wiresGormSessionTimeout)
+	db := provideTimeoutGormSession(wiresTimeoutGormContext)
+	client := provideRedisRdb()
+	context := provideRedisContext()
+	logger := provideLogger()
+	mailer := provideMailer()
+	userServiceImpl := provideUserService(db, client, context, logger, mailer)
+	oauthServiceImpl := provideOAuthService(db, client, context, logger, userServiceImpl)
+	controller := provideOAuthClientListController(oauthServiceImpl)
+	return controller
+}
+
+func CreateOAuthClientController(ginContext *gin.Context) controllers.Controller {
+	createOAuthClientForm := provideCreateOAuthClientForm()
+	wiresRequestContext := provideRequestContext(ginContext)
+	wiresGormSessionTimeout := provideGormSessionTimeout()
+	wiresTimeoutGormContext := provideTimeoutGormContext(wiresRequestContext, wiresGormSessionTimeout)
+	db := provideTimeoutGormSession(wiresTimeoutGormContext)
+	client := provideRedisRdb()
+	context := provideRedisContext()
+	logger := provideLogger()
+	mailer := provideMailer()
+	userServiceImpl := provideUserService(db, client, context, logger, mailer)
+	oauthServiceImpl := provideOAuthService(db, client, context, logger, userServiceImpl)
+	controller := provideCreateOAuthClientController(createOAuthClientForm, oauthServiceImpl)
+	return controller
+}
+
+func DeleteOAuthClientController(ginContext *gin.Context) controllers.Controller {
+	wiresRequestContext := provideRequestContext(ginContext)
+	wiresGormSessionTimeout := provideGormSessionTimeout()
+	wiresTimeoutGormContext := provideTimeoutGormContext(wiresRequestContext, wiresGormSessionTimeout)
+	db := provideTimeoutGormSession(wiresTimeoutGormContext)
+	client := provideRedisRdb()
+	context := provideRedisContext()
+	logger := provideLogger()
+	mailer := provideMailer()
+	userServiceImpl := provideUserService(db, client, context, logger, mailer)
+	oauthServiceImpl := provideOAuthService(db, client, context, logger, userServiceImpl)
+	controller := provideDeleteOAuthClientController(oauthServiceImpl)
+	return controller
+}
+
+func ForgotPasswordController(ginContext *gin.Context) controllers.Controller {
+	forgotPasswordForm := provideForgotPasswordForm()
+	wiresRequestContext := provideRequestContext(ginContext)
+	wiresGormSessionTimeout := provideGormSessionTimeout()
+	wiresTimeoutGormContext := provideTimeoutGormContext(wiresRequestContext, wiresGormSessionTimeout)
+	db := provideTimeoutGormSession(wiresTimeoutGormContext)
+	client := provideRedisRdb()
+	context := provideRedisContext()
+	logger := provideLogger()
+	mailer := provideMailer()
+	userServiceImpl := provideUserService(db, client, context, logger, mailer)
+	loginGuardServiceImpl := provideLoginGuardService(db, client, context, logger)
+	controller := provideForgotPasswordController(forgotPasswordForm, userServiceImpl, loginGuardServiceImpl)
+	return controller
+}
+
+func ResetPasswordController(ginContext *gin.Context) controllers.Controller {
+	resetPasswordForm := provideResetPasswordForm()
+	wiresRequestContext := provideRequestContext(ginContext)
+	wiresGormSessionTimeout := provideGormSessionTimeout()
+	wiresTimeoutGormContext := provideTimeoutGormContext(wiresRequestContext, wiresGormSessionTimeout)
+	db := provideTimeoutGormSession(wiresTimeoutGormContext)
+	client := provideRedisRdb()
+	context := provideRedisContext()
+	logger := provideLogger()
+	mailer := provideMailer()
+	userServiceImpl := provideUserService(db, client, context, logger, mailer)
+	controller := provideResetPasswordController(resetPasswordForm, userServiceImpl)
+	return controller
+}
+
+func SendEmailVerificationController(ginContext *gin.Context) controllers.Controller {
+	wiresRequestContext := provideRequestContext(ginContext)
+	wiresGormSessionTimeout := provideGormSessionTimeout()
+	wiresTimeoutGormContext := provideTimeoutGormContext(wiresRequestContext, wiresGormSessionTimeout)
+	db := provideTimeoutGormSession(wiresTimeoutGormContext)
+	client := provideRedisRdb()
+	context := provideRedisContext()
+	logger := provideLogger()
+	mailer := provideMailer()
+	userServiceImpl := provideUserService(db, client, context, logger, mailer)
+	controller := provideSendEmailVerificationController(userServiceImpl)
+	return controller
+}
+
+func VerifyEmailController(ginContext *gin.Context) controllers.Controller {
+	verifyEmailForm := provideVerifyEmailForm()
+	wiresRequestContext := provideRequestContext(ginContext)
+	wiresGormSessionTimeout := provideGormSessionTimeout()
+	wiresTimeoutGormContext := provideTimeoutGormContext(wiresRequestContext, wiresGormSessionTimeout)
+	db := provideTimeoutGormSession(wiresTimeoutGormContext)
+	client := provideRedisRdb()
+	context := provideRedisContext()
+	logger := provideLogger()
+	mailer := provideMailer()
+	userServiceImpl := provideUserService(db, client, context, logger, mailer)
+	controller := provideVerifyEmailController(verifyEmailForm, userServiceImpl)
+	return controller
+}
+
+func LoginChangePasswordController(ginContext *gin.Context) controllers.Controller {
+	loginChangePasswordForm := provideLoginChangePasswordForm()
+	context := provideRedisContext()
+	client := provideRedisRdb()
+	wiresRequestContext := provideRequestContext(ginContext)
+	wiresGormSessionTimeout := provideGormSessionTimeout()
+	wiresTimeoutGormContext := provideTimeoutGormContext(wiresRequestContext, wiresGormSessionTimeout)
+	db := provideTimeoutGormSession(wiresTimeoutGormContext)
+	logger := provideLogger()
+	mailer := provideMailer()
+	userServiceImpl := provideUserService(db, client, context, logger, mailer)
+	mfaServiceImpl := provideMFAService(db, logger)
+	loginGuardServiceImpl := provideLoginGuardService(db, client, context, logger)
+	auditServiceImpl := provideAuditService(db, logger)
+	authServiceImpl := provideAuthService(context, client, userServiceImpl, mfaServiceImpl, loginGuardServiceImpl, auditServiceImpl)
+	controller := provideLoginChangePasswordController(loginChangePasswordForm, authServiceImpl)
+	return controller
+}
+
+func AuditLogListController(ginContext *gin.Context) controllers.Controller {
+	wiresRequestContext := provideRequestContext(ginContext)
+	wiresGormSessionTimeout := provideGormSessionTimeout()
+	wiresTimeoutGormContext := provideTimeoutGormContext(wiresRequestContext, wiresGormSessionTimeout)
+	db := provideTimeoutGormSession(wiresTimeoutGormContext)
+	logger := provideLogger()
+	auditServiceImpl := provideAuditService(db, logger)
+	controller := provideAuditLogListController(auditServiceImpl)
+	return controller
+}
+
 // injector.go.bak:
 
 var sessionInjectSet = wire.NewSet(provideGormSessionTimeout, provideTimeoutGormContext, provideTimeoutGormSession)
 
+var requestContextInjectSet = wire.NewSet(provideRequestContext)
+
 var redisInjectSet = wire.NewSet(provideRedisRdb, provideRedisContext)
 
-var userServiceInjectSet = wire.NewSet(sessionInjectSet, provideUserService, wire.Bind(new(service.UserService), new(*impl.UserServiceImpl)))
+var serviceBaseInjectSet = wire.NewSet(sessionInjectSet, redisInjectSet, provideLogger)
+
+var userServiceInjectSet = wire.NewSet(serviceBaseInjectSet, provideMailer, provideUserService, wire.Bind(new(service.UserService), new(*impl.UserServiceImpl)))
 
 var createUserControllerInjectSet = wire.NewSet(provideCreateUserForm, userServiceInjectSet, provideCreateUserController)
 
@@ -146,12 +919,50 @@ var deActivateUserControllerInjectSet = wire.NewSet(provideDeActivateUserControl
 
 var getUserInfoControllerInjectSet = wire.NewSet(provideGetUserInfoController, userServiceInjectSet)
 
+var userListControllerInjectSet = wire.NewSet(provideUserListController, provideUserListForm, userServiceInjectSet)
+
+var restoreUserControllerInjectSet = wire.NewSet(provideRestoreUserController, userServiceInjectSet)
+
+var userTrashControllerInjectSet = wire.NewSet(provideUserTrashController, provideUserListForm, userServiceInjectSet)
+
 var changePasswordControllerInjectSet = wire.NewSet(provideChangePasswordController, provideChangePassForm, userServiceInjectSet)
 
-var authMiddlewareInjectSet = wire.NewSet(provideAuthMiddleware, redisInjectSet, userServiceInjectSet)
+var authMiddlewareInjectSet = wire.NewSet(provideAuthMiddleware, userServiceInjectSet)
 
-var authServiceInjectSet = wire.NewSet(provideAuthService, redisInjectSet, userServiceInjectSet, wire.Bind(new(service.AuthService), new(*impl.AuthServiceImpl)))
+var auditServiceInjectSet = wire.NewSet(serviceBaseInjectSet, provideAuditService, wire.Bind(new(service.AuditService), new(*impl.AuditServiceImpl)))
+
+var authServiceInjectSet = wire.NewSet(provideAuthService, userServiceInjectSet, auditServiceInjectSet, wire.Bind(new(service.AuthService), new(*impl.AuthServiceImpl)))
 
 var loginControllerInjectSet = wire.NewSet(provideLoginController, provideLoginForm, authServiceInjectSet)
 
+var refreshTokenControllerInjectSet = wire.NewSet(provideRefreshTokenController, provideRefreshTokenForm, authServiceInjectSet)
+
 var logoutControllerInjectSet = wire.NewSet(provideLogoutController, authServiceInjectSet)
+
+var sessionListControllerInjectSet = wire.NewSet(provideSessionListController, authServiceInjectSet)
+
+var revokeSessionControllerInjectSet = wire.NewSet(provideRevokeSessionController, authServiceInjectSet)
+
+var logoutAllControllerInjectSet = wire.NewSet(provideLogoutAllController, authServiceInjectSet)
+
+var roleServiceInjectSet = wire.NewSet(serviceBaseInjectSet, provideRoleService, wire.Bind(new(service.RoleService), new(*impl.RoleServiceImpl)))
+
+var permissionMiddlewareInjectSet = wire.NewSet(providePermissionMiddleware, roleServiceInjectSet)
+
+var roleListControllerInjectSet = wire.NewSet(provideRoleListController, roleServiceInjectSet)
+
+var createRoleControllerInjectSet = wire.NewSet(provideCreateRoleController, provideCreateRoleForm, roleServiceInjectSet)
+
+var updateRoleControllerInjectSet = wire.NewSet(provideUpdateRoleController, provideUpdateRoleForm, roleServiceInjectSet)
+
+var deleteRoleControllerInjectSet = wire.NewSet(provideDeleteRoleController, roleServiceInjectSet)
+
+var permissionListControllerInjectSet = wire.NewSet(providePermissionListController, roleServiceInjectSet)
+
+var userRolesControllerInjectSet = wire.NewSet(provideUserRolesController, roleServiceInjectSet)
+
+var assignUserRolesControllerInjectSet = wire.NewSet(provideAssignUserRolesController, provideAssignRolesForm, roleServiceInjectSet, userServiceInjectSet)
+
+var unassignUserRoleControllerInjectSet = wire.NewSet(provideUnassignUserRoleController, roleServiceInjectSet)
+
+var auditLogListControllerInjectSet = wire.NewSet(provideAuditLogListController, auditServiceInjectSet)